@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+
+	"user-service/config"
+	"user-service/internal/adapter/catalogfile"
+	"user-service/internal/adapter/postgres"
+	"user-service/internal/repository"
+	"user-service/internal/usecase/catalog"
+)
+
+func main() {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatalf("failed to read config: %v", err)
+	}
+
+	//выполняем команду, переданную как аргумент (seed)
+	if len(os.Args) < 3 {
+		log.Fatalf("Usage: catalog <command> <file>\nAvailable commands: seed")
+	}
+
+	command, path := os.Args[1], os.Args[2]
+
+	switch command {
+	case "seed":
+		//читаем записи каталога из JSON- или CSV-файла
+		products, err := catalogfile.Load(path)
+		if err != nil {
+			log.Fatalf("failed to load catalog: %v", err)
+		}
+
+		ctx := context.Background()
+		dbpool, err := postgres.NewMaintenance(ctx, *cfg)
+		if err != nil {
+			log.Fatalf("failed to connect to database: %v", err)
+		}
+		defer dbpool.Close()
+
+		catalogUseCase := catalog.New(repository.NewCatalog(dbpool, slog.Default()), slog.Default())
+		for i, product := range products {
+			if _, err := catalogUseCase.UpsertProduct(ctx, product); err != nil {
+				log.Fatalf("failed to save catalog product #%d %q: %v", i+1, product.CanonicalName, err)
+			}
+		}
+		fmt.Printf("catalog seeded successfully: %d products\n", len(products))
+	default:
+		log.Fatalf("unknown command: %s\n", command)
+	}
+}
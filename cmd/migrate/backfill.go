@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"user-service/config"
+	"user-service/internal/adapter/postgres"
+)
+
+// legacyTable - таблица со строками старых целочисленных идентификаторов и запрос,
+// который переносит строки пользователей из user_id_mappings в таблицу данных
+type legacyTable struct {
+	name  string
+	query string
+}
+
+// legacyTables - таблицы, которые миграция 000012_uuid_user_ids заполняет строками
+// с целочисленными идентификаторами. Строки, которые уже есть у пользователя с новым
+// идентификатором, не перезаписываются: запрос сначала вставляет строки, а из legacy_*
+// удаляет только вставленные, поэтому конфликтующие строки остаются в legacy_*.
+var legacyTables = []legacyTable{
+	{
+		name: "legacy_user_products",
+		query: `WITH moved AS (
+				INSERT INTO user_products (id, user_id, product_name, catalog_product_id, category, quantity,
+					unit, expires_at, created_at, updated_at)
+				-- Ссылка на удаленную после миграции запись каталога сбрасывается, как при ON DELETE SET NULL
+				SELECT l.id, m.user_id, l.product_name, p.id, l.category, l.quantity,
+					l.unit, l.expires_at, l.created_at, l.updated_at
+				FROM legacy_user_products l
+				JOIN user_id_mappings m ON m.legacy_id = l.user_id
+				LEFT JOIN products p ON p.id = l.catalog_product_id
+				ON CONFLICT DO NOTHING
+				RETURNING id
+			)
+			DELETE FROM legacy_user_products l USING moved WHERE l.id = moved.id`,
+	},
+	{
+		name: "legacy_user_preferences",
+		query: `WITH moved AS (
+				INSERT INTO user_preferences (user_id, calories, protein_g, fat_g, carbs_g, created_at, updated_at)
+				SELECT m.user_id, l.calories, l.protein_g, l.fat_g, l.carbs_g, l.created_at, l.updated_at
+				FROM legacy_user_preferences l
+				JOIN user_id_mappings m ON m.legacy_id = l.user_id
+				ON CONFLICT DO NOTHING
+				RETURNING user_id
+			)
+			DELETE FROM legacy_user_preferences l USING user_id_mappings m, moved
+			WHERE l.user_id = m.legacy_id AND m.user_id = moved.user_id`,
+	},
+	{
+		name: "legacy_user_preference_entries",
+		query: `WITH moved AS (
+				INSERT INTO user_preference_entries (user_id, kind, value, created_at)
+				SELECT m.user_id, l.kind, l.value, l.created_at
+				FROM legacy_user_preference_entries l
+				JOIN user_id_mappings m ON m.legacy_id = l.user_id
+				ON CONFLICT DO NOTHING
+				RETURNING user_id, kind, value
+			)
+			DELETE FROM legacy_user_preference_entries l USING user_id_mappings m, moved
+			WHERE l.user_id = m.legacy_id AND m.user_id = moved.user_id
+				AND l.kind = moved.kind AND l.value = moved.value`,
+	},
+}
+
+// backfillResult - сколько строк перенесено в таблицу данных, сколько осталось в legacy_*,
+// потому что у пользователя уже есть такие строки, и сколько осталось без соответствия
+type backfillResult struct {
+	table     string
+	moved     int64
+	conflicts int64
+	remaining int64
+}
+
+// backfill - дополняет user_id_mappings соответствиями из CSV-файла path (legacy_id,user_id)
+// и переносит строки пользователей с известным новым идентификатором из таблиц legacy_*.
+// Все выполняется в одной транзакции от имени postgres.maintenance_role, которая видит
+// данные всех пользователей; строки без соответствия и строки, которые уже есть у пользователя
+// с новым идентификатором, остаются в legacy_*, и команду можно повторить.
+func backfill(ctx context.Context, cfg *config.Config, path string) ([]backfillResult, error) {
+	legacyIds, userIds, err := readMappings(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dbpool, err := postgres.NewMaintenance(ctx, *cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer dbpool.Close()
+
+	tx, err := dbpool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO user_id_mappings (legacy_id, user_id)
+		SELECT * FROM unnest($1::int[], $2::uuid[])
+		ON CONFLICT DO NOTHING`
+	if _, err := tx.Exec(ctx, query, legacyIds, userIds); err != nil {
+		return nil, fmt.Errorf("failed to save user id mappings: %w", err)
+	}
+	// Соответствие, которое уже сохранено с другим значением, пропускается при вставке
+	var conflicts int
+	query = `SELECT count(*) FROM unnest($1::int[], $2::uuid[]) AS f(legacy_id, user_id)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM user_id_mappings m WHERE m.legacy_id = f.legacy_id AND m.user_id = f.user_id
+		)`
+	if err := tx.QueryRow(ctx, query, legacyIds, userIds).Scan(&conflicts); err != nil {
+		return nil, fmt.Errorf("failed to check user id mappings: %w", err)
+	}
+	if conflicts > 0 {
+		return nil, fmt.Errorf("%d mappings conflict with user_id_mappings: legacy id or user id is already mapped", conflicts)
+	}
+
+	results := make([]backfillResult, 0, len(legacyTables))
+	for _, table := range legacyTables {
+		tag, err := tx.Exec(ctx, table.query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to backfill %s: %w", table.name, err)
+		}
+		result := backfillResult{table: table.name, moved: tag.RowsAffected()}
+		query := `SELECT count(*) FILTER (WHERE m.legacy_id IS NOT NULL), count(*) FILTER (WHERE m.legacy_id IS NULL)
+			FROM ` + pgx.Identifier{table.name}.Sanitize() + ` l
+			LEFT JOIN user_id_mappings m ON m.legacy_id = l.user_id`
+		if err := tx.QueryRow(ctx, query).Scan(&result.conflicts, &result.remaining); err != nil {
+			return nil, fmt.Errorf("failed to count rows left in %s: %w", table.name, err)
+		}
+		results = append(results, result)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit backfill: %w", err)
+	}
+	return results, nil
+}
+
+// readMappings - читает CSV-файл с колонками legacy_id и user_id, строка заголовка необязательна
+func readMappings(path string) ([]int32, []string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open mapping file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	var legacyIds []int32
+	var userIds []string
+	seen := make(map[int32]bool)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read mapping file: %w", err)
+		}
+		if line == 1 && strings.EqualFold(record[0], "legacy_id") {
+			continue
+		}
+
+		legacyId, err := strconv.ParseInt(record[0], 10, 32)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: invalid legacy id %q", line, record[0])
+		}
+		userId, err := uuid.Parse(record[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: invalid user id %q", line, record[1])
+		}
+		if seen[int32(legacyId)] {
+			return nil, nil, fmt.Errorf("line %d: duplicate legacy id %d", line, legacyId)
+		}
+		seen[int32(legacyId)] = true
+		legacyIds = append(legacyIds, int32(legacyId))
+		userIds = append(userIds, userId.String())
+	}
+	return legacyIds, userIds, nil
+}
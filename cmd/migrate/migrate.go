@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"log"
 	"os"
+	"strings"
 	"user-service/config"
 )
 
@@ -17,9 +20,17 @@ func main() {
 		log.Fatalf("failed to read config: %v", err)
 	}
 
-	//получаем путь к миграциям и строку подключения к БД
+	//получаем путь к миграциям и строку подключения к БД выбранного хранилища
 	migrationsPath := cfg.Migrations.Path
-	dbUrl := cfg.PG.MigrationsUrl()
+	dbUrl := cfg.PG.Admin().MigrationsUrl()
+	if cfg.Storage.Driver == "sqlite" {
+		migrationsPath = cfg.Migrations.SQLitePath
+		dbUrl = cfg.Storage.SQLite.MigrationsUrl()
+	}
+	//путь без схемы считаем локальным каталогом
+	if !strings.Contains(migrationsPath, "://") {
+		migrationsPath = "file://" + migrationsPath
+	}
 
 	//создаем объект миграции
 	m, err := migrate.New(migrationsPath, dbUrl)
@@ -27,9 +38,9 @@ func main() {
 		log.Fatalf("failed to create migration instance: %v", err)
 	}
 
-	//выполняем команду, переданную как аргумент (up, down)
+	//выполняем команду, переданную как аргумент (up, down, backfill)
 	if len(os.Args) < 2 {
-		log.Fatalf("Usage: migrate <command>\nAvailable commands: up, down")
+		log.Fatalf("Usage: migrate <command>\nAvailable commands: up, down, backfill <mapping file>")
 	}
 
 	command := os.Args[1]
@@ -46,6 +57,22 @@ func main() {
 			log.Fatalf("failed to rollback migrations: %v", err)
 		}
 		fmt.Print("migrations rolled back successfully\n")
+	case "backfill":
+		//переносим данные со старыми целочисленными идентификаторами пользователей по CSV-файлу legacy_id,user_id
+		if cfg.Storage.Driver != "postgres" {
+			log.Fatalf("backfill is only needed for postgres storage")
+		}
+		if len(os.Args) < 3 {
+			log.Fatalf("Usage: migrate backfill <mapping file>")
+		}
+		results, err := backfill(context.Background(), cfg, os.Args[2])
+		if err != nil {
+			log.Fatalf("failed to backfill legacy user ids: %v", err)
+		}
+		for _, result := range results {
+			fmt.Printf("%s: %d rows moved, %d rows kept because the user already has them, %d rows left without mapping\n",
+				result.table, result.moved, result.conflicts, result.remaining)
+		}
 	default:
 		log.Fatalf("unknown command: %s\n", command)
 	}
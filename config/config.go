@@ -11,40 +11,222 @@ type (
 	Config struct {
 		App        AppConfig        `yaml:"app"`
 		GRPC       GRPCConfig       `yaml:"grpc"`
+		Gateway    GatewayConfig    `yaml:"gateway"`
 		Log        LogConfig        `yaml:"logger"`
 		Token      TokenConfig      `yaml:"token"`
+		Storage    StorageConfig    `yaml:"storage"`
 		PG         PGConfig         `yaml:"postgres"`
 		Migrations MigrationsConfig `yaml:"migrations"`
+		Admin      AdminConfig      `yaml:"admin"`
+		Internal   InternalConfig   `yaml:"internal"`
+		Health     HealthConfig     `yaml:"health"`
+		RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+		Tracing    TracingConfig    `yaml:"tracing"`
+		Cache      CacheConfig      `yaml:"cache"`
+		Outbox     OutboxConfig     `yaml:"outbox"`
+		Dev        DevConfig        `yaml:"dev"`
 	}
 	AppConfig struct {
 		Name    string `yaml:"name"`
 		Version string `yaml:"version"`
 	}
 	GRPCConfig struct {
-		Port    int `yaml:"port"`
+		Port int `yaml:"port"`
+		// Timeout - дедлайн обработки запроса по умолчанию в секундах, 0 его отключает
 		Timeout int `yaml:"timeout"`
+		// DrainTimeout - сколько ждать завершения текущих запросов при остановке,
+		// после этого они обрываются
+		DrainTimeout time.Duration `yaml:"drain_timeout"`
+	}
+
+	GatewayConfig struct {
+		// Port - порт REST/JSON шлюза к UserService, 0 его выключает
+		Port int `yaml:"port"`
 	}
 
 	LogConfig struct {
-		Level string `yaml:"level"`
+		// Level - уровень по умолчанию: debug, info, warn или error
+		Level string `yaml:"level" env-default:"info"`
+		// Format - формат вывода: json или text
+		Format string `yaml:"format" env-default:"json"`
+		// Packages - уровни отдельных компонентов, например repository: debug
+		Packages map[string]string `yaml:"packages"`
+		// PayloadSampleRate - доля запросов, тела которых попадают в debug-лог (0..1)
+		PayloadSampleRate float64 `yaml:"payload_sample_rate" env-default:"0.1"`
 	}
 
 	TokenConfig struct {
+		// Secret - общий секрет для HMAC-алгоритмов (HS256 и т.д.)
 		Secret string `yaml:"secret"`
+		// Algorithms - разрешенные алгоритмы подписи, по умолчанию только HS256
+		Algorithms []string `yaml:"algorithms"`
+		// Issuer - ожидаемый iss, пустое значение не проверяется
+		Issuer string `yaml:"issuer"`
+		// Audience - ожидаемый aud, пустое значение не проверяется
+		Audience string `yaml:"audience"`
+		// Leeway - допустимое расхождение часов при проверке exp, nbf и iat
+		Leeway time.Duration `yaml:"leeway"`
+		JWKS   JWKSConfig    `yaml:"jwks"`
+	}
+
+	JWKSConfig struct {
+		// URL - адрес JWKS-документа сервиса авторизации
+		URL string `yaml:"url"`
+		// File - путь к локальному JWKS-документу, используется вместо URL
+		File string `yaml:"file"`
+		// RefreshInterval - период обновления ключей, 0 отключает обновление
+		RefreshInterval time.Duration `yaml:"refresh_interval"`
+	}
+
+	StorageConfig struct {
+		// Driver - хранилище данных: postgres или sqlite
+		Driver string       `yaml:"driver" env-default:"postgres"`
+		SQLite SQLiteConfig `yaml:"sqlite"`
+	}
+
+	SQLiteConfig struct {
+		// Path - путь к файлу базы данных, файл создается при первом открытии
+		Path string `yaml:"path"`
 	}
 
 	PGConfig struct {
-		Port        int           `yaml:"port"`
+		Port int `yaml:"port"`
+		// User - роль сервиса; она не должна быть суперпользователем или иметь BYPASSRLS,
+		// иначе политики RLS не действуют, и сервис отказывается запускаться
 		User        string        `yaml:"pg_user"`
 		Password    string        `yaml:"pg_password"`
 		Host        string        `yaml:"pg_host"`
 		Name        string        `yaml:"pg_db_name"`
 		MaxConns    int32         `yaml:"db_max_connections"`
 		ConnTimeout time.Duration `yaml:"db_connection_timeout"`
+		// AdminUser - владелец схемы, от имени которого применяются миграции и работают
+		// административные инструменты; пустое значение - использовать pg_user
+		AdminUser     string `yaml:"pg_admin_user"`
+		AdminPassword string `yaml:"pg_admin_password"`
+		// StatementTimeout - statement_timeout сессии: запрос дольше этого времени отменяется сервером, 0 - без ограничения
+		StatementTimeout time.Duration `yaml:"db_statement_timeout"`
+		// MaintenanceRole - роль с BYPASSRLS, на которую административные инструменты (cmd/catalog, migrate backfill)
+		// переключаются через SET ROLE; роль выдается pg_admin_user, пустое значение - работать без SET ROLE
+		MaintenanceRole string `yaml:"maintenance_role"`
 	}
 
 	MigrationsConfig struct {
 		Path string `yaml:"path"`
+		// SQLitePath - миграции для storage.driver: sqlite
+		SQLitePath string `yaml:"sqlite_path"`
+	}
+
+	AdminConfig struct {
+		// APIKey - ключ административных RPC, пустое значение их выключает
+		APIKey string `yaml:"api_key"`
+		// Port - порт служебного HTTP-сервера (/livez, /readyz), 0 его выключает
+		Port int `yaml:"port"`
+	}
+
+	InternalConfig struct {
+		// APIKey - ключ внутренних RPC для других сервисов (отзыв токенов), пустое значение их выключает
+		APIKey string `yaml:"api_key"`
+	}
+
+	HealthConfig struct {
+		// Interval - период проверки доступности Postgres
+		Interval time.Duration `yaml:"interval"`
+		// Timeout - таймаут одной проверки
+		Timeout time.Duration `yaml:"timeout"`
+	}
+
+	RateLimitConfig struct {
+		Enabled bool `yaml:"enabled"`
+		// Backend - где хранятся корзины: memory (в каждой реплике свои) или postgres (общие)
+		Backend string `yaml:"backend"`
+		// Default - лимиты методов, для которых нет записи в Methods
+		Default RateLimitRule `yaml:"default"`
+		// Methods - лимиты по полному имени метода (/user.UserService/AddUserProduct),
+		// незаданные поля берутся из Default
+		Methods map[string]RateLimitRule `yaml:"methods"`
+	}
+
+	RateLimitRule struct {
+		// User - лимит на аутентифицированного пользователя
+		User RateLimit `yaml:"user"`
+		// Peer - лимит на адрес клиента
+		Peer RateLimit `yaml:"peer"`
+	}
+
+	RateLimit struct {
+		// Rate - запросов в секунду, 0 - без ограничения
+		Rate float64 `yaml:"rate"`
+		// Burst - сколько запросов можно сделать подряд
+		Burst int `yaml:"burst"`
+	}
+
+	CacheConfig struct {
+		Enabled bool `yaml:"enabled"`
+		// Backend - где хранится кэш: memory (LRU в каждой реплике) или redis (общий)
+		Backend string `yaml:"backend"`
+		// Size - максимальное количество значений в кэше memory
+		Size int `yaml:"size" env-default:"10000"`
+		// ProductsTTL - сколько живут страницы продуктов, если их не сбросило изменение
+		ProductsTTL time.Duration `yaml:"products_ttl" env-default:"30s"`
+		// PreferencesTTL - сколько живут наборы предпочтений, если их не сбросило изменение
+		PreferencesTTL time.Duration `yaml:"preferences_ttl" env-default:"5m"`
+		Redis          RedisConfig   `yaml:"redis"`
+	}
+
+	RedisConfig struct {
+		// Addr - адрес сервера с протоколом Redis host:port
+		Addr     string `yaml:"addr"`
+		Password string `yaml:"password"`
+		DB       int    `yaml:"db"`
+	}
+
+	OutboxConfig struct {
+		// Enabled - публиковать ли события из outbox; выключенный relay не мешает
+		// записывать события, они копятся до его включения
+		Enabled bool `yaml:"enabled"`
+		// Broker - куда публикуются события: kafka или nats; stdout и file - только в режиме --dev
+		Broker string `yaml:"broker"`
+		// PollInterval - как часто relay проверяет outbox
+		PollInterval time.Duration `yaml:"poll_interval" env-default:"1s"`
+		// BatchSize - сколько событий публикуется за раз
+		BatchSize int `yaml:"batch_size" env-default:"100"`
+		// File - файл для брокера file, события дописываются в конец
+		File  string      `yaml:"file"`
+		Kafka KafkaConfig `yaml:"kafka"`
+		NATS  NATSConfig  `yaml:"nats"`
+	}
+
+	KafkaConfig struct {
+		// Brokers - адреса брокеров host:port
+		Brokers []string `yaml:"brokers"`
+		Topic   string   `yaml:"topic"`
+	}
+
+	NATSConfig struct {
+		URL string `yaml:"url"`
+		// Subject - префикс субъектов, событие публикуется в <subject>.<тип события>
+		Subject string `yaml:"subject"`
+	}
+
+	DevConfig struct {
+		// SnapshotFile - JSON-файл, в котором хранилище режима разработки (--dev)
+		// сохраняет данные между перезапусками; пустое значение - данные не сохраняются
+		SnapshotFile string `yaml:"snapshot_file"`
+	}
+
+	TracingConfig struct {
+		Enabled bool `yaml:"enabled"`
+		// Exporter - куда отправляются спаны: otlp (коллектор по gRPC), stdout или file
+		Exporter string `yaml:"exporter"`
+		// Endpoint - адрес OTLP-коллектора host:port
+		Endpoint string `yaml:"endpoint"`
+		// Insecure - подключаться к коллектору без TLS
+		Insecure bool `yaml:"insecure"`
+		// File - файл для экспортера file, спаны дописываются в конец
+		File string `yaml:"file"`
+		// SampleRatio - доля трассируемых запросов без родительского спана (0..1);
+		// запросы с родительским спаном следуют его решению
+		SampleRatio float64 `yaml:"sample_ratio" env-default:"1"`
 	}
 )
 
@@ -53,11 +235,29 @@ func (pc PGConfig) Url() string {
 		pc.User, pc.Password, pc.Host, pc.Port, pc.Name)
 }
 
+// Admin - настройки подключения от имени pg_admin_user
+func (pc PGConfig) Admin() PGConfig {
+	if pc.AdminUser != "" {
+		pc.User, pc.Password = pc.AdminUser, pc.AdminPassword
+	}
+	return pc
+}
+
 func (pc PGConfig) MigrationsUrl() string {
 	return fmt.Sprintf("pgx5://%s:%s@%s:%d/%s?sslmode=disable",
 		pc.User, pc.Password, pc.Host, pc.Port, pc.Name)
 }
 
+func (sc SQLiteConfig) Url() string {
+	// Время пишется в формате SQLite, чтобы значения в UTC сравнивались как строки
+	return fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_time_format=sqlite",
+		sc.Path)
+}
+
+func (sc SQLiteConfig) MigrationsUrl() string {
+	return fmt.Sprintf("sqlite://%s", sc.Path)
+}
+
 func NewConfig() (*Config, error) {
 	cfg := &Config{}
 	err := cleanenv.ReadConfig("./config/config.yaml", cfg)
@@ -0,0 +1,9 @@
+// Package openapi содержит OpenAPI-документ REST/JSON API, сгенерированный из proto/user.proto.
+package openapi
+
+import _ "embed"
+
+// Spec - OpenAPI 2.0 документ в формате JSON
+//
+//go:embed user.swagger.json
+var Spec []byte
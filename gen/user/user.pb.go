@@ -7,8 +7,11 @@
 package user
 
 import (
+	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,12 +24,123 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type PreferenceKind int32
+
+const (
+	PreferenceKind_PREFERENCE_KIND_UNSPECIFIED         PreferenceKind = 0
+	PreferenceKind_PREFERENCE_KIND_DIET                PreferenceKind = 1
+	PreferenceKind_PREFERENCE_KIND_ALLERGEN            PreferenceKind = 2
+	PreferenceKind_PREFERENCE_KIND_DISLIKED_INGREDIENT PreferenceKind = 3
+)
+
+// Enum value maps for PreferenceKind.
+var (
+	PreferenceKind_name = map[int32]string{
+		0: "PREFERENCE_KIND_UNSPECIFIED",
+		1: "PREFERENCE_KIND_DIET",
+		2: "PREFERENCE_KIND_ALLERGEN",
+		3: "PREFERENCE_KIND_DISLIKED_INGREDIENT",
+	}
+	PreferenceKind_value = map[string]int32{
+		"PREFERENCE_KIND_UNSPECIFIED":         0,
+		"PREFERENCE_KIND_DIET":                1,
+		"PREFERENCE_KIND_ALLERGEN":            2,
+		"PREFERENCE_KIND_DISLIKED_INGREDIENT": 3,
+	}
+)
+
+func (x PreferenceKind) Enum() *PreferenceKind {
+	p := new(PreferenceKind)
+	*p = x
+	return p
+}
+
+func (x PreferenceKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PreferenceKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_enumTypes[0].Descriptor()
+}
+
+func (PreferenceKind) Type() protoreflect.EnumType {
+	return &file_user_proto_enumTypes[0]
+}
+
+func (x PreferenceKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PreferenceKind.Descriptor instead.
+func (PreferenceKind) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{0}
+}
+
+type ProductSortField int32
+
+const (
+	// По времени добавления
+	ProductSortField_PRODUCT_SORT_FIELD_UNSPECIFIED ProductSortField = 0
+	ProductSortField_PRODUCT_SORT_FIELD_NAME        ProductSortField = 1
+	ProductSortField_PRODUCT_SORT_FIELD_CREATED_AT  ProductSortField = 2
+	// Продукты без срока годности идут последними
+	ProductSortField_PRODUCT_SORT_FIELD_EXPIRES_AT ProductSortField = 3
+)
+
+// Enum value maps for ProductSortField.
+var (
+	ProductSortField_name = map[int32]string{
+		0: "PRODUCT_SORT_FIELD_UNSPECIFIED",
+		1: "PRODUCT_SORT_FIELD_NAME",
+		2: "PRODUCT_SORT_FIELD_CREATED_AT",
+		3: "PRODUCT_SORT_FIELD_EXPIRES_AT",
+	}
+	ProductSortField_value = map[string]int32{
+		"PRODUCT_SORT_FIELD_UNSPECIFIED": 0,
+		"PRODUCT_SORT_FIELD_NAME":        1,
+		"PRODUCT_SORT_FIELD_CREATED_AT":  2,
+		"PRODUCT_SORT_FIELD_EXPIRES_AT":  3,
+	}
+)
+
+func (x ProductSortField) Enum() *ProductSortField {
+	p := new(ProductSortField)
+	*p = x
+	return p
+}
+
+func (x ProductSortField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProductSortField) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_enumTypes[1].Descriptor()
+}
+
+func (ProductSortField) Type() protoreflect.EnumType {
+	return &file_user_proto_enumTypes[1]
+}
+
+func (x ProductSortField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProductSortField.Descriptor instead.
+func (ProductSortField) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{1}
+}
+
 type UpdatePreferenceRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	AccessToken    string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
-	PreferenceName string                 `protobuf:"bytes,2,opt,name=preference_name,json=preferenceName,proto3" json:"preference_name,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	// Устарело: заменяет набор одним типом питания, используется если не задан preferences
+	//
+	// Deprecated: Marked as deprecated in user.proto.
+	PreferenceName string `protobuf:"bytes,2,opt,name=preference_name,json=preferenceName,proto3" json:"preference_name,omitempty"`
+	// Новый набор предпочтений, заменяет текущий целиком
+	Preferences   *Preferences `protobuf:"bytes,3,opt,name=preferences,proto3" json:"preferences,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdatePreferenceRequest) Reset() {
@@ -36,14 +150,1517 @@ func (x *UpdatePreferenceRequest) Reset() {
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdatePreferenceRequest) String() string {
+func (x *UpdatePreferenceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePreferenceRequest) ProtoMessage() {}
+
+func (x *UpdatePreferenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePreferenceRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePreferenceRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UpdatePreferenceRequest) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in user.proto.
+func (x *UpdatePreferenceRequest) GetPreferenceName() string {
+	if x != nil {
+		return x.PreferenceName
+	}
+	return ""
+}
+
+func (x *UpdatePreferenceRequest) GetPreferences() *Preferences {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+// Суточные цели, 0 означает "не задано"
+type NutritionTargets struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Calories      int32                  `protobuf:"varint,1,opt,name=calories,proto3" json:"calories,omitempty"`
+	ProteinG      float64                `protobuf:"fixed64,2,opt,name=protein_g,json=proteinG,proto3" json:"protein_g,omitempty"`
+	FatG          float64                `protobuf:"fixed64,3,opt,name=fat_g,json=fatG,proto3" json:"fat_g,omitempty"`
+	CarbsG        float64                `protobuf:"fixed64,4,opt,name=carbs_g,json=carbsG,proto3" json:"carbs_g,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NutritionTargets) Reset() {
+	*x = NutritionTargets{}
+	mi := &file_user_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NutritionTargets) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NutritionTargets) ProtoMessage() {}
+
+func (x *NutritionTargets) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NutritionTargets.ProtoReflect.Descriptor instead.
+func (*NutritionTargets) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *NutritionTargets) GetCalories() int32 {
+	if x != nil {
+		return x.Calories
+	}
+	return 0
+}
+
+func (x *NutritionTargets) GetProteinG() float64 {
+	if x != nil {
+		return x.ProteinG
+	}
+	return 0
+}
+
+func (x *NutritionTargets) GetFatG() float64 {
+	if x != nil {
+		return x.FatG
+	}
+	return 0
+}
+
+func (x *NutritionTargets) GetCarbsG() float64 {
+	if x != nil {
+		return x.CarbsG
+	}
+	return 0
+}
+
+type Preferences struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Типы питания: vegetarian, lactose-free и т.д.
+	Diets               []string               `protobuf:"bytes,1,rep,name=diets,proto3" json:"diets,omitempty"`
+	Allergens           []string               `protobuf:"bytes,2,rep,name=allergens,proto3" json:"allergens,omitempty"`
+	DislikedIngredients []string               `protobuf:"bytes,3,rep,name=disliked_ingredients,json=dislikedIngredients,proto3" json:"disliked_ingredients,omitempty"`
+	Targets             *NutritionTargets      `protobuf:"bytes,4,opt,name=targets,proto3" json:"targets,omitempty"`
+	UpdatedAt           *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Preferences) Reset() {
+	*x = Preferences{}
+	mi := &file_user_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Preferences) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Preferences) ProtoMessage() {}
+
+func (x *Preferences) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Preferences.ProtoReflect.Descriptor instead.
+func (*Preferences) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Preferences) GetDiets() []string {
+	if x != nil {
+		return x.Diets
+	}
+	return nil
+}
+
+func (x *Preferences) GetAllergens() []string {
+	if x != nil {
+		return x.Allergens
+	}
+	return nil
+}
+
+func (x *Preferences) GetDislikedIngredients() []string {
+	if x != nil {
+		return x.DislikedIngredients
+	}
+	return nil
+}
+
+func (x *Preferences) GetTargets() *NutritionTargets {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *Preferences) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type PreferenceEntryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	Kind          PreferenceKind         `protobuf:"varint,2,opt,name=kind,proto3,enum=user.PreferenceKind" json:"kind,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreferenceEntryRequest) Reset() {
+	*x = PreferenceEntryRequest{}
+	mi := &file_user_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreferenceEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreferenceEntryRequest) ProtoMessage() {}
+
+func (x *PreferenceEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreferenceEntryRequest.ProtoReflect.Descriptor instead.
+func (*PreferenceEntryRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PreferenceEntryRequest) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *PreferenceEntryRequest) GetKind() PreferenceKind {
+	if x != nil {
+		return x.Kind
+	}
+	return PreferenceKind_PREFERENCE_KIND_UNSPECIFIED
+}
+
+func (x *PreferenceEntryRequest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type PreferenceEntryResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// Набор предпочтений после изменения
+	Preferences   *Preferences `protobuf:"bytes,2,opt,name=preferences,proto3" json:"preferences,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreferenceEntryResponse) Reset() {
+	*x = PreferenceEntryResponse{}
+	mi := &file_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreferenceEntryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreferenceEntryResponse) ProtoMessage() {}
+
+func (x *PreferenceEntryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreferenceEntryResponse.ProtoReflect.Descriptor instead.
+func (*PreferenceEntryResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PreferenceEntryResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PreferenceEntryResponse) GetPreferences() *Preferences {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+type RemoveProductRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	// Название продукта, используется если не задан product_id
+	ProductName   string `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	ProductId     string `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveProductRequest) Reset() {
+	*x = RemoveProductRequest{}
+	mi := &file_user_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveProductRequest) ProtoMessage() {}
+
+func (x *RemoveProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveProductRequest.ProtoReflect.Descriptor instead.
+func (*RemoveProductRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RemoveProductRequest) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *RemoveProductRequest) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *RemoveProductRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type AddProductRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	ProductName string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	// Количество в единицах unit, 0 означает 1
+	Quantity      float64                `protobuf:"fixed64,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Unit          string                 `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Category      string                 `protobuf:"bytes,6,opt,name=category,proto3" json:"category,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddProductRequest) Reset() {
+	*x = AddProductRequest{}
+	mi := &file_user_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProductRequest) ProtoMessage() {}
+
+func (x *AddProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProductRequest.ProtoReflect.Descriptor instead.
+func (*AddProductRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AddProductRequest) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *AddProductRequest) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *AddProductRequest) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *AddProductRequest) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *AddProductRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *AddProductRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+type Product struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity  float64                `protobuf:"fixed64,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Unit      string                 `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Category  string                 `protobuf:"bytes,8,opt,name=category,proto3" json:"category,omitempty"`
+	// Идентификатор записи каталога, пустой для пользовательских продуктов
+	CatalogProductId string `protobuf:"bytes,9,opt,name=catalog_product_id,json=catalogProductId,proto3" json:"catalog_product_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_user_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Product) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Product) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Product) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Product) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Product) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Product) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Product) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Product) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Product) GetCatalogProductId() string {
+	if x != nil {
+		return x.CatalogProductId
+	}
+	return ""
+}
+
+type RemovePreferenceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePreferenceRequest) Reset() {
+	*x = RemovePreferenceRequest{}
+	mi := &file_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePreferenceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePreferenceRequest) ProtoMessage() {}
+
+func (x *RemovePreferenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePreferenceRequest.ProtoReflect.Descriptor instead.
+func (*RemovePreferenceRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RemovePreferenceRequest) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+type UserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserRequest) Reset() {
+	*x = UserRequest{}
+	mi := &file_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserRequest) ProtoMessage() {}
+
+func (x *UserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserRequest.ProtoReflect.Descriptor instead.
+func (*UserRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UserRequest) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+type ProductFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Префикс названия без учета регистра
+	NamePrefix     string                 `protobuf:"bytes,1,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	ExpiringBefore *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expiring_before,json=expiringBefore,proto3" json:"expiring_before,omitempty"`
+	Category       string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ProductFilter) Reset() {
+	*x = ProductFilter{}
+	mi := &file_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductFilter) ProtoMessage() {}
+
+func (x *ProductFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductFilter.ProtoReflect.Descriptor instead.
+func (*ProductFilter) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ProductFilter) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
+func (x *ProductFilter) GetExpiringBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiringBefore
+	}
+	return nil
+}
+
+func (x *ProductFilter) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+type GetProductsRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	// Размер страницы, 0 - значение по умолчанию (50), максимум 500
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token из предыдущего ответа; фильтры и сортировка должны совпадать
+	PageToken     string           `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Filter        *ProductFilter   `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
+	SortBy        ProductSortField `protobuf:"varint,5,opt,name=sort_by,json=sortBy,proto3,enum=user.ProductSortField" json:"sort_by,omitempty"`
+	Descending    bool             `protobuf:"varint,6,opt,name=descending,proto3" json:"descending,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsRequest) Reset() {
+	*x = GetProductsRequest{}
+	mi := &file_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsRequest) ProtoMessage() {}
+
+func (x *GetProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsRequest.ProtoReflect.Descriptor instead.
+func (*GetProductsRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetProductsRequest) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *GetProductsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetProductsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetProductsRequest) GetFilter() *ProductFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *GetProductsRequest) GetSortBy() ProductSortField {
+	if x != nil {
+		return x.SortBy
+	}
+	return ProductSortField_PRODUCT_SORT_FIELD_UNSPECIFIED
+}
+
+func (x *GetProductsRequest) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+type GetProductsResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Products []*Product             `protobuf:"bytes,2,rep,name=products,proto3" json:"products,omitempty"`
+	// Пустой, если страница последняя
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsResponse) Reset() {
+	*x = GetProductsResponse{}
+	mi := &file_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsResponse) ProtoMessage() {}
+
+func (x *GetProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsResponse.ProtoReflect.Descriptor instead.
+func (*GetProductsResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetProductsResponse) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *GetProductsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetPreferenceResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Устарело: первый тип питания из preferences.diets
+	//
+	// Deprecated: Marked as deprecated in user.proto.
+	PreferenceName string       `protobuf:"bytes,1,opt,name=preference_name,json=preferenceName,proto3" json:"preference_name,omitempty"`
+	Preferences    *Preferences `protobuf:"bytes,2,opt,name=preferences,proto3" json:"preferences,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetPreferenceResponse) Reset() {
+	*x = GetPreferenceResponse{}
+	mi := &file_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPreferenceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPreferenceResponse) ProtoMessage() {}
+
+func (x *GetPreferenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPreferenceResponse.ProtoReflect.Descriptor instead.
+func (*GetPreferenceResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{13}
+}
+
+// Deprecated: Marked as deprecated in user.proto.
+func (x *GetPreferenceResponse) GetPreferenceName() string {
+	if x != nil {
+		return x.PreferenceName
+	}
+	return ""
+}
+
+func (x *GetPreferenceResponse) GetPreferences() *Preferences {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+type AddProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Product       *Product               `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddProductResponse) Reset() {
+	*x = AddProductResponse{}
+	mi := &file_user_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProductResponse) ProtoMessage() {}
+
+func (x *AddProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProductResponse.ProtoReflect.Descriptor instead.
+func (*AddProductResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AddProductResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AddProductResponse) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type RemoveProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveProductResponse) Reset() {
+	*x = RemoveProductResponse{}
+	mi := &file_user_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveProductResponse) ProtoMessage() {}
+
+func (x *RemoveProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveProductResponse.ProtoReflect.Descriptor instead.
+func (*RemoveProductResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RemoveProductResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type UpdatePreferenceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePreferenceResponse) Reset() {
+	*x = UpdatePreferenceResponse{}
+	mi := &file_user_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePreferenceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePreferenceResponse) ProtoMessage() {}
+
+func (x *UpdatePreferenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePreferenceResponse.ProtoReflect.Descriptor instead.
+func (*UpdatePreferenceResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpdatePreferenceResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type RemovePreferenceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePreferenceResponse) Reset() {
+	*x = RemovePreferenceResponse{}
+	mi := &file_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePreferenceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePreferenceResponse) ProtoMessage() {}
+
+func (x *RemovePreferenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePreferenceResponse.ProtoReflect.Descriptor instead.
+func (*RemovePreferenceResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RemovePreferenceResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type AddProductItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductName   string                 `protobuf:"bytes,1,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Unit          string                 `protobuf:"bytes,3,opt,name=unit,proto3" json:"unit,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Category      string                 `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddProductItem) Reset() {
+	*x = AddProductItem{}
+	mi := &file_user_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddProductItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProductItem) ProtoMessage() {}
+
+func (x *AddProductItem) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProductItem.ProtoReflect.Descriptor instead.
+func (*AddProductItem) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AddProductItem) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *AddProductItem) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *AddProductItem) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *AddProductItem) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *AddProductItem) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+type BatchAddProductsRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	Items       []*AddProductItem      `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	// Если true, ошибка любого элемента откатывает весь пакет
+	AllOrNothing  bool `protobuf:"varint,3,opt,name=all_or_nothing,json=allOrNothing,proto3" json:"all_or_nothing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchAddProductsRequest) Reset() {
+	*x = BatchAddProductsRequest{}
+	mi := &file_user_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchAddProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchAddProductsRequest) ProtoMessage() {}
+
+func (x *BatchAddProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchAddProductsRequest.ProtoReflect.Descriptor instead.
+func (*BatchAddProductsRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *BatchAddProductsRequest) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *BatchAddProductsRequest) GetItems() []*AddProductItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *BatchAddProductsRequest) GetAllOrNothing() bool {
+	if x != nil {
+		return x.AllOrNothing
+	}
+	return false
+}
+
+type BatchRemoveProductsRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	ProductIds  []string               `protobuf:"bytes,2,rep,name=product_ids,json=productIds,proto3" json:"product_ids,omitempty"`
+	// Если true, ошибка любого элемента откатывает весь пакет
+	AllOrNothing  bool `protobuf:"varint,3,opt,name=all_or_nothing,json=allOrNothing,proto3" json:"all_or_nothing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchRemoveProductsRequest) Reset() {
+	*x = BatchRemoveProductsRequest{}
+	mi := &file_user_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRemoveProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRemoveProductsRequest) ProtoMessage() {}
+
+func (x *BatchRemoveProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRemoveProductsRequest.ProtoReflect.Descriptor instead.
+func (*BatchRemoveProductsRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *BatchRemoveProductsRequest) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *BatchRemoveProductsRequest) GetProductIds() []string {
+	if x != nil {
+		return x.ProductIds
+	}
+	return nil
+}
+
+func (x *BatchRemoveProductsRequest) GetAllOrNothing() bool {
+	if x != nil {
+		return x.AllOrNothing
+	}
+	return false
+}
+
+// Результат обработки одного элемента пакета
+type BatchItemResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Позиция элемента в запросе
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Код google.rpc.Code, 0 (OK) при успехе
+	Code int32 `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	// Стабильная причина ошибки, как в google.rpc.ErrorInfo.reason
+	Reason  string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Message string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	// Сохраненный продукт, заполняется для успешно добавленных элементов
+	Product *Product `protobuf:"bytes,5,opt,name=product,proto3" json:"product,omitempty"`
+	// Идентификатор продукта, заполняется для успешно удаленных элементов
+	ProductId     string `protobuf:"bytes,6,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchItemResult) Reset() {
+	*x = BatchItemResult{}
+	mi := &file_user_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchItemResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchItemResult) ProtoMessage() {}
+
+func (x *BatchItemResult) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchItemResult.ProtoReflect.Descriptor instead.
+func (*BatchItemResult) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BatchItemResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchItemResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BatchItemResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BatchItemResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *BatchItemResult) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *BatchItemResult) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type BatchAddProductsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// false, если пакет был откачен в режиме all_or_nothing
+	Committed     bool               `protobuf:"varint,1,opt,name=committed,proto3" json:"committed,omitempty"`
+	Results       []*BatchItemResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchAddProductsResponse) Reset() {
+	*x = BatchAddProductsResponse{}
+	mi := &file_user_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchAddProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchAddProductsResponse) ProtoMessage() {}
+
+func (x *BatchAddProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchAddProductsResponse.ProtoReflect.Descriptor instead.
+func (*BatchAddProductsResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BatchAddProductsResponse) GetCommitted() bool {
+	if x != nil {
+		return x.Committed
+	}
+	return false
+}
+
+func (x *BatchAddProductsResponse) GetResults() []*BatchItemResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type BatchRemoveProductsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// false, если пакет был откачен в режиме all_or_nothing
+	Committed     bool               `protobuf:"varint,1,opt,name=committed,proto3" json:"committed,omitempty"`
+	Results       []*BatchItemResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchRemoveProductsResponse) Reset() {
+	*x = BatchRemoveProductsResponse{}
+	mi := &file_user_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRemoveProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRemoveProductsResponse) ProtoMessage() {}
+
+func (x *BatchRemoveProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRemoveProductsResponse.ProtoReflect.Descriptor instead.
+func (*BatchRemoveProductsResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BatchRemoveProductsResponse) GetCommitted() bool {
+	if x != nil {
+		return x.Committed
+	}
+	return false
+}
+
+func (x *BatchRemoveProductsResponse) GetResults() []*BatchItemResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type CatalogProduct struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CanonicalName string                 `protobuf:"bytes,2,opt,name=canonical_name,json=canonicalName,proto3" json:"canonical_name,omitempty"`
+	Category      string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	// Локализованные названия, ключ - локаль (ru, en и т.д.)
+	DisplayNames map[string]string `protobuf:"bytes,4,rep,name=display_names,json=displayNames,proto3" json:"display_names,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Синонимы; каноническое и локализованные названия добавляются автоматически
+	Aliases       []string               `protobuf:"bytes,5,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CatalogProduct) Reset() {
+	*x = CatalogProduct{}
+	mi := &file_user_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CatalogProduct) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdatePreferenceRequest) ProtoMessage() {}
+func (*CatalogProduct) ProtoMessage() {}
 
-func (x *UpdatePreferenceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[0]
+func (x *CatalogProduct) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -54,48 +1671,83 @@ func (x *UpdatePreferenceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdatePreferenceRequest.ProtoReflect.Descriptor instead.
-func (*UpdatePreferenceRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use CatalogProduct.ProtoReflect.Descriptor instead.
+func (*CatalogProduct) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *UpdatePreferenceRequest) GetAccessToken() string {
+func (x *CatalogProduct) GetId() string {
 	if x != nil {
-		return x.AccessToken
+		return x.Id
 	}
 	return ""
 }
 
-func (x *UpdatePreferenceRequest) GetPreferenceName() string {
+func (x *CatalogProduct) GetCanonicalName() string {
 	if x != nil {
-		return x.PreferenceName
+		return x.CanonicalName
 	}
 	return ""
 }
 
-type RemoveProductRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
-	ProductName   string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+func (x *CatalogProduct) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CatalogProduct) GetDisplayNames() map[string]string {
+	if x != nil {
+		return x.DisplayNames
+	}
+	return nil
+}
+
+func (x *CatalogProduct) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *CatalogProduct) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *CatalogProduct) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type UpsertCatalogProductRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Запись ищется по canonical_name, id игнорируется
+	Product       *CatalogProduct `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RemoveProductRequest) Reset() {
-	*x = RemoveProductRequest{}
-	mi := &file_user_proto_msgTypes[1]
+func (x *UpsertCatalogProductRequest) Reset() {
+	*x = UpsertCatalogProductRequest{}
+	mi := &file_user_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemoveProductRequest) String() string {
+func (x *UpsertCatalogProductRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveProductRequest) ProtoMessage() {}
+func (*UpsertCatalogProductRequest) ProtoMessage() {}
 
-func (x *RemoveProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[1]
+func (x *UpsertCatalogProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -106,48 +1758,40 @@ func (x *RemoveProductRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveProductRequest.ProtoReflect.Descriptor instead.
-func (*RemoveProductRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{1}
-}
-
-func (x *RemoveProductRequest) GetAccessToken() string {
-	if x != nil {
-		return x.AccessToken
-	}
-	return ""
+// Deprecated: Use UpsertCatalogProductRequest.ProtoReflect.Descriptor instead.
+func (*UpsertCatalogProductRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *RemoveProductRequest) GetProductName() string {
+func (x *UpsertCatalogProductRequest) GetProduct() *CatalogProduct {
 	if x != nil {
-		return x.ProductName
+		return x.Product
 	}
-	return ""
+	return nil
 }
 
-type AddProductRequest struct {
+type UpsertCatalogProductResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
-	ProductName   string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	Product       *CatalogProduct        `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddProductRequest) Reset() {
-	*x = AddProductRequest{}
-	mi := &file_user_proto_msgTypes[2]
+func (x *UpsertCatalogProductResponse) Reset() {
+	*x = UpsertCatalogProductResponse{}
+	mi := &file_user_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddProductRequest) String() string {
+func (x *UpsertCatalogProductResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddProductRequest) ProtoMessage() {}
+func (*UpsertCatalogProductResponse) ProtoMessage() {}
 
-func (x *AddProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[2]
+func (x *UpsertCatalogProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -158,47 +1802,40 @@ func (x *AddProductRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddProductRequest.ProtoReflect.Descriptor instead.
-func (*AddProductRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *AddProductRequest) GetAccessToken() string {
-	if x != nil {
-		return x.AccessToken
-	}
-	return ""
+// Deprecated: Use UpsertCatalogProductResponse.ProtoReflect.Descriptor instead.
+func (*UpsertCatalogProductResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *AddProductRequest) GetProductName() string {
+func (x *UpsertCatalogProductResponse) GetProduct() *CatalogProduct {
 	if x != nil {
-		return x.ProductName
+		return x.Product
 	}
-	return ""
+	return nil
 }
 
-type RemovePreferenceRequest struct {
+type DeleteCatalogProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RemovePreferenceRequest) Reset() {
-	*x = RemovePreferenceRequest{}
-	mi := &file_user_proto_msgTypes[3]
+func (x *DeleteCatalogProductRequest) Reset() {
+	*x = DeleteCatalogProductRequest{}
+	mi := &file_user_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemovePreferenceRequest) String() string {
+func (x *DeleteCatalogProductRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemovePreferenceRequest) ProtoMessage() {}
+func (*DeleteCatalogProductRequest) ProtoMessage() {}
 
-func (x *RemovePreferenceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[3]
+func (x *DeleteCatalogProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -209,40 +1846,40 @@ func (x *RemovePreferenceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemovePreferenceRequest.ProtoReflect.Descriptor instead.
-func (*RemovePreferenceRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use DeleteCatalogProductRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCatalogProductRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *RemovePreferenceRequest) GetAccessToken() string {
+func (x *DeleteCatalogProductRequest) GetId() string {
 	if x != nil {
-		return x.AccessToken
+		return x.Id
 	}
 	return ""
 }
 
-type UserRequest struct {
+type DeleteCatalogProductResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserRequest) Reset() {
-	*x = UserRequest{}
-	mi := &file_user_proto_msgTypes[4]
+func (x *DeleteCatalogProductResponse) Reset() {
+	*x = DeleteCatalogProductResponse{}
+	mi := &file_user_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserRequest) String() string {
+func (x *DeleteCatalogProductResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserRequest) ProtoMessage() {}
+func (*DeleteCatalogProductResponse) ProtoMessage() {}
 
-func (x *UserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[4]
+func (x *DeleteCatalogProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -253,40 +1890,43 @@ func (x *UserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserRequest.ProtoReflect.Descriptor instead.
-func (*UserRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use DeleteCatalogProductResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCatalogProductResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *UserRequest) GetAccessToken() string {
+func (x *DeleteCatalogProductResponse) GetSuccess() bool {
 	if x != nil {
-		return x.AccessToken
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-type GetProductsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductNames  []string               `protobuf:"bytes,1,rep,name=product_names,json=productNames,proto3" json:"product_names,omitempty"`
+type ListCatalogProductsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Префикс канонического названия без учета регистра
+	NamePrefix    string `protobuf:"bytes,1,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	PageSize      int32  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProductsResponse) Reset() {
-	*x = GetProductsResponse{}
-	mi := &file_user_proto_msgTypes[5]
+func (x *ListCatalogProductsRequest) Reset() {
+	*x = ListCatalogProductsRequest{}
+	mi := &file_user_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProductsResponse) String() string {
+func (x *ListCatalogProductsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProductsResponse) ProtoMessage() {}
+func (*ListCatalogProductsRequest) ProtoMessage() {}
 
-func (x *GetProductsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[5]
+func (x *ListCatalogProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -297,40 +1937,55 @@ func (x *GetProductsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProductsResponse.ProtoReflect.Descriptor instead.
-func (*GetProductsResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ListCatalogProductsRequest.ProtoReflect.Descriptor instead.
+func (*ListCatalogProductsRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *GetProductsResponse) GetProductNames() []string {
+func (x *ListCatalogProductsRequest) GetNamePrefix() string {
 	if x != nil {
-		return x.ProductNames
+		return x.NamePrefix
 	}
-	return nil
+	return ""
 }
 
-type GetPreferenceResponse struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	PreferenceName string                 `protobuf:"bytes,1,opt,name=preference_name,json=preferenceName,proto3" json:"preference_name,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *ListCatalogProductsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
 }
 
-func (x *GetPreferenceResponse) Reset() {
-	*x = GetPreferenceResponse{}
-	mi := &file_user_proto_msgTypes[6]
+func (x *ListCatalogProductsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListCatalogProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*CatalogProduct      `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCatalogProductsResponse) Reset() {
+	*x = ListCatalogProductsResponse{}
+	mi := &file_user_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPreferenceResponse) String() string {
+func (x *ListCatalogProductsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPreferenceResponse) ProtoMessage() {}
+func (*ListCatalogProductsResponse) ProtoMessage() {}
 
-func (x *GetPreferenceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[6]
+func (x *ListCatalogProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -341,40 +1996,52 @@ func (x *GetPreferenceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPreferenceResponse.ProtoReflect.Descriptor instead.
-func (*GetPreferenceResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ListCatalogProductsResponse.ProtoReflect.Descriptor instead.
+func (*ListCatalogProductsResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *GetPreferenceResponse) GetPreferenceName() string {
+func (x *ListCatalogProductsResponse) GetProducts() []*CatalogProduct {
 	if x != nil {
-		return x.PreferenceName
+		return x.Products
+	}
+	return nil
+}
+
+func (x *ListCatalogProductsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
 	}
 	return ""
 }
 
-type AddProductResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+type RevokeTokenRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор токена (claim jti)
+	Jti string `protobuf:"bytes,1,opt,name=jti,proto3" json:"jti,omitempty"`
+	// Срок действия токена (claim exp), после него запись об отзыве удаляется
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Владелец токена (claim sub), необязательный
+	UserId        string `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddProductResponse) Reset() {
-	*x = AddProductResponse{}
-	mi := &file_user_proto_msgTypes[7]
+func (x *RevokeTokenRequest) Reset() {
+	*x = RevokeTokenRequest{}
+	mi := &file_user_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddProductResponse) String() string {
+func (x *RevokeTokenRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddProductResponse) ProtoMessage() {}
+func (*RevokeTokenRequest) ProtoMessage() {}
 
-func (x *AddProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[7]
+func (x *RevokeTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -385,40 +2052,54 @@ func (x *AddProductResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddProductResponse.ProtoReflect.Descriptor instead.
-func (*AddProductResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use RevokeTokenRequest.ProtoReflect.Descriptor instead.
+func (*RevokeTokenRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *AddProductResponse) GetSuccess() bool {
+func (x *RevokeTokenRequest) GetJti() string {
 	if x != nil {
-		return x.Success
+		return x.Jti
 	}
-	return false
+	return ""
 }
 
-type RemoveProductResponse struct {
+func (x *RevokeTokenRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *RevokeTokenRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type RevokeTokenResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RemoveProductResponse) Reset() {
-	*x = RemoveProductResponse{}
-	mi := &file_user_proto_msgTypes[8]
+func (x *RevokeTokenResponse) Reset() {
+	*x = RevokeTokenResponse{}
+	mi := &file_user_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemoveProductResponse) String() string {
+func (x *RevokeTokenResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveProductResponse) ProtoMessage() {}
+func (*RevokeTokenResponse) ProtoMessage() {}
 
-func (x *RemoveProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[8]
+func (x *RevokeTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -429,40 +2110,42 @@ func (x *RemoveProductResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveProductResponse.ProtoReflect.Descriptor instead.
-func (*RemoveProductResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use RevokeTokenResponse.ProtoReflect.Descriptor instead.
+func (*RevokeTokenResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *RemoveProductResponse) GetSuccess() bool {
+func (x *RevokeTokenResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-type UpdatePreferenceResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+type RevokeUserTokensRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Отзываются токены с claim iat не позже этого момента, усеченного до секунд, по умолчанию - текущий момент
+	RevokedBefore *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=revoked_before,json=revokedBefore,proto3" json:"revoked_before,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdatePreferenceResponse) Reset() {
-	*x = UpdatePreferenceResponse{}
-	mi := &file_user_proto_msgTypes[9]
+func (x *RevokeUserTokensRequest) Reset() {
+	*x = RevokeUserTokensRequest{}
+	mi := &file_user_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdatePreferenceResponse) String() string {
+func (x *RevokeUserTokensRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdatePreferenceResponse) ProtoMessage() {}
+func (*RevokeUserTokensRequest) ProtoMessage() {}
 
-func (x *UpdatePreferenceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[9]
+func (x *RevokeUserTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -473,40 +2156,48 @@ func (x *UpdatePreferenceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdatePreferenceResponse.ProtoReflect.Descriptor instead.
-func (*UpdatePreferenceResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use RevokeUserTokensRequest.ProtoReflect.Descriptor instead.
+func (*RevokeUserTokensRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *UpdatePreferenceResponse) GetSuccess() bool {
+func (x *RevokeUserTokensRequest) GetUserId() string {
 	if x != nil {
-		return x.Success
+		return x.UserId
 	}
-	return false
+	return ""
 }
 
-type RemovePreferenceResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+func (x *RevokeUserTokensRequest) GetRevokedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedBefore
+	}
+	return nil
+}
+
+type RevokeUserTokensResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Действующая граница отзыва, она не сдвигается назад
+	RevokedBefore *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=revoked_before,json=revokedBefore,proto3" json:"revoked_before,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RemovePreferenceResponse) Reset() {
-	*x = RemovePreferenceResponse{}
-	mi := &file_user_proto_msgTypes[10]
+func (x *RevokeUserTokensResponse) Reset() {
+	*x = RevokeUserTokensResponse{}
+	mi := &file_user_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemovePreferenceResponse) String() string {
+func (x *RevokeUserTokensResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemovePreferenceResponse) ProtoMessage() {}
+func (*RevokeUserTokensResponse) ProtoMessage() {}
 
-func (x *RemovePreferenceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[10]
+func (x *RevokeUserTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -517,16 +2208,16 @@ func (x *RemovePreferenceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemovePreferenceResponse.ProtoReflect.Descriptor instead.
-func (*RemovePreferenceResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use RevokeUserTokensResponse.ProtoReflect.Descriptor instead.
+func (*RevokeUserTokensResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *RemovePreferenceResponse) GetSuccess() bool {
+func (x *RevokeUserTokensResponse) GetRevokedBefore() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Success
+		return x.RevokedBefore
 	}
-	return false
+	return nil
 }
 
 var File_user_proto protoreflect.FileDescriptor
@@ -534,39 +2225,197 @@ var File_user_proto protoreflect.FileDescriptor
 const file_user_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"user.proto\x12\x04user\"e\n" +
+	"user.proto\x12\x04user\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"\x9e\x01\n" +
 	"\x17UpdatePreferenceRequest\x12!\n" +
-	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12'\n" +
-	"\x0fpreference_name\x18\x02 \x01(\tR\x0epreferenceName\"\\\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12+\n" +
+	"\x0fpreference_name\x18\x02 \x01(\tB\x02\x18\x01R\x0epreferenceName\x123\n" +
+	"\vpreferences\x18\x03 \x01(\v2\x11.user.PreferencesR\vpreferences\"y\n" +
+	"\x10NutritionTargets\x12\x1a\n" +
+	"\bcalories\x18\x01 \x01(\x05R\bcalories\x12\x1b\n" +
+	"\tprotein_g\x18\x02 \x01(\x01R\bproteinG\x12\x13\n" +
+	"\x05fat_g\x18\x03 \x01(\x01R\x04fatG\x12\x17\n" +
+	"\acarbs_g\x18\x04 \x01(\x01R\x06carbsG\"\xe1\x01\n" +
+	"\vPreferences\x12\x14\n" +
+	"\x05diets\x18\x01 \x03(\tR\x05diets\x12\x1c\n" +
+	"\tallergens\x18\x02 \x03(\tR\tallergens\x121\n" +
+	"\x14disliked_ingredients\x18\x03 \x03(\tR\x13dislikedIngredients\x120\n" +
+	"\atargets\x18\x04 \x01(\v2\x16.user.NutritionTargetsR\atargets\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"{\n" +
+	"\x16PreferenceEntryRequest\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12(\n" +
+	"\x04kind\x18\x02 \x01(\x0e2\x14.user.PreferenceKindR\x04kind\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\"h\n" +
+	"\x17PreferenceEntryResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x123\n" +
+	"\vpreferences\x18\x02 \x01(\v2\x11.user.PreferencesR\vpreferences\"{\n" +
 	"\x14RemoveProductRequest\x12!\n" +
 	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12!\n" +
-	"\fproduct_name\x18\x02 \x01(\tR\vproductName\"Y\n" +
+	"\fproduct_name\x18\x02 \x01(\tR\vproductName\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\tR\tproductId\"\xe0\x01\n" +
 	"\x11AddProductRequest\x12!\n" +
 	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12!\n" +
-	"\fproduct_name\x18\x02 \x01(\tR\vproductName\"<\n" +
+	"\fproduct_name\x18\x02 \x01(\tR\vproductName\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x01R\bquantity\x12\x12\n" +
+	"\x04unit\x18\x04 \x01(\tR\x04unit\x129\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1a\n" +
+	"\bcategory\x18\x06 \x01(\tR\bcategory\"\xd8\x02\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x01R\bquantity\x12\x12\n" +
+	"\x04unit\x18\x04 \x01(\tR\x04unit\x129\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1a\n" +
+	"\bcategory\x18\b \x01(\tR\bcategory\x12,\n" +
+	"\x12catalog_product_id\x18\t \x01(\tR\x10catalogProductId\"<\n" +
 	"\x17RemovePreferenceRequest\x12!\n" +
 	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\"0\n" +
 	"\vUserRequest\x12!\n" +
-	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\":\n" +
-	"\x13GetProductsResponse\x12#\n" +
-	"\rproduct_names\x18\x01 \x03(\tR\fproductNames\"@\n" +
-	"\x15GetPreferenceResponse\x12'\n" +
-	"\x0fpreference_name\x18\x01 \x01(\tR\x0epreferenceName\".\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\"\x91\x01\n" +
+	"\rProductFilter\x12\x1f\n" +
+	"\vname_prefix\x18\x01 \x01(\tR\n" +
+	"namePrefix\x12C\n" +
+	"\x0fexpiring_before\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0eexpiringBefore\x12\x1a\n" +
+	"\bcategory\x18\x03 \x01(\tR\bcategory\"\xf1\x01\n" +
+	"\x12GetProductsRequest\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12+\n" +
+	"\x06filter\x18\x04 \x01(\v2\x13.user.ProductFilterR\x06filter\x12/\n" +
+	"\asort_by\x18\x05 \x01(\x0e2\x16.user.ProductSortFieldR\x06sortBy\x12\x1e\n" +
+	"\n" +
+	"descending\x18\x06 \x01(\bR\n" +
+	"descending\"}\n" +
+	"\x13GetProductsResponse\x12)\n" +
+	"\bproducts\x18\x02 \x03(\v2\r.user.ProductR\bproducts\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageTokenJ\x04\b\x01\x10\x02R\rproduct_names\"y\n" +
+	"\x15GetPreferenceResponse\x12+\n" +
+	"\x0fpreference_name\x18\x01 \x01(\tB\x02\x18\x01R\x0epreferenceName\x123\n" +
+	"\vpreferences\x18\x02 \x01(\v2\x11.user.PreferencesR\vpreferences\"W\n" +
 	"\x12AddProductResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"1\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12'\n" +
+	"\aproduct\x18\x02 \x01(\v2\r.user.ProductR\aproduct\"1\n" +
 	"\x15RemoveProductResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\"4\n" +
 	"\x18UpdatePreferenceResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\"4\n" +
 	"\x18RemovePreferenceResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess2\xd4\x03\n" +
-	"\vUserService\x12?\n" +
-	"\x0fGetUserProducts\x12\x11.user.UserRequest\x1a\x19.user.GetProductsResponse\x12C\n" +
-	"\x11GetUserPreference\x12\x11.user.UserRequest\x1a\x1b.user.GetPreferenceResponse\x12C\n" +
-	"\x0eAddUserProduct\x12\x17.user.AddProductRequest\x1a\x18.user.AddProductResponse\x12L\n" +
-	"\x11RemoveUserProduct\x12\x1a.user.RemoveProductRequest\x1a\x1b.user.RemoveProductResponse\x12U\n" +
-	"\x14UpdateUserPreference\x12\x1d.user.UpdatePreferenceRequest\x1a\x1e.user.UpdatePreferenceResponse\x12U\n" +
-	"\x14RemoveUserPreference\x12\x1d.user.RemovePreferenceRequest\x1a\x1e.user.RemovePreferenceResponseB\x17Z\x15user-service/gen/userb\x06proto3"
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xba\x01\n" +
+	"\x0eAddProductItem\x12!\n" +
+	"\fproduct_name\x18\x01 \x01(\tR\vproductName\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x01R\bquantity\x12\x12\n" +
+	"\x04unit\x18\x03 \x01(\tR\x04unit\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1a\n" +
+	"\bcategory\x18\x05 \x01(\tR\bcategory\"\x8e\x01\n" +
+	"\x17BatchAddProductsRequest\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12*\n" +
+	"\x05items\x18\x02 \x03(\v2\x14.user.AddProductItemR\x05items\x12$\n" +
+	"\x0eall_or_nothing\x18\x03 \x01(\bR\fallOrNothing\"\x86\x01\n" +
+	"\x1aBatchRemoveProductsRequest\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12\x1f\n" +
+	"\vproduct_ids\x18\x02 \x03(\tR\n" +
+	"productIds\x12$\n" +
+	"\x0eall_or_nothing\x18\x03 \x01(\bR\fallOrNothing\"\xb5\x01\n" +
+	"\x0fBatchItemResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\x05R\x04code\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12'\n" +
+	"\aproduct\x18\x05 \x01(\v2\r.user.ProductR\aproduct\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x06 \x01(\tR\tproductId\"i\n" +
+	"\x18BatchAddProductsResponse\x12\x1c\n" +
+	"\tcommitted\x18\x01 \x01(\bR\tcommitted\x12/\n" +
+	"\aresults\x18\x02 \x03(\v2\x15.user.BatchItemResultR\aresults\"l\n" +
+	"\x1bBatchRemoveProductsResponse\x12\x1c\n" +
+	"\tcommitted\x18\x01 \x01(\bR\tcommitted\x12/\n" +
+	"\aresults\x18\x02 \x03(\v2\x15.user.BatchItemResultR\aresults\"\x81\x03\n" +
+	"\x0eCatalogProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0ecanonical_name\x18\x02 \x01(\tR\rcanonicalName\x12\x1a\n" +
+	"\bcategory\x18\x03 \x01(\tR\bcategory\x12K\n" +
+	"\rdisplay_names\x18\x04 \x03(\v2&.user.CatalogProduct.DisplayNamesEntryR\fdisplayNames\x12\x18\n" +
+	"\aaliases\x18\x05 \x03(\tR\aaliases\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x1a?\n" +
+	"\x11DisplayNamesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"M\n" +
+	"\x1bUpsertCatalogProductRequest\x12.\n" +
+	"\aproduct\x18\x01 \x01(\v2\x14.user.CatalogProductR\aproduct\"N\n" +
+	"\x1cUpsertCatalogProductResponse\x12.\n" +
+	"\aproduct\x18\x01 \x01(\v2\x14.user.CatalogProductR\aproduct\"-\n" +
+	"\x1bDeleteCatalogProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"8\n" +
+	"\x1cDeleteCatalogProductResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"y\n" +
+	"\x1aListCatalogProductsRequest\x12\x1f\n" +
+	"\vname_prefix\x18\x01 \x01(\tR\n" +
+	"namePrefix\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"w\n" +
+	"\x1bListCatalogProductsResponse\x120\n" +
+	"\bproducts\x18\x01 \x03(\v2\x14.user.CatalogProductR\bproducts\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"z\n" +
+	"\x12RevokeTokenRequest\x12\x10\n" +
+	"\x03jti\x18\x01 \x01(\tR\x03jti\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\"/\n" +
+	"\x13RevokeTokenResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"u\n" +
+	"\x17RevokeUserTokensRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12A\n" +
+	"\x0erevoked_before\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\rrevokedBefore\"]\n" +
+	"\x18RevokeUserTokensResponse\x12A\n" +
+	"\x0erevoked_before\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\rrevokedBefore*\x92\x01\n" +
+	"\x0ePreferenceKind\x12\x1f\n" +
+	"\x1bPREFERENCE_KIND_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14PREFERENCE_KIND_DIET\x10\x01\x12\x1c\n" +
+	"\x18PREFERENCE_KIND_ALLERGEN\x10\x02\x12'\n" +
+	"#PREFERENCE_KIND_DISLIKED_INGREDIENT\x10\x03*\x99\x01\n" +
+	"\x10ProductSortField\x12\"\n" +
+	"\x1ePRODUCT_SORT_FIELD_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17PRODUCT_SORT_FIELD_NAME\x10\x01\x12!\n" +
+	"\x1dPRODUCT_SORT_FIELD_CREATED_AT\x10\x02\x12!\n" +
+	"\x1dPRODUCT_SORT_FIELD_EXPIRES_AT\x10\x032\xb2\t\n" +
+	"\vUserService\x12_\n" +
+	"\x0fGetUserProducts\x12\x18.user.GetProductsRequest\x1a\x19.user.GetProductsResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/me/products\x12_\n" +
+	"\x11GetUserPreference\x12\x11.user.UserRequest\x1a\x1b.user.GetPreferenceResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/me/preferences\x12_\n" +
+	"\x0eAddUserProduct\x12\x17.user.AddProductRequest\x1a\x18.user.AddProductResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/me/products\x12\x85\x01\n" +
+	"\x11RemoveUserProduct\x12\x1a.user.RemoveProductRequest\x1a\x1b.user.RemoveProductResponse\"7\x82\xd3\xe4\x93\x021Z\x11*\x0f/v1/me/products*\x1c/v1/me/products/{product_id}\x12t\n" +
+	"\x14UpdateUserPreference\x12\x1d.user.UpdatePreferenceRequest\x1a\x1e.user.UpdatePreferenceResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\x1a\x12/v1/me/preferences\x12q\n" +
+	"\x14RemoveUserPreference\x12\x1d.user.RemovePreferenceRequest\x1a\x1e.user.RemovePreferenceResponse\"\x1a\x82\xd3\xe4\x93\x02\x14*\x12/v1/me/preferences\x12|\n" +
+	"\x16AddUserPreferenceEntry\x12\x1c.user.PreferenceEntryRequest\x1a\x1d.user.PreferenceEntryResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/me/preferences/entries\x12\x8b\x01\n" +
+	"\x19RemoveUserPreferenceEntry\x12\x1c.user.PreferenceEntryRequest\x1a\x1d.user.PreferenceEntryResponse\"1\x82\xd3\xe4\x93\x02+*)/v1/me/preferences/entries/{kind}/{value}\x12z\n" +
+	"\x14BatchAddUserProducts\x12\x1d.user.BatchAddProductsRequest\x1a\x1e.user.BatchAddProductsResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/me/products:batchAdd\x12\x86\x01\n" +
+	"\x17BatchRemoveUserProducts\x12 .user.BatchRemoveProductsRequest\x1a!.user.BatchRemoveProductsResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/me/products:batchRemove2\xaa\x02\n" +
+	"\x0eCatalogService\x12]\n" +
+	"\x14UpsertCatalogProduct\x12!.user.UpsertCatalogProductRequest\x1a\".user.UpsertCatalogProductResponse\x12]\n" +
+	"\x14DeleteCatalogProduct\x12!.user.DeleteCatalogProductRequest\x1a\".user.DeleteCatalogProductResponse\x12Z\n" +
+	"\x13ListCatalogProducts\x12 .user.ListCatalogProductsRequest\x1a!.user.ListCatalogProductsResponse2\xaf\x01\n" +
+	"\x16TokenRevocationService\x12B\n" +
+	"\vRevokeToken\x12\x18.user.RevokeTokenRequest\x1a\x19.user.RevokeTokenResponse\x12Q\n" +
+	"\x10RevokeUserTokens\x12\x1d.user.RevokeUserTokensRequest\x1a\x1e.user.RevokeUserTokensResponseB\xb0\x01\x92A\x95\x01\x12\x1e\n" +
+	"\x15User Service REST API2\x051.0.0Za\n" +
+	"_\n" +
+	"\n" +
+	"BearerAuth\x12Q\b\x02\x12<Токен доступа в формате \"Bearer <token>\"\x1a\rAuthorization \x02b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00Z\x15user-service/gen/userb\x06proto3"
 
 var (
 	file_user_proto_rawDescOnce sync.Once
@@ -580,38 +2429,114 @@ func file_user_proto_rawDescGZIP() []byte {
 	return file_user_proto_rawDescData
 }
 
-var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_user_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
 var file_user_proto_goTypes = []any{
-	(*UpdatePreferenceRequest)(nil),  // 0: user.UpdatePreferenceRequest
-	(*RemoveProductRequest)(nil),     // 1: user.RemoveProductRequest
-	(*AddProductRequest)(nil),        // 2: user.AddProductRequest
-	(*RemovePreferenceRequest)(nil),  // 3: user.RemovePreferenceRequest
-	(*UserRequest)(nil),              // 4: user.UserRequest
-	(*GetProductsResponse)(nil),      // 5: user.GetProductsResponse
-	(*GetPreferenceResponse)(nil),    // 6: user.GetPreferenceResponse
-	(*AddProductResponse)(nil),       // 7: user.AddProductResponse
-	(*RemoveProductResponse)(nil),    // 8: user.RemoveProductResponse
-	(*UpdatePreferenceResponse)(nil), // 9: user.UpdatePreferenceResponse
-	(*RemovePreferenceResponse)(nil), // 10: user.RemovePreferenceResponse
+	(PreferenceKind)(0),                  // 0: user.PreferenceKind
+	(ProductSortField)(0),                // 1: user.ProductSortField
+	(*UpdatePreferenceRequest)(nil),      // 2: user.UpdatePreferenceRequest
+	(*NutritionTargets)(nil),             // 3: user.NutritionTargets
+	(*Preferences)(nil),                  // 4: user.Preferences
+	(*PreferenceEntryRequest)(nil),       // 5: user.PreferenceEntryRequest
+	(*PreferenceEntryResponse)(nil),      // 6: user.PreferenceEntryResponse
+	(*RemoveProductRequest)(nil),         // 7: user.RemoveProductRequest
+	(*AddProductRequest)(nil),            // 8: user.AddProductRequest
+	(*Product)(nil),                      // 9: user.Product
+	(*RemovePreferenceRequest)(nil),      // 10: user.RemovePreferenceRequest
+	(*UserRequest)(nil),                  // 11: user.UserRequest
+	(*ProductFilter)(nil),                // 12: user.ProductFilter
+	(*GetProductsRequest)(nil),           // 13: user.GetProductsRequest
+	(*GetProductsResponse)(nil),          // 14: user.GetProductsResponse
+	(*GetPreferenceResponse)(nil),        // 15: user.GetPreferenceResponse
+	(*AddProductResponse)(nil),           // 16: user.AddProductResponse
+	(*RemoveProductResponse)(nil),        // 17: user.RemoveProductResponse
+	(*UpdatePreferenceResponse)(nil),     // 18: user.UpdatePreferenceResponse
+	(*RemovePreferenceResponse)(nil),     // 19: user.RemovePreferenceResponse
+	(*AddProductItem)(nil),               // 20: user.AddProductItem
+	(*BatchAddProductsRequest)(nil),      // 21: user.BatchAddProductsRequest
+	(*BatchRemoveProductsRequest)(nil),   // 22: user.BatchRemoveProductsRequest
+	(*BatchItemResult)(nil),              // 23: user.BatchItemResult
+	(*BatchAddProductsResponse)(nil),     // 24: user.BatchAddProductsResponse
+	(*BatchRemoveProductsResponse)(nil),  // 25: user.BatchRemoveProductsResponse
+	(*CatalogProduct)(nil),               // 26: user.CatalogProduct
+	(*UpsertCatalogProductRequest)(nil),  // 27: user.UpsertCatalogProductRequest
+	(*UpsertCatalogProductResponse)(nil), // 28: user.UpsertCatalogProductResponse
+	(*DeleteCatalogProductRequest)(nil),  // 29: user.DeleteCatalogProductRequest
+	(*DeleteCatalogProductResponse)(nil), // 30: user.DeleteCatalogProductResponse
+	(*ListCatalogProductsRequest)(nil),   // 31: user.ListCatalogProductsRequest
+	(*ListCatalogProductsResponse)(nil),  // 32: user.ListCatalogProductsResponse
+	(*RevokeTokenRequest)(nil),           // 33: user.RevokeTokenRequest
+	(*RevokeTokenResponse)(nil),          // 34: user.RevokeTokenResponse
+	(*RevokeUserTokensRequest)(nil),      // 35: user.RevokeUserTokensRequest
+	(*RevokeUserTokensResponse)(nil),     // 36: user.RevokeUserTokensResponse
+	nil,                                  // 37: user.CatalogProduct.DisplayNamesEntry
+	(*timestamppb.Timestamp)(nil),        // 38: google.protobuf.Timestamp
 }
 var file_user_proto_depIdxs = []int32{
-	4,  // 0: user.UserService.GetUserProducts:input_type -> user.UserRequest
-	4,  // 1: user.UserService.GetUserPreference:input_type -> user.UserRequest
-	2,  // 2: user.UserService.AddUserProduct:input_type -> user.AddProductRequest
-	1,  // 3: user.UserService.RemoveUserProduct:input_type -> user.RemoveProductRequest
-	0,  // 4: user.UserService.UpdateUserPreference:input_type -> user.UpdatePreferenceRequest
-	3,  // 5: user.UserService.RemoveUserPreference:input_type -> user.RemovePreferenceRequest
-	5,  // 6: user.UserService.GetUserProducts:output_type -> user.GetProductsResponse
-	6,  // 7: user.UserService.GetUserPreference:output_type -> user.GetPreferenceResponse
-	7,  // 8: user.UserService.AddUserProduct:output_type -> user.AddProductResponse
-	8,  // 9: user.UserService.RemoveUserProduct:output_type -> user.RemoveProductResponse
-	9,  // 10: user.UserService.UpdateUserPreference:output_type -> user.UpdatePreferenceResponse
-	10, // 11: user.UserService.RemoveUserPreference:output_type -> user.RemovePreferenceResponse
-	6,  // [6:12] is the sub-list for method output_type
-	0,  // [0:6] is the sub-list for method input_type
-	0,  // [0:0] is the sub-list for extension type_name
-	0,  // [0:0] is the sub-list for extension extendee
-	0,  // [0:0] is the sub-list for field type_name
+	4,  // 0: user.UpdatePreferenceRequest.preferences:type_name -> user.Preferences
+	3,  // 1: user.Preferences.targets:type_name -> user.NutritionTargets
+	38, // 2: user.Preferences.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: user.PreferenceEntryRequest.kind:type_name -> user.PreferenceKind
+	4,  // 4: user.PreferenceEntryResponse.preferences:type_name -> user.Preferences
+	38, // 5: user.AddProductRequest.expires_at:type_name -> google.protobuf.Timestamp
+	38, // 6: user.Product.expires_at:type_name -> google.protobuf.Timestamp
+	38, // 7: user.Product.created_at:type_name -> google.protobuf.Timestamp
+	38, // 8: user.Product.updated_at:type_name -> google.protobuf.Timestamp
+	38, // 9: user.ProductFilter.expiring_before:type_name -> google.protobuf.Timestamp
+	12, // 10: user.GetProductsRequest.filter:type_name -> user.ProductFilter
+	1,  // 11: user.GetProductsRequest.sort_by:type_name -> user.ProductSortField
+	9,  // 12: user.GetProductsResponse.products:type_name -> user.Product
+	4,  // 13: user.GetPreferenceResponse.preferences:type_name -> user.Preferences
+	9,  // 14: user.AddProductResponse.product:type_name -> user.Product
+	38, // 15: user.AddProductItem.expires_at:type_name -> google.protobuf.Timestamp
+	20, // 16: user.BatchAddProductsRequest.items:type_name -> user.AddProductItem
+	9,  // 17: user.BatchItemResult.product:type_name -> user.Product
+	23, // 18: user.BatchAddProductsResponse.results:type_name -> user.BatchItemResult
+	23, // 19: user.BatchRemoveProductsResponse.results:type_name -> user.BatchItemResult
+	37, // 20: user.CatalogProduct.display_names:type_name -> user.CatalogProduct.DisplayNamesEntry
+	38, // 21: user.CatalogProduct.created_at:type_name -> google.protobuf.Timestamp
+	38, // 22: user.CatalogProduct.updated_at:type_name -> google.protobuf.Timestamp
+	26, // 23: user.UpsertCatalogProductRequest.product:type_name -> user.CatalogProduct
+	26, // 24: user.UpsertCatalogProductResponse.product:type_name -> user.CatalogProduct
+	26, // 25: user.ListCatalogProductsResponse.products:type_name -> user.CatalogProduct
+	38, // 26: user.RevokeTokenRequest.expires_at:type_name -> google.protobuf.Timestamp
+	38, // 27: user.RevokeUserTokensRequest.revoked_before:type_name -> google.protobuf.Timestamp
+	38, // 28: user.RevokeUserTokensResponse.revoked_before:type_name -> google.protobuf.Timestamp
+	13, // 29: user.UserService.GetUserProducts:input_type -> user.GetProductsRequest
+	11, // 30: user.UserService.GetUserPreference:input_type -> user.UserRequest
+	8,  // 31: user.UserService.AddUserProduct:input_type -> user.AddProductRequest
+	7,  // 32: user.UserService.RemoveUserProduct:input_type -> user.RemoveProductRequest
+	2,  // 33: user.UserService.UpdateUserPreference:input_type -> user.UpdatePreferenceRequest
+	10, // 34: user.UserService.RemoveUserPreference:input_type -> user.RemovePreferenceRequest
+	5,  // 35: user.UserService.AddUserPreferenceEntry:input_type -> user.PreferenceEntryRequest
+	5,  // 36: user.UserService.RemoveUserPreferenceEntry:input_type -> user.PreferenceEntryRequest
+	21, // 37: user.UserService.BatchAddUserProducts:input_type -> user.BatchAddProductsRequest
+	22, // 38: user.UserService.BatchRemoveUserProducts:input_type -> user.BatchRemoveProductsRequest
+	27, // 39: user.CatalogService.UpsertCatalogProduct:input_type -> user.UpsertCatalogProductRequest
+	29, // 40: user.CatalogService.DeleteCatalogProduct:input_type -> user.DeleteCatalogProductRequest
+	31, // 41: user.CatalogService.ListCatalogProducts:input_type -> user.ListCatalogProductsRequest
+	33, // 42: user.TokenRevocationService.RevokeToken:input_type -> user.RevokeTokenRequest
+	35, // 43: user.TokenRevocationService.RevokeUserTokens:input_type -> user.RevokeUserTokensRequest
+	14, // 44: user.UserService.GetUserProducts:output_type -> user.GetProductsResponse
+	15, // 45: user.UserService.GetUserPreference:output_type -> user.GetPreferenceResponse
+	16, // 46: user.UserService.AddUserProduct:output_type -> user.AddProductResponse
+	17, // 47: user.UserService.RemoveUserProduct:output_type -> user.RemoveProductResponse
+	18, // 48: user.UserService.UpdateUserPreference:output_type -> user.UpdatePreferenceResponse
+	19, // 49: user.UserService.RemoveUserPreference:output_type -> user.RemovePreferenceResponse
+	6,  // 50: user.UserService.AddUserPreferenceEntry:output_type -> user.PreferenceEntryResponse
+	6,  // 51: user.UserService.RemoveUserPreferenceEntry:output_type -> user.PreferenceEntryResponse
+	24, // 52: user.UserService.BatchAddUserProducts:output_type -> user.BatchAddProductsResponse
+	25, // 53: user.UserService.BatchRemoveUserProducts:output_type -> user.BatchRemoveProductsResponse
+	28, // 54: user.CatalogService.UpsertCatalogProduct:output_type -> user.UpsertCatalogProductResponse
+	30, // 55: user.CatalogService.DeleteCatalogProduct:output_type -> user.DeleteCatalogProductResponse
+	32, // 56: user.CatalogService.ListCatalogProducts:output_type -> user.ListCatalogProductsResponse
+	34, // 57: user.TokenRevocationService.RevokeToken:output_type -> user.RevokeTokenResponse
+	36, // 58: user.TokenRevocationService.RevokeUserTokens:output_type -> user.RevokeUserTokensResponse
+	44, // [44:59] is the sub-list for method output_type
+	29, // [29:44] is the sub-list for method input_type
+	29, // [29:29] is the sub-list for extension type_name
+	29, // [29:29] is the sub-list for extension extendee
+	0,  // [0:29] is the sub-list for field type_name
 }
 
 func init() { file_user_proto_init() }
@@ -624,13 +2549,14 @@ func file_user_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_proto_rawDesc), len(file_user_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   11,
+			NumEnums:      2,
+			NumMessages:   36,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   3,
 		},
 		GoTypes:           file_user_proto_goTypes,
 		DependencyIndexes: file_user_proto_depIdxs,
+		EnumInfos:         file_user_proto_enumTypes,
 		MessageInfos:      file_user_proto_msgTypes,
 	}.Build()
 	File_user_proto = out.File
@@ -19,24 +19,37 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UserService_GetUserProducts_FullMethodName      = "/user.UserService/GetUserProducts"
-	UserService_GetUserPreference_FullMethodName    = "/user.UserService/GetUserPreference"
-	UserService_AddUserProduct_FullMethodName       = "/user.UserService/AddUserProduct"
-	UserService_RemoveUserProduct_FullMethodName    = "/user.UserService/RemoveUserProduct"
-	UserService_UpdateUserPreference_FullMethodName = "/user.UserService/UpdateUserPreference"
-	UserService_RemoveUserPreference_FullMethodName = "/user.UserService/RemoveUserPreference"
+	UserService_GetUserProducts_FullMethodName           = "/user.UserService/GetUserProducts"
+	UserService_GetUserPreference_FullMethodName         = "/user.UserService/GetUserPreference"
+	UserService_AddUserProduct_FullMethodName            = "/user.UserService/AddUserProduct"
+	UserService_RemoveUserProduct_FullMethodName         = "/user.UserService/RemoveUserProduct"
+	UserService_UpdateUserPreference_FullMethodName      = "/user.UserService/UpdateUserPreference"
+	UserService_RemoveUserPreference_FullMethodName      = "/user.UserService/RemoveUserPreference"
+	UserService_AddUserPreferenceEntry_FullMethodName    = "/user.UserService/AddUserPreferenceEntry"
+	UserService_RemoveUserPreferenceEntry_FullMethodName = "/user.UserService/RemoveUserPreferenceEntry"
+	UserService_BatchAddUserProducts_FullMethodName      = "/user.UserService/BatchAddUserProducts"
+	UserService_BatchRemoveUserProducts_FullMethodName   = "/user.UserService/BatchRemoveUserProducts"
 )
 
 // UserServiceClient is the client API for UserService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Токен доступа передается в метаданных "authorization: Bearer <token>"
+// (в REST/JSON - в заголовке Authorization).
+// Поле access_token в сообщениях поддерживается на время перехода клиентов
+// и используется, только если метаданных нет.
 type UserServiceClient interface {
-	GetUserProducts(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*GetProductsResponse, error)
+	GetUserProducts(ctx context.Context, in *GetProductsRequest, opts ...grpc.CallOption) (*GetProductsResponse, error)
 	GetUserPreference(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*GetPreferenceResponse, error)
 	AddUserProduct(ctx context.Context, in *AddProductRequest, opts ...grpc.CallOption) (*AddProductResponse, error)
 	RemoveUserProduct(ctx context.Context, in *RemoveProductRequest, opts ...grpc.CallOption) (*RemoveProductResponse, error)
 	UpdateUserPreference(ctx context.Context, in *UpdatePreferenceRequest, opts ...grpc.CallOption) (*UpdatePreferenceResponse, error)
 	RemoveUserPreference(ctx context.Context, in *RemovePreferenceRequest, opts ...grpc.CallOption) (*RemovePreferenceResponse, error)
+	AddUserPreferenceEntry(ctx context.Context, in *PreferenceEntryRequest, opts ...grpc.CallOption) (*PreferenceEntryResponse, error)
+	RemoveUserPreferenceEntry(ctx context.Context, in *PreferenceEntryRequest, opts ...grpc.CallOption) (*PreferenceEntryResponse, error)
+	BatchAddUserProducts(ctx context.Context, in *BatchAddProductsRequest, opts ...grpc.CallOption) (*BatchAddProductsResponse, error)
+	BatchRemoveUserProducts(ctx context.Context, in *BatchRemoveProductsRequest, opts ...grpc.CallOption) (*BatchRemoveProductsResponse, error)
 }
 
 type userServiceClient struct {
@@ -47,7 +60,7 @@ func NewUserServiceClient(cc grpc.ClientConnInterface) UserServiceClient {
 	return &userServiceClient{cc}
 }
 
-func (c *userServiceClient) GetUserProducts(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*GetProductsResponse, error) {
+func (c *userServiceClient) GetUserProducts(ctx context.Context, in *GetProductsRequest, opts ...grpc.CallOption) (*GetProductsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetProductsResponse)
 	err := c.cc.Invoke(ctx, UserService_GetUserProducts_FullMethodName, in, out, cOpts...)
@@ -107,16 +120,65 @@ func (c *userServiceClient) RemoveUserPreference(ctx context.Context, in *Remove
 	return out, nil
 }
 
+func (c *userServiceClient) AddUserPreferenceEntry(ctx context.Context, in *PreferenceEntryRequest, opts ...grpc.CallOption) (*PreferenceEntryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PreferenceEntryResponse)
+	err := c.cc.Invoke(ctx, UserService_AddUserPreferenceEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RemoveUserPreferenceEntry(ctx context.Context, in *PreferenceEntryRequest, opts ...grpc.CallOption) (*PreferenceEntryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PreferenceEntryResponse)
+	err := c.cc.Invoke(ctx, UserService_RemoveUserPreferenceEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) BatchAddUserProducts(ctx context.Context, in *BatchAddProductsRequest, opts ...grpc.CallOption) (*BatchAddProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchAddProductsResponse)
+	err := c.cc.Invoke(ctx, UserService_BatchAddUserProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) BatchRemoveUserProducts(ctx context.Context, in *BatchRemoveProductsRequest, opts ...grpc.CallOption) (*BatchRemoveProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchRemoveProductsResponse)
+	err := c.cc.Invoke(ctx, UserService_BatchRemoveUserProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
+//
+// Токен доступа передается в метаданных "authorization: Bearer <token>"
+// (в REST/JSON - в заголовке Authorization).
+// Поле access_token в сообщениях поддерживается на время перехода клиентов
+// и используется, только если метаданных нет.
 type UserServiceServer interface {
-	GetUserProducts(context.Context, *UserRequest) (*GetProductsResponse, error)
+	GetUserProducts(context.Context, *GetProductsRequest) (*GetProductsResponse, error)
 	GetUserPreference(context.Context, *UserRequest) (*GetPreferenceResponse, error)
 	AddUserProduct(context.Context, *AddProductRequest) (*AddProductResponse, error)
 	RemoveUserProduct(context.Context, *RemoveProductRequest) (*RemoveProductResponse, error)
 	UpdateUserPreference(context.Context, *UpdatePreferenceRequest) (*UpdatePreferenceResponse, error)
 	RemoveUserPreference(context.Context, *RemovePreferenceRequest) (*RemovePreferenceResponse, error)
+	AddUserPreferenceEntry(context.Context, *PreferenceEntryRequest) (*PreferenceEntryResponse, error)
+	RemoveUserPreferenceEntry(context.Context, *PreferenceEntryRequest) (*PreferenceEntryResponse, error)
+	BatchAddUserProducts(context.Context, *BatchAddProductsRequest) (*BatchAddProductsResponse, error)
+	BatchRemoveUserProducts(context.Context, *BatchRemoveProductsRequest) (*BatchRemoveProductsResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -127,7 +189,7 @@ type UserServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedUserServiceServer struct{}
 
-func (UnimplementedUserServiceServer) GetUserProducts(context.Context, *UserRequest) (*GetProductsResponse, error) {
+func (UnimplementedUserServiceServer) GetUserProducts(context.Context, *GetProductsRequest) (*GetProductsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserProducts not implemented")
 }
 func (UnimplementedUserServiceServer) GetUserPreference(context.Context, *UserRequest) (*GetPreferenceResponse, error) {
@@ -145,6 +207,18 @@ func (UnimplementedUserServiceServer) UpdateUserPreference(context.Context, *Upd
 func (UnimplementedUserServiceServer) RemoveUserPreference(context.Context, *RemovePreferenceRequest) (*RemovePreferenceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveUserPreference not implemented")
 }
+func (UnimplementedUserServiceServer) AddUserPreferenceEntry(context.Context, *PreferenceEntryRequest) (*PreferenceEntryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddUserPreferenceEntry not implemented")
+}
+func (UnimplementedUserServiceServer) RemoveUserPreferenceEntry(context.Context, *PreferenceEntryRequest) (*PreferenceEntryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveUserPreferenceEntry not implemented")
+}
+func (UnimplementedUserServiceServer) BatchAddUserProducts(context.Context, *BatchAddProductsRequest) (*BatchAddProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchAddUserProducts not implemented")
+}
+func (UnimplementedUserServiceServer) BatchRemoveUserProducts(context.Context, *BatchRemoveProductsRequest) (*BatchRemoveProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchRemoveUserProducts not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -167,7 +241,7 @@ func RegisterUserServiceServer(s grpc.ServiceRegistrar, srv UserServiceServer) {
 }
 
 func _UserService_GetUserProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UserRequest)
+	in := new(GetProductsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -179,7 +253,7 @@ func _UserService_GetUserProducts_Handler(srv interface{}, ctx context.Context,
 		FullMethod: UserService_GetUserProducts_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServiceServer).GetUserProducts(ctx, req.(*UserRequest))
+		return srv.(UserServiceServer).GetUserProducts(ctx, req.(*GetProductsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -274,6 +348,78 @@ func _UserService_RemoveUserPreference_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_AddUserPreferenceEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreferenceEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).AddUserPreferenceEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_AddUserPreferenceEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).AddUserPreferenceEntry(ctx, req.(*PreferenceEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RemoveUserPreferenceEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreferenceEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RemoveUserPreferenceEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RemoveUserPreferenceEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RemoveUserPreferenceEntry(ctx, req.(*PreferenceEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_BatchAddUserProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchAddProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).BatchAddUserProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_BatchAddUserProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).BatchAddUserProducts(ctx, req.(*BatchAddProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_BatchRemoveUserProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchRemoveProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).BatchRemoveUserProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_BatchRemoveUserProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).BatchRemoveUserProducts(ctx, req.(*BatchRemoveProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -305,6 +451,353 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveUserPreference",
 			Handler:    _UserService_RemoveUserPreference_Handler,
 		},
+		{
+			MethodName: "AddUserPreferenceEntry",
+			Handler:    _UserService_AddUserPreferenceEntry_Handler,
+		},
+		{
+			MethodName: "RemoveUserPreferenceEntry",
+			Handler:    _UserService_RemoveUserPreferenceEntry_Handler,
+		},
+		{
+			MethodName: "BatchAddUserProducts",
+			Handler:    _UserService_BatchAddUserProducts_Handler,
+		},
+		{
+			MethodName: "BatchRemoveUserProducts",
+			Handler:    _UserService_BatchRemoveUserProducts_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "user.proto",
+}
+
+const (
+	CatalogService_UpsertCatalogProduct_FullMethodName = "/user.CatalogService/UpsertCatalogProduct"
+	CatalogService_DeleteCatalogProduct_FullMethodName = "/user.CatalogService/DeleteCatalogProduct"
+	CatalogService_ListCatalogProducts_FullMethodName  = "/user.CatalogService/ListCatalogProducts"
+)
+
+// CatalogServiceClient is the client API for CatalogService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Административный сервис ведения каталога продуктов.
+// Требует ключ из admin.api_key в метаданных x-api-key.
+type CatalogServiceClient interface {
+	UpsertCatalogProduct(ctx context.Context, in *UpsertCatalogProductRequest, opts ...grpc.CallOption) (*UpsertCatalogProductResponse, error)
+	DeleteCatalogProduct(ctx context.Context, in *DeleteCatalogProductRequest, opts ...grpc.CallOption) (*DeleteCatalogProductResponse, error)
+	ListCatalogProducts(ctx context.Context, in *ListCatalogProductsRequest, opts ...grpc.CallOption) (*ListCatalogProductsResponse, error)
+}
+
+type catalogServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCatalogServiceClient(cc grpc.ClientConnInterface) CatalogServiceClient {
+	return &catalogServiceClient{cc}
+}
+
+func (c *catalogServiceClient) UpsertCatalogProduct(ctx context.Context, in *UpsertCatalogProductRequest, opts ...grpc.CallOption) (*UpsertCatalogProductResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpsertCatalogProductResponse)
+	err := c.cc.Invoke(ctx, CatalogService_UpsertCatalogProduct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) DeleteCatalogProduct(ctx context.Context, in *DeleteCatalogProductRequest, opts ...grpc.CallOption) (*DeleteCatalogProductResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteCatalogProductResponse)
+	err := c.cc.Invoke(ctx, CatalogService_DeleteCatalogProduct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) ListCatalogProducts(ctx context.Context, in *ListCatalogProductsRequest, opts ...grpc.CallOption) (*ListCatalogProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCatalogProductsResponse)
+	err := c.cc.Invoke(ctx, CatalogService_ListCatalogProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CatalogServiceServer is the server API for CatalogService service.
+// All implementations must embed UnimplementedCatalogServiceServer
+// for forward compatibility.
+//
+// Административный сервис ведения каталога продуктов.
+// Требует ключ из admin.api_key в метаданных x-api-key.
+type CatalogServiceServer interface {
+	UpsertCatalogProduct(context.Context, *UpsertCatalogProductRequest) (*UpsertCatalogProductResponse, error)
+	DeleteCatalogProduct(context.Context, *DeleteCatalogProductRequest) (*DeleteCatalogProductResponse, error)
+	ListCatalogProducts(context.Context, *ListCatalogProductsRequest) (*ListCatalogProductsResponse, error)
+	mustEmbedUnimplementedCatalogServiceServer()
+}
+
+// UnimplementedCatalogServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCatalogServiceServer struct{}
+
+func (UnimplementedCatalogServiceServer) UpsertCatalogProduct(context.Context, *UpsertCatalogProductRequest) (*UpsertCatalogProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertCatalogProduct not implemented")
+}
+func (UnimplementedCatalogServiceServer) DeleteCatalogProduct(context.Context, *DeleteCatalogProductRequest) (*DeleteCatalogProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCatalogProduct not implemented")
+}
+func (UnimplementedCatalogServiceServer) ListCatalogProducts(context.Context, *ListCatalogProductsRequest) (*ListCatalogProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCatalogProducts not implemented")
+}
+func (UnimplementedCatalogServiceServer) mustEmbedUnimplementedCatalogServiceServer() {}
+func (UnimplementedCatalogServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeCatalogServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CatalogServiceServer will
+// result in compilation errors.
+type UnsafeCatalogServiceServer interface {
+	mustEmbedUnimplementedCatalogServiceServer()
+}
+
+func RegisterCatalogServiceServer(s grpc.ServiceRegistrar, srv CatalogServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCatalogServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CatalogService_ServiceDesc, srv)
+}
+
+func _CatalogService_UpsertCatalogProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertCatalogProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).UpsertCatalogProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_UpsertCatalogProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).UpsertCatalogProduct(ctx, req.(*UpsertCatalogProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_DeleteCatalogProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCatalogProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).DeleteCatalogProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_DeleteCatalogProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).DeleteCatalogProduct(ctx, req.(*DeleteCatalogProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_ListCatalogProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCatalogProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).ListCatalogProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_ListCatalogProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).ListCatalogProducts(ctx, req.(*ListCatalogProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CatalogService_ServiceDesc is the grpc.ServiceDesc for CatalogService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CatalogService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "user.CatalogService",
+	HandlerType: (*CatalogServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "UpsertCatalogProduct",
+			Handler:    _CatalogService_UpsertCatalogProduct_Handler,
+		},
+		{
+			MethodName: "DeleteCatalogProduct",
+			Handler:    _CatalogService_DeleteCatalogProduct_Handler,
+		},
+		{
+			MethodName: "ListCatalogProducts",
+			Handler:    _CatalogService_ListCatalogProducts_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "user.proto",
+}
+
+const (
+	TokenRevocationService_RevokeToken_FullMethodName      = "/user.TokenRevocationService/RevokeToken"
+	TokenRevocationService_RevokeUserTokens_FullMethodName = "/user.TokenRevocationService/RevokeUserTokens"
+)
+
+// TokenRevocationServiceClient is the client API for TokenRevocationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Внутренний сервис отзыва токенов доступа, вызывается сервисом авторизации.
+// Требует ключ из internal.api_key в метаданных x-api-key.
+type TokenRevocationServiceClient interface {
+	RevokeToken(ctx context.Context, in *RevokeTokenRequest, opts ...grpc.CallOption) (*RevokeTokenResponse, error)
+	RevokeUserTokens(ctx context.Context, in *RevokeUserTokensRequest, opts ...grpc.CallOption) (*RevokeUserTokensResponse, error)
+}
+
+type tokenRevocationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTokenRevocationServiceClient(cc grpc.ClientConnInterface) TokenRevocationServiceClient {
+	return &tokenRevocationServiceClient{cc}
+}
+
+func (c *tokenRevocationServiceClient) RevokeToken(ctx context.Context, in *RevokeTokenRequest, opts ...grpc.CallOption) (*RevokeTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeTokenResponse)
+	err := c.cc.Invoke(ctx, TokenRevocationService_RevokeToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tokenRevocationServiceClient) RevokeUserTokens(ctx context.Context, in *RevokeUserTokensRequest, opts ...grpc.CallOption) (*RevokeUserTokensResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeUserTokensResponse)
+	err := c.cc.Invoke(ctx, TokenRevocationService_RevokeUserTokens_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TokenRevocationServiceServer is the server API for TokenRevocationService service.
+// All implementations must embed UnimplementedTokenRevocationServiceServer
+// for forward compatibility.
+//
+// Внутренний сервис отзыва токенов доступа, вызывается сервисом авторизации.
+// Требует ключ из internal.api_key в метаданных x-api-key.
+type TokenRevocationServiceServer interface {
+	RevokeToken(context.Context, *RevokeTokenRequest) (*RevokeTokenResponse, error)
+	RevokeUserTokens(context.Context, *RevokeUserTokensRequest) (*RevokeUserTokensResponse, error)
+	mustEmbedUnimplementedTokenRevocationServiceServer()
+}
+
+// UnimplementedTokenRevocationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTokenRevocationServiceServer struct{}
+
+func (UnimplementedTokenRevocationServiceServer) RevokeToken(context.Context, *RevokeTokenRequest) (*RevokeTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeToken not implemented")
+}
+func (UnimplementedTokenRevocationServiceServer) RevokeUserTokens(context.Context, *RevokeUserTokensRequest) (*RevokeUserTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeUserTokens not implemented")
+}
+func (UnimplementedTokenRevocationServiceServer) mustEmbedUnimplementedTokenRevocationServiceServer() {
+}
+func (UnimplementedTokenRevocationServiceServer) testEmbeddedByValue() {}
+
+// UnsafeTokenRevocationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TokenRevocationServiceServer will
+// result in compilation errors.
+type UnsafeTokenRevocationServiceServer interface {
+	mustEmbedUnimplementedTokenRevocationServiceServer()
+}
+
+func RegisterTokenRevocationServiceServer(s grpc.ServiceRegistrar, srv TokenRevocationServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTokenRevocationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TokenRevocationService_ServiceDesc, srv)
+}
+
+func _TokenRevocationService_RevokeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenRevocationServiceServer).RevokeToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TokenRevocationService_RevokeToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenRevocationServiceServer).RevokeToken(ctx, req.(*RevokeTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TokenRevocationService_RevokeUserTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeUserTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenRevocationServiceServer).RevokeUserTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TokenRevocationService_RevokeUserTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenRevocationServiceServer).RevokeUserTokens(ctx, req.(*RevokeUserTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TokenRevocationService_ServiceDesc is the grpc.ServiceDesc for TokenRevocationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TokenRevocationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "user.TokenRevocationService",
+	HandlerType: (*TokenRevocationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RevokeToken",
+			Handler:    _TokenRevocationService_RevokeToken_Handler,
+		},
+		{
+			MethodName: "RevokeUserTokens",
+			Handler:    _TokenRevocationService_RevokeUserTokens_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "user.proto",
@@ -0,0 +1,65 @@
+-- Роли сервиса. Скрипт выполняет суперпользователь в базе сервиса до первого `migrate up`,
+-- повторный запуск ничего не ломает:
+--   psql -h localhost -p 5433 -U postgres -d postgres -f init-scripts/roles.sql \
+--        -v app_password=... -v admin_password=...
+--
+-- user_service_admin       - владелец схемы: применяет миграции, от его имени работают
+--                            cmd/catalog и migrate backfill (postgres.pg_admin_user)
+-- user_service_app         - роль сервиса (postgres.pg_user): без SUPERUSER и BYPASSRLS,
+--                            иначе политики RLS не действуют и сервис не запустится
+-- user_service_maintenance - видит данные всех пользователей в обход RLS, войти под ней нельзя;
+--                            административные инструменты переключаются на нее через SET ROLE
+--                            (postgres.maintenance_role), поэтому она выдается user_service_admin
+
+\set ON_ERROR_STOP on
+
+\if :{?app_password}
+\else
+\set app_password app_password
+\endif
+\if :{?admin_password}
+\else
+\set admin_password admin_password
+\endif
+
+SELECT format('CREATE ROLE %I LOGIN', r)
+FROM unnest(ARRAY['user_service_admin', 'user_service_app']) AS r
+WHERE NOT EXISTS (SELECT FROM pg_roles WHERE rolname = r)
+\gexec
+SELECT 'CREATE ROLE user_service_maintenance NOLOGIN BYPASSRLS'
+WHERE NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'user_service_maintenance')
+\gexec
+
+ALTER ROLE user_service_admin NOSUPERUSER NOBYPASSRLS PASSWORD :'admin_password';
+ALTER ROLE user_service_app NOSUPERUSER NOBYPASSRLS PASSWORD :'app_password';
+ALTER ROLE user_service_maintenance NOLOGIN BYPASSRLS;
+GRANT user_service_maintenance TO user_service_admin;
+
+GRANT USAGE, CREATE ON SCHEMA public TO user_service_admin;
+GRANT USAGE ON SCHEMA public TO user_service_app, user_service_maintenance;
+
+-- Схема, созданная миграциями от имени другой роли, передается владельцу
+SELECT format('ALTER %s %I.%I OWNER TO user_service_admin',
+              CASE c.relkind WHEN 'S' THEN 'SEQUENCE' ELSE 'TABLE' END, n.nspname, c.relname)
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = 'public' AND c.relkind IN ('r', 'p', 'S')
+  AND pg_get_userbyid(c.relowner) <> 'user_service_admin'
+  -- последовательности колонок SERIAL меняют владельца вместе с таблицей, объекты расширений не трогаются
+  AND NOT EXISTS (SELECT FROM pg_depend d WHERE d.objid = c.oid AND d.deptype IN ('a', 'i', 'e'))
+\gexec
+SELECT format('ALTER FUNCTION %s OWNER TO user_service_admin', p.oid::regprocedure)
+FROM pg_proc p
+JOIN pg_namespace n ON n.oid = p.pronamespace
+WHERE n.nspname = 'public' AND pg_get_userbyid(p.proowner) <> 'user_service_admin'
+  AND NOT EXISTS (SELECT FROM pg_depend d WHERE d.objid = p.oid AND d.deptype = 'e')
+\gexec
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public
+    TO user_service_app, user_service_maintenance;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public
+    TO user_service_app, user_service_maintenance;
+ALTER DEFAULT PRIVILEGES FOR ROLE user_service_admin IN SCHEMA public
+    GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO user_service_app, user_service_maintenance;
+ALTER DEFAULT PRIVILEGES FOR ROLE user_service_admin IN SCHEMA public
+    GRANT USAGE, SELECT ON SEQUENCES TO user_service_app, user_service_maintenance;
\ No newline at end of file
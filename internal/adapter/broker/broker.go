@@ -0,0 +1,41 @@
+// Package broker публикует доменные события из outbox в брокер сообщений: Kafka, NATS JetStream
+// или, для локального запуска, в файл или stdout.
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"user-service/internal/entity"
+)
+
+// Publisher - брокер, в который relay публикует события
+type Publisher interface {
+	// Publish - публикует события по порядку и возвращает, сколько первых событий опубликовано.
+	// События одного пользователя должны доставляться подписчикам в порядке публикации.
+	Publish(ctx context.Context, events []entity.Event) (int, error)
+	// Close - закрывает соединение с брокером
+	Close() error
+}
+
+// message - событие в том виде, в котором его получают подписчики
+type message struct {
+	// ID - порядковый номер события; при повторной доставке он тот же, по нему отбрасываются дубликаты
+	ID         int64            `json:"id"`
+	Type       entity.EventType `json:"type"`
+	UserID     string           `json:"user_id"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Payload    json.RawMessage  `json:"payload"`
+}
+
+// encode - тело сообщения с событием
+func encode(event entity.Event) ([]byte, error) {
+	return json.Marshal(message{
+		ID:         event.ID,
+		Type:       event.Type,
+		UserID:     event.UserID,
+		OccurredAt: event.CreatedAt,
+		Payload:    event.Payload,
+	})
+}
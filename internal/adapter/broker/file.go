@@ -0,0 +1,61 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"user-service/internal/entity"
+)
+
+// writerPublisher - пишет события в файл или stdout по одному JSON-объекту на строку
+type writerPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+	// file - открытый файл, nil для stdout
+	file *os.File
+}
+
+// NewStdout - публикует события в stdout
+func NewStdout() *writerPublisher {
+	return &writerPublisher{w: os.Stdout}
+}
+
+// NewFile - дописывает события в конец файла path
+func NewFile(path string) (*writerPublisher, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open events file: %w", err)
+	}
+	return &writerPublisher{w: file, file: file}, nil
+}
+
+func (p *writerPublisher) Publish(_ context.Context, events []entity.Event) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, event := range events {
+		data, err := encode(event)
+		if err != nil {
+			return i, err
+		}
+		if _, err := p.w.Write(append(data, '\n')); err != nil {
+			return i, err
+		}
+	}
+	// Событие считается опубликованным, только когда оно записано на диск
+	if p.file != nil {
+		if err := p.file.Sync(); err != nil {
+			return 0, err
+		}
+	}
+	return len(events), nil
+}
+
+func (p *writerPublisher) Close() error {
+	if p.file == nil {
+		return nil
+	}
+	return p.file.Close()
+}
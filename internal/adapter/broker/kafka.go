@@ -0,0 +1,64 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"user-service/config"
+	"user-service/internal/entity"
+)
+
+// kafkaPublisher - публикует события в топик Kafka с ключом user_id
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewKafka - создает продюсер Kafka, соединения открываются при первой публикации
+func NewKafka(cfg config.KafkaConfig) *kafkaPublisher {
+	return &kafkaPublisher{writer: &kafka.Writer{
+		Addr:  kafka.TCP(cfg.Brokers...),
+		Topic: cfg.Topic,
+		// События одного пользователя попадают в одну партицию и читаются в порядке публикации
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		// Relay передает события пачкой, ждать наполнения пачки не нужно
+		BatchTimeout: 10 * time.Millisecond,
+	}}
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, events []entity.Event) (int, error) {
+	messages := make([]kafka.Message, len(events))
+	for i, event := range events {
+		value, err := encode(event)
+		if err != nil {
+			return 0, err
+		}
+		messages[i] = kafka.Message{
+			Key:     []byte(event.UserID),
+			Value:   value,
+			Headers: []kafka.Header{{Key: "type", Value: []byte(event.Type)}},
+		}
+	}
+	err := p.writer.WriteMessages(ctx, messages...)
+	if err == nil {
+		return len(events), nil
+	}
+	// Опубликованными считаются события до первого неудачного,
+	// остальные будут опубликованы повторно
+	var writeErrs kafka.WriteErrors
+	if errors.As(err, &writeErrs) {
+		for i, writeErr := range writeErrs {
+			if writeErr != nil {
+				return i, err
+			}
+		}
+	}
+	return 0, err
+}
+
+func (p *kafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,56 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/nats-io/nats.go"
+
+	"user-service/config"
+	"user-service/internal/entity"
+)
+
+// natsPublisher - публикует события в NATS JetStream в субъект <subject>.<тип события>.
+// Stream, принимающий эти субъекты, создается заранее.
+type natsPublisher struct {
+	conn    *nats.Conn
+	js      nats.JetStreamContext
+	subject string
+}
+
+// NewNATS - подключается к NATS
+func NewNATS(cfg config.NATSConfig) (*natsPublisher, error) {
+	conn, err := nats.Connect(cfg.URL, nats.Name("user-service"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats %s: %w", cfg.URL, err)
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open jetstream: %w", err)
+	}
+	return &natsPublisher{conn: conn, js: js, subject: cfg.Subject}, nil
+}
+
+// Publish - публикует события по одному и ждет подтверждения JetStream.
+// Nats-Msg-Id позволяет JetStream отбросить повторную публикацию того же события.
+func (p *natsPublisher) Publish(ctx context.Context, events []entity.Event) (int, error) {
+	for i, event := range events {
+		data, err := encode(event)
+		if err != nil {
+			return i, err
+		}
+		msg := nats.NewMsg(p.subject + "." + string(event.Type))
+		msg.Data = data
+		msg.Header.Set(nats.MsgIdHdr, strconv.FormatInt(event.ID, 10))
+		if _, err := p.js.PublishMsg(msg, nats.Context(ctx)); err != nil {
+			return i, err
+		}
+	}
+	return len(events), nil
+}
+
+func (p *natsPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,20 @@
+// Package cache реализует хранилища кэша: LRU в памяти процесса и Redis.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache - хранилище кэша. Значения лежат в полях ключа: ключ объединяет значения,
+// которые сбрасываются вместе (например, все страницы продуктов пользователя).
+type Cache interface {
+	// Get - значение поля field ключа key; ok=false, если его нет или оно истекло
+	Get(ctx context.Context, key string, field string) (value []byte, ok bool, err error)
+	// Set - сохраняет значение поля field ключа key, оно живет не дольше ttl
+	Set(ctx context.Context, key string, field string, value []byte, ttl time.Duration) error
+	// Delete - удаляет ключи со всеми их полями
+	Delete(ctx context.Context, keys ...string) error
+	// Clear - удаляет все значения кэша
+	Clear(ctx context.Context) error
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// lru - кэш в памяти процесса, при переполнении вытесняются давно не читавшиеся значения
+type lru struct {
+	// size - максимальное количество значений (полей всех ключей)
+	size int
+
+	mu sync.Mutex
+	// order - значения от недавно использованных к давно использованным
+	order *list.List
+	// keys - элементы order по ключу и полю
+	keys map[string]map[string]*list.Element
+}
+
+// lruEntry - значение в списке order
+type lruEntry struct {
+	key       string
+	field     string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRU - создает кэш в памяти не больше чем на size значений
+func NewLRU(size int) *lru {
+	return &lru{
+		size:  max(size, 1),
+		order: list.New(),
+		keys:  make(map[string]map[string]*list.Element),
+	}
+}
+
+func (c *lru) Get(_ context.Context, key string, field string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.keys[key][field]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.remove(element)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+func (c *lru) Set(_ context.Context, key string, field string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiresAt := time.Now().Add(ttl)
+	if element, ok := c.keys[key][field]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	fields, ok := c.keys[key]
+	if !ok {
+		fields = make(map[string]*list.Element)
+		c.keys[key] = fields
+	}
+	fields[field] = c.order.PushFront(&lruEntry{key: key, field: field, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *lru) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		for _, element := range c.keys[key] {
+			c.order.Remove(element)
+		}
+		delete(c.keys, key)
+	}
+	return nil
+}
+
+func (c *lru) Clear(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.keys = make(map[string]map[string]*list.Element)
+	return nil
+}
+
+// remove - удаляет значение из order и keys, пустой ключ удаляется целиком
+func (c *lru) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*lruEntry)
+	fields := c.keys[entry.key]
+	delete(fields, entry.field)
+	if len(fields) == 0 {
+		delete(c.keys, entry.key)
+	}
+}
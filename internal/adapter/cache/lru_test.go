@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	value := []byte("v")
+
+	// present - какие пары ключ/поле есть в кэше
+	present := func(t *testing.T, c *lru, entries ...[2]string) []bool {
+		t.Helper()
+		got := make([]bool, len(entries))
+		for i, entry := range entries {
+			_, ok, err := c.Get(ctx, entry[0], entry[1])
+			if err != nil {
+				t.Fatalf("Get(%s, %s): %v", entry[0], entry[1], err)
+			}
+			got[i] = ok
+		}
+		return got
+	}
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T, c *lru)
+	}{
+		{
+			name: "evicts least recently read value",
+			run: func(t *testing.T, c *lru) {
+				c.Set(ctx, "a", "1", value, time.Minute)
+				c.Set(ctx, "b", "1", value, time.Minute)
+				// Чтение делает "a" недавно использованным, поэтому вытесняется "b"
+				present(t, c, [2]string{"a", "1"})
+				c.Set(ctx, "c", "1", value, time.Minute)
+
+				got := present(t, c, [2]string{"a", "1"}, [2]string{"b", "1"}, [2]string{"c", "1"})
+				if want := []bool{true, false, true}; !slices.Equal(got, want) {
+					t.Errorf("present = %v, want %v", got, want)
+				}
+			},
+		},
+		{
+			name: "eviction removes empty key",
+			run: func(t *testing.T, c *lru) {
+				c.Set(ctx, "a", "1", value, time.Minute)
+				c.Set(ctx, "b", "1", value, time.Minute)
+				c.Set(ctx, "b", "2", value, time.Minute)
+				if _, ok := c.keys["a"]; ok {
+					t.Error("key without fields is kept after eviction")
+				}
+				if len(c.keys["b"]) != 2 || c.order.Len() != 2 {
+					t.Errorf("got %d fields of b and %d values, want 2 and 2", len(c.keys["b"]), c.order.Len())
+				}
+			},
+		},
+		{
+			name: "overwrite does not evict",
+			run: func(t *testing.T, c *lru) {
+				c.Set(ctx, "a", "1", value, time.Minute)
+				c.Set(ctx, "b", "1", value, time.Minute)
+				c.Set(ctx, "a", "1", []byte("new"), time.Minute)
+
+				got, ok, _ := c.Get(ctx, "a", "1")
+				if !ok || string(got) != "new" {
+					t.Errorf("Get(a, 1) = %q, %v, want new value", got, ok)
+				}
+				if !present(t, c, [2]string{"b", "1"})[0] {
+					t.Error("overwriting a value evicted another one")
+				}
+			},
+		},
+		{
+			name: "expired value is removed with its empty key",
+			run: func(t *testing.T, c *lru) {
+				c.Set(ctx, "a", "1", value, -time.Second)
+				if present(t, c, [2]string{"a", "1"})[0] {
+					t.Error("expired value is returned")
+				}
+				if _, ok := c.keys["a"]; ok || c.order.Len() != 0 {
+					t.Error("expired value is kept in the cache")
+				}
+			},
+		},
+		{
+			name: "delete removes all fields of the key",
+			run: func(t *testing.T, c *lru) {
+				c.Set(ctx, "a", "1", value, time.Minute)
+				c.Set(ctx, "a", "2", value, time.Minute)
+				if err := c.Delete(ctx, "a", "missing"); err != nil {
+					t.Fatalf("Delete: %v", err)
+				}
+				if got := present(t, c, [2]string{"a", "1"}, [2]string{"a", "2"}); got[0] || got[1] {
+					t.Errorf("present after Delete = %v", got)
+				}
+				if len(c.keys) != 0 || c.order.Len() != 0 {
+					t.Error("deleted values are kept in the cache")
+				}
+			},
+		},
+		{
+			name: "clear removes everything",
+			run: func(t *testing.T, c *lru) {
+				c.Set(ctx, "a", "1", value, time.Minute)
+				c.Set(ctx, "b", "1", value, time.Minute)
+				if err := c.Clear(ctx); err != nil {
+					t.Fatalf("Clear: %v", err)
+				}
+				if got := present(t, c, [2]string{"a", "1"}, [2]string{"b", "1"}); got[0] || got[1] {
+					t.Errorf("present after Clear = %v", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, NewLRU(2))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"user-service/config"
+)
+
+// redisKeyPrefix - префикс ключей сервиса в Redis
+const redisKeyPrefix = "user-service:cache:"
+
+// redisCache - кэш в Redis (или другом сервере с протоколом Redis), общий для всех реплик.
+// Ключ хранится как hash, его поля - значения. Срок жизни ставится ключу целиком
+// при первой записи (EXPIRE NX, нужен Redis 7), поэтому ни одно поле не живет дольше ttl.
+type redisCache struct {
+	client *redis.Client
+}
+
+// NewRedis - подключается к Redis и проверяет соединение
+func NewRedis(ctx context.Context, cfg config.RedisConfig) (*redisCache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis %s: %w", cfg.Addr, err)
+	}
+	return &redisCache{client: client}, nil
+}
+
+// Close - закрывает соединения с Redis
+func (c *redisCache) Close() error {
+	return c.client.Close()
+}
+
+func (c *redisCache) Get(ctx context.Context, key string, field string) ([]byte, bool, error) {
+	value, err := c.client.HGet(ctx, redisKeyPrefix+key, field).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, field string, value []byte, ttl time.Duration) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, redisKeyPrefix+key, field, value)
+		pipe.ExpireNX(ctx, redisKeyPrefix+key, ttl)
+		return nil
+	})
+	return err
+}
+
+func (c *redisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = redisKeyPrefix + key
+	}
+	return c.client.Del(ctx, prefixed...).Err()
+}
+
+// Clear - удаляет все ключи сервиса; перебирает их через SCAN, поэтому не блокирует Redis
+func (c *redisCache) Clear(ctx context.Context) error {
+	iter := c.client.Scan(ctx, 0, redisKeyPrefix+"*", 100).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == 100 {
+			if err := c.client.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
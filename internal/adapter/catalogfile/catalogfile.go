@@ -0,0 +1,124 @@
+package catalogfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"user-service/internal/entity"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported catalog file format")
+	ErrMissingColumn     = errors.New("missing canonical_name column")
+)
+
+const (
+	// aliasSeparator - разделитель синонимов в колонке aliases CSV-файла
+	aliasSeparator = "|"
+	// displayNamePrefix - префикс колонок с локализованными названиями в CSV-файле (name_ru, name_en)
+	displayNamePrefix = "name_"
+)
+
+// jsonProduct - запись каталога в JSON-файле
+type jsonProduct struct {
+	CanonicalName string            `json:"canonical_name"`
+	Category      string            `json:"category"`
+	DisplayNames  map[string]string `json:"display_names"`
+	Aliases       []string          `json:"aliases"`
+}
+
+// Load - читает записи каталога из JSON- или CSV-файла, формат определяется по расширению
+func Load(path string) ([]entity.CatalogProduct, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open catalog file: %w", err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ReadJSON(file)
+	case ".csv":
+		return ReadCSV(file)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, path)
+	}
+}
+
+// ReadJSON - читает массив записей каталога в формате JSON
+func ReadJSON(r io.Reader) ([]entity.CatalogProduct, error) {
+	var items []jsonProduct
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("failed to decode catalog json: %w", err)
+	}
+	products := make([]entity.CatalogProduct, 0, len(items))
+	for _, item := range items {
+		products = append(products, entity.CatalogProduct{
+			CanonicalName: item.CanonicalName,
+			Category:      item.Category,
+			DisplayNames:  item.DisplayNames,
+			Aliases:       item.Aliases,
+		})
+	}
+	return products, nil
+}
+
+// ReadCSV - читает записи каталога в формате CSV с заголовком.
+// Колонки: canonical_name, category, aliases (через "|") и name_<локаль> для локализованных названий.
+func ReadCSV(r io.Reader) ([]entity.CatalogProduct, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["canonical_name"]; !ok {
+		return nil, ErrMissingColumn
+	}
+
+	var products []entity.CatalogProduct
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read catalog csv line %d: %w", line, err)
+		}
+		product := entity.CatalogProduct{
+			CanonicalName: column(record, columns, "canonical_name"),
+			Category:      column(record, columns, "category"),
+			DisplayNames:  make(map[string]string),
+		}
+		if aliases := column(record, columns, "aliases"); aliases != "" {
+			product.Aliases = strings.Split(aliases, aliasSeparator)
+		}
+		for name, i := range columns {
+			if locale, ok := strings.CutPrefix(name, displayNamePrefix); ok && i < len(record) && record[i] != "" {
+				product.DisplayNames[locale] = record[i]
+			}
+		}
+		products = append(products, product)
+	}
+	return products, nil
+}
+
+// column - значение колонки по имени или пустая строка, если колонки нет
+func column(record []string, columns map[string]int, name string) string {
+	i, ok := columns[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return record[i]
+}
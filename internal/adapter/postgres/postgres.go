@@ -3,14 +3,64 @@ package postgres
 import (
 	"context"
 	"fmt"
-	"time"
+	"strconv"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"user-service/config"
 )
 
 // New - функция для создания подключения к базе данных
 func New(ctx context.Context, cfg config.Config) (*pgxpool.Pool, error) {
+	poolConfig, err := newPoolConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return connect(ctx, poolConfig)
+}
+
+// NewMaintenance - подключение для административных и служебных инструментов: соединения
+// открываются от имени postgres.pg_admin_user и переключаются на роль postgres.maintenance_role,
+// которая не проверяет политики RLS и видит данные всех пользователей
+func NewMaintenance(ctx context.Context, cfg config.Config) (*pgxpool.Pool, error) {
+	cfg.PG = cfg.PG.Admin()
+	poolConfig, err := newPoolConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if role := cfg.PG.MaintenanceRole; role != "" {
+		poolConfig.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+			if _, err := conn.Exec(ctx, "SET ROLE "+pgx.Identifier{role}.Sanitize()); err != nil {
+				return fmt.Errorf("failed to switch to maintenance role %s (is it granted to %s? see init-scripts/roles.sql): %w",
+					role, cfg.PG.User, err)
+			}
+			return nil
+		}
+	}
+	return connect(ctx, poolConfig)
+}
+
+// CheckRowLevelSecurity - проверяет, что политики RLS действуют для роли подключения:
+// суперпользователь и роль с BYPASSRLS видят данные всех пользователей
+func CheckRowLevelSecurity(ctx context.Context, db *pgxpool.Pool) error {
+	var (
+		role               string
+		super, bypassesRLS bool
+	)
+	err := db.QueryRow(ctx, `SELECT rolname, rolsuper, rolbypassrls FROM pg_roles WHERE rolname = current_user`).
+		Scan(&role, &super, &bypassesRLS)
+	if err != nil {
+		return fmt.Errorf("failed to check PostgreSQL role attributes: %w", err)
+	}
+	if super || bypassesRLS {
+		return fmt.Errorf("PostgreSQL role %s bypasses row level security (superuser: %t, bypassrls: %t), "+
+			"connect as a role without these attributes, see init-scripts/roles.sql", role, super, bypassesRLS)
+	}
+	return nil
+}
+
+// newPoolConfig - настройки пула соединений из конфигурации
+func newPoolConfig(cfg config.Config) (*pgxpool.Config, error) {
 	url := cfg.PG.Url()
 
 	poolConfig, err := pgxpool.ParseConfig(url)
@@ -19,8 +69,20 @@ func New(ctx context.Context, cfg config.Config) (*pgxpool.Pool, error) {
 	}
 
 	poolConfig.MaxConns = cfg.PG.MaxConns
-	poolConfig.ConnConfig.ConnectTimeout = cfg.PG.ConnTimeout * time.Second
+	poolConfig.ConnConfig.ConnectTimeout = cfg.PG.ConnTimeout
+	// Сервер сам отменяет слишком долгие запросы, чтобы они не занимали соединения пула;
+	// запросы, чей контекст отменен раньше, pgx отменяет сам
+	if cfg.PG.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.PG.StatementTimeout.Milliseconds(), 10)
+	}
+
+	// Спаны на ожидание соединения и на запросы; без включенной трассировки они ничего не стоят
+	poolConfig.ConnConfig.Tracer = newTracer()
+	return poolConfig, nil
+}
 
+// connect - создает пул соединений
+func connect(ctx context.Context, poolConfig *pgxpool.Config) (*pgxpool.Pool, error) {
 	db, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create PostgreSQL connection pool: %w", err)
@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ Limiter = (*memoryLimiter)(nil)
+
+// bucket - состояние одной корзины
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// memoryLimiter - корзины в памяти процесса, лимиты действуют в пределах одной реплики
+type memoryLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewMemory - создает лимитер с корзинами в памяти
+func NewMemory() *memoryLimiter {
+	return &memoryLimiter{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func (l *memoryLimiter) Allow(_ context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	if limit.Unlimited() {
+		return true, 0, nil
+	}
+	now := time.Now()
+	capacity := limit.capacity()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(capacity, b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	b.updated = now
+	if b.tokens < 1 {
+		return false, limit.retryAfter(b.tokens), nil
+	}
+	b.tokens--
+	return true, 0, nil
+}
+
+func (l *memoryLimiter) Refund(_ context.Context, key string, limit Limit) error {
+	if limit.Unlimited() {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[key]; ok {
+		b.tokens = min(limit.capacity(), b.tokens+1)
+	}
+	return nil
+}
+
+func (l *memoryLimiter) Run(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cutoff := time.Now().Add(-idleTTL)
+			l.mu.Lock()
+			for key, b := range l.buckets {
+				if b.updated.Before(cutoff) {
+					delete(l.buckets, key)
+				}
+			}
+			l.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ Limiter = (*postgresLimiter)(nil)
+
+// postgresLimiter - корзины в таблице rate_limit_buckets, лимиты общие для всех реплик.
+// Каждая проверка - один запрос к Postgres, поэтому режим подходит для небольшой нагрузки.
+type postgresLimiter struct {
+	db  *pgxpool.Pool
+	log *slog.Logger
+}
+
+// NewPostgres - создает лимитер с корзинами в Postgres
+func NewPostgres(db *pgxpool.Pool, log *slog.Logger) *postgresLimiter {
+	return &postgresLimiter{
+		db:  db,
+		log: log,
+	}
+}
+
+// refillExpr - количество токенов в корзине с учетом пополнения с момента updated_at
+const refillExpr = `LEAST($3::float8, b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at) * $2::float8)`
+
+func (l *postgresLimiter) Allow(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	if limit.Unlimited() {
+		return true, 0, nil
+	}
+	capacity := limit.capacity()
+
+	// Пополнение и списание выполняются одним запросом под блокировкой строки
+	query := `INSERT INTO rate_limit_buckets AS b (key, tokens, updated_at) VALUES ($1, $3::float8 - 1, clock_timestamp())
+		ON CONFLICT (key) DO UPDATE SET tokens = ` + refillExpr + ` - 1, updated_at = clock_timestamp()
+		WHERE ` + refillExpr + ` >= 1
+		RETURNING tokens`
+	var tokens float64
+	err := l.db.QueryRow(ctx, query, key, limit.Rate, capacity).Scan(&tokens)
+	if err == nil {
+		return true, 0, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return false, 0, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+
+	// Токенов нет, считаем, когда появится следующий
+	query = `SELECT ` + refillExpr + ` FROM rate_limit_buckets b WHERE b.key = $1`
+	if err := l.db.QueryRow(ctx, query, key, limit.Rate, capacity).Scan(&tokens); err != nil {
+		return false, 0, fmt.Errorf("failed to read rate limit bucket: %w", err)
+	}
+	return false, limit.retryAfter(tokens), nil
+}
+
+func (l *postgresLimiter) Refund(ctx context.Context, key string, limit Limit) error {
+	if limit.Unlimited() {
+		return nil
+	}
+	// Пополнение по времени учитывается при следующем Allow от прежнего updated_at
+	query := `UPDATE rate_limit_buckets SET tokens = LEAST($2::float8, tokens + 1) WHERE key = $1`
+	if _, err := l.db.Exec(ctx, query, key, limit.capacity()); err != nil {
+		return fmt.Errorf("failed to refund rate limit token: %w", err)
+	}
+	return nil
+}
+
+func (l *postgresLimiter) Run(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			query := `DELETE FROM rate_limit_buckets WHERE updated_at < clock_timestamp() - make_interval(secs => $1)`
+			if _, err := l.db.Exec(ctx, query, idleTTL.Seconds()); err != nil {
+				l.log.WarnContext(ctx, "Failed to remove idle rate limit buckets", "error", err)
+			}
+		}
+	}
+}
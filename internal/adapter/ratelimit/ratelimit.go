@@ -0,0 +1,68 @@
+// Package ratelimit реализует ограничение частоты запросов по алгоритму token bucket.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrRateLimited - ошибка, когда лимит запросов исчерпан
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RetryError - отказ из-за лимита с временем, через которое запрос стоит повторить
+type RetryError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%v, retry after %s", ErrRateLimited, e.RetryAfter)
+}
+
+func (e *RetryError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// Limit - параметры token bucket
+type Limit struct {
+	// Rate - скорость пополнения, запросов в секунду; 0 - без ограничения
+	Rate float64
+	// Burst - емкость корзины, сколько запросов можно сделать подряд
+	Burst int
+}
+
+// Unlimited - не ограничивает ли лимит запросы
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+// capacity - емкость корзины, не меньше одного запроса
+func (l Limit) capacity() float64 {
+	return math.Max(float64(l.Burst), 1)
+}
+
+// retryAfter - через сколько в корзине накопится один токен, если сейчас в ней tokens
+func (l Limit) retryAfter(tokens float64) time.Duration {
+	return time.Duration(math.Ceil((1 - tokens) / l.Rate * float64(time.Second)))
+}
+
+// Limiter - хранилище корзин
+type Limiter interface {
+	// Allow - списывает токен из корзины key. Если токенов нет, возвращает false
+	// и время, через которое запрос стоит повторить.
+	Allow(ctx context.Context, key string, limit Limit) (allowed bool, retryAfter time.Duration, err error)
+	// Refund - возвращает в корзину key токен, списанный Allow для запроса, который отклонила другая корзина
+	Refund(ctx context.Context, key string, limit Limit) error
+	// Run - удаляет давно не использовавшиеся корзины до отмены ctx
+	Run(ctx context.Context)
+}
+
+const (
+	// idleTTL - через сколько неиспользуемая корзина удаляется;
+	// при разумных лимитах к этому времени она успевает наполниться и неотличима от новой
+	idleTTL = time.Hour
+	// cleanupInterval - период удаления неиспользуемых корзин
+	cleanupInterval = 5 * time.Minute
+)
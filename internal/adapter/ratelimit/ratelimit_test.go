@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimitRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  Limit
+		tokens float64
+		want   time.Duration
+	}{
+		{name: "empty bucket", limit: Limit{Rate: 2, Burst: 5}, tokens: 0, want: 500 * time.Millisecond},
+		{name: "almost one token", limit: Limit{Rate: 1, Burst: 5}, tokens: 0.75, want: 250 * time.Millisecond},
+		{name: "slow rate", limit: Limit{Rate: 0.1, Burst: 1}, tokens: 0, want: 10 * time.Second},
+		{name: "rounded up to nanoseconds", limit: Limit{Rate: 3, Burst: 1}, tokens: 0, want: 333333334 * time.Nanosecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limit.retryAfter(tt.tokens); got != tt.want {
+				t.Errorf("retryAfter(%v) = %v, want %v", tt.tokens, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitCapacity(t *testing.T) {
+	tests := []struct {
+		limit Limit
+		want  float64
+	}{
+		{limit: Limit{Rate: 1, Burst: 10}, want: 10},
+		{limit: Limit{Rate: 1, Burst: 1}, want: 1},
+		// Без burst корзина все равно пропускает один запрос
+		{limit: Limit{Rate: 1, Burst: 0}, want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := tt.limit.capacity(); got != tt.want {
+			t.Errorf("capacity of %+v = %v, want %v", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryLimiterAllow(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		limit Limit
+		// calls - сколько раз подряд вызывается Allow
+		calls       int
+		wantAllowed int
+	}{
+		{name: "burst then rejected", limit: Limit{Rate: 0.001, Burst: 3}, calls: 5, wantAllowed: 3},
+		{name: "zero burst allows one", limit: Limit{Rate: 0.001}, calls: 3, wantAllowed: 1},
+		{name: "unlimited", limit: Limit{Rate: 0, Burst: 1}, calls: 100, wantAllowed: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewMemory()
+			allowed := 0
+			for range tt.calls {
+				ok, retryAfter, err := limiter.Allow(ctx, "key", tt.limit)
+				if err != nil {
+					t.Fatalf("Allow: %v", err)
+				}
+				if ok {
+					allowed++
+				} else if retryAfter <= 0 {
+					t.Errorf("rejected call has retry after %v", retryAfter)
+				}
+			}
+			if allowed != tt.wantAllowed {
+				t.Errorf("allowed %d calls, want %d", allowed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestMemoryLimiterRefill(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewMemory()
+	limit := Limit{Rate: 20, Burst: 1}
+
+	if ok, _, _ := limiter.Allow(ctx, "key", limit); !ok {
+		t.Fatal("first call rejected")
+	}
+	ok, retryAfter, _ := limiter.Allow(ctx, "key", limit)
+	if ok {
+		t.Fatal("second call allowed before refill")
+	}
+	if retryAfter <= 0 || retryAfter > 50*time.Millisecond {
+		t.Errorf("retry after %v, want up to 50ms at 20 requests per second", retryAfter)
+	}
+	time.Sleep(retryAfter + 10*time.Millisecond)
+	if ok, _, _ := limiter.Allow(ctx, "key", limit); !ok {
+		t.Error("call rejected after refill")
+	}
+}
+
+func TestMemoryLimiterRefund(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewMemory()
+	limit := Limit{Rate: 0.001, Burst: 2}
+
+	for range 2 {
+		limiter.Allow(ctx, "key", limit)
+	}
+	if err := limiter.Refund(ctx, "key", limit); err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	if ok, _, _ := limiter.Allow(ctx, "key", limit); !ok {
+		t.Error("refunded token is not available")
+	}
+	// Возврат не переполняет корзину сверх burst
+	for range 5 {
+		limiter.Refund(ctx, "key", limit)
+	}
+	allowed := 0
+	for range 5 {
+		if ok, _, _ := limiter.Allow(ctx, "key", limit); ok {
+			allowed++
+		}
+	}
+	if allowed != limit.Burst {
+		t.Errorf("allowed %d calls after refunds, want burst %d", allowed, limit.Burst)
+	}
+}
@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"modernc.org/sqlite"
+	"user-service/config"
+)
+
+// LowerFunction - SQL-функция перевода строки в нижний регистр с учетом Unicode;
+// встроенная lower() в SQLite меняет регистр только у латиницы
+const LowerFunction = "unicode_lower"
+
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction(LowerFunction, 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		switch v := args[0].(type) {
+		case string:
+			return strings.ToLower(v), nil
+		case nil:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("%s: unsupported argument type %T", LowerFunction, v)
+		}
+	})
+}
+
+// New - функция для открытия файла базы данных SQLite
+func New(ctx context.Context, cfg config.SQLiteConfig) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", cfg.Url())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	// SQLite допускает только одного писателя, поэтому запросы выполняются по очереди
+	// через одно соединение, а транзакции не ждут друг друга на блокировке файла
+	db.SetMaxOpenConns(1)
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open SQLite database %s: %w", cfg.Path, err)
+	}
+	return db, nil
+}
@@ -0,0 +1,330 @@
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	ErrJWKSNotConfigured = errors.New("jwks source is not configured")
+	ErrUnsupportedKey    = errors.New("unsupported jwk")
+)
+
+const (
+	// jwksFetchTimeout - таймаут загрузки JWKS по URL
+	jwksFetchTimeout = 10 * time.Second
+	// jwksMinRefreshInterval - минимальный интервал между внеплановыми обновлениями JWKS
+	// при появлении неизвестного kid, защищает источник от лишних запросов
+	jwksMinRefreshInterval = 30 * time.Second
+	// jwksMaxBodySize - максимальный размер документа JWKS
+	jwksMaxBodySize = 1 << 20
+)
+
+// jwk - ключ из JWKS-документа (RFC 7517)
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// verificationKey - публичный ключ проверки подписи
+type verificationKey struct {
+	kid string
+	// alg - алгоритм, для которого предназначен ключ, пустой если не указан
+	alg string
+	key crypto.PublicKey
+}
+
+// jwks - набор публичных ключей, загружаемый из файла или по URL и периодически обновляемый
+type jwks struct {
+	url    string
+	file   string
+	client *http.Client
+	log    *slog.Logger
+
+	mu   sync.RWMutex
+	keys []verificationKey
+	// lastAttempt - время начала последней загрузки, в том числе неудачной
+	lastAttempt time.Time
+	// refreshes - объединяет одновременные обновления в одну загрузку
+	refreshes singleflight.Group
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newJWKS - загружает набор ключей и, если interval > 0, запускает его периодическое обновление
+func newJWKS(url, file string, interval time.Duration, log *slog.Logger) (*jwks, error) {
+	if url == "" && file == "" {
+		return nil, ErrJWKSNotConfigured
+	}
+	set := &jwks{
+		url:    url,
+		file:   file,
+		client: &http.Client{Timeout: jwksFetchTimeout},
+		log:    log,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if err := set.refreshShared(context.Background(), func() bool { return true }); err != nil {
+		return nil, err
+	}
+	if interval > 0 {
+		go set.refreshLoop(interval)
+	} else {
+		close(set.done)
+	}
+	return set, nil
+}
+
+// lookup - ищет ключ по kid и алгоритму токена.
+// Если kid в токене нет, подходит единственный ключ с этим алгоритмом.
+func (s *jwks) lookup(kid, alg string) (crypto.PublicKey, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var found []verificationKey
+	for _, key := range s.keys {
+		if key.alg != "" && key.alg != alg || !keyFitsAlg(key.key, alg) {
+			continue
+		}
+		if kid != "" && key.kid != kid {
+			continue
+		}
+		found = append(found, key)
+	}
+	if len(found) != 1 {
+		return nil, false
+	}
+	return found[0].key, true
+}
+
+// refreshIfStale - внепланово обновляет набор, если с прошлой попытки обновления прошло
+// не меньше jwksMinRefreshInterval. Используется при неизвестном kid после ротации ключей.
+// Неудачные попытки тоже учитываются, поэтому недоступный источник не получает запрос
+// на каждый токен. Ключи могли обновиться и в соседнем вызове, поэтому после refreshIfStale
+// ключ ищут снова независимо от того, была ли загрузка.
+func (s *jwks) refreshIfStale(ctx context.Context) {
+	err := s.refreshShared(ctx, func() bool {
+		return time.Since(s.lastAttempt) >= jwksMinRefreshInterval
+	})
+	if err != nil {
+		s.log.WarnContext(ctx, "Failed to refresh JWKS", "error", err)
+	}
+}
+
+// refreshShared - обновляет набор, если due под блокировкой набора вернет true.
+// Одновременные вызовы ждут одну загрузку, которая не отменяется вместе с контекстом
+// первого из них.
+func (s *jwks) refreshShared(ctx context.Context, due func() bool) error {
+	_, err, _ := s.refreshes.Do("refresh", func() (any, error) {
+		s.mu.Lock()
+		if !due() {
+			s.mu.Unlock()
+			return nil, nil
+		}
+		s.lastAttempt = time.Now()
+		s.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jwksFetchTimeout)
+		defer cancel()
+		return nil, s.refresh(ctx)
+	})
+	return err
+}
+
+// refresh - загружает документ JWKS и атомарно заменяет набор ключей
+func (s *jwks) refresh(ctx context.Context) error {
+	data, err := s.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	keys, err := parseJWKS(data, s.log)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+// fetch - читает документ JWKS из файла или по URL
+func (s *jwks) fetch(ctx context.Context) ([]byte, error) {
+	if s.file != "" {
+		data, err := os.ReadFile(s.file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwks file: %w", err)
+		}
+		return data, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create jwks request: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch jwks: unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, jwksMaxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwks response: %w", err)
+	}
+	return data, nil
+}
+
+// refreshLoop - периодически обновляет набор ключей до вызова close
+func (s *jwks) refreshLoop(interval time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			err := s.refreshShared(context.Background(), func() bool { return true })
+			if err != nil {
+				// Продолжаем работать со старым набором ключей
+				s.log.Warn("Failed to refresh JWKS", "error", err)
+			}
+		}
+	}
+}
+
+// close - останавливает периодическое обновление
+func (s *jwks) close() {
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	<-s.done
+}
+
+// keyFitsAlg - подходит ли тип ключа для алгоритма подписи
+func keyFitsAlg(key crypto.PublicKey, alg string) bool {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS")
+	case *ecdsa.PublicKey:
+		return strings.HasPrefix(alg, "ES")
+	case ed25519.PublicKey:
+		return alg == "EdDSA"
+	default:
+		return false
+	}
+}
+
+// parseJWKS - разбирает документ JWKS, ключи не для подписи и неподдерживаемые ключи пропускаются
+func parseJWKS(data []byte, log *slog.Logger) ([]verificationKey, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode jwks: %w", err)
+	}
+	keys := make([]verificationKey, 0, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			log.Warn("Skipping JWK", "kid", k.Kid, "error", err)
+			continue
+		}
+		keys = append(keys, verificationKey{kid: k.Kid, alg: k.Alg, key: key})
+	}
+	return keys, nil
+}
+
+// publicKey - восстанавливает публичный ключ из параметров JWK
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("%w: rsa exponent is too large", ErrUnsupportedKey)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("%w: curve %q", ErrUnsupportedKey, k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("%w: point is not on curve", ErrUnsupportedKey)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("%w: curve %q", ErrUnsupportedKey, k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: invalid ed25519 key", ErrUnsupportedKey)
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("%w: key type %q", ErrUnsupportedKey, k.Kty)
+	}
+}
+
+// decodeBigInt - декодирует base64url-число из JWK
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("%w: invalid base64url number", ErrUnsupportedKey)
+	}
+	return new(big.Int).SetBytes(data), nil
+}
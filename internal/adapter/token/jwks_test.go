@@ -0,0 +1,217 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testJWKSServer - источник JWKS, который считает запросы и отвечает ошибкой, пока fail = true
+type testJWKSServer struct {
+	*httptest.Server
+	requests atomic.Int32
+	fail     atomic.Bool
+	// release - если не nil, ответ ждет закрытия канала
+	release chan struct{}
+}
+
+func newTestJWKSServer(t *testing.T) *testJWKSServer {
+	t.Helper()
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	doc := fmt.Sprintf(`{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"k1","x":%q}]}`,
+		base64.RawURLEncoding.EncodeToString(public))
+
+	server := &testJWKSServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.requests.Add(1)
+		if server.release != nil {
+			<-server.release
+		}
+		if server.fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, doc)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// expireAttempt - делает прошлую попытку обновления достаточно старой для внепланового обновления
+func expireAttempt(set *jwks) {
+	set.mu.Lock()
+	set.lastAttempt = time.Now().Add(-jwksMinRefreshInterval)
+	set.mu.Unlock()
+}
+
+func TestJWKSRefreshIfStaleLimitsFailedAttempts(t *testing.T) {
+	server := newTestJWKSServer(t)
+	set, err := newJWKS(server.URL, "", 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("newJWKS: %v", err)
+	}
+
+	// Сразу после загрузки внеплановое обновление не нужно
+	set.refreshIfStale(t.Context())
+	if got := server.requests.Load(); got != 1 {
+		t.Fatalf("got %d requests after fresh load, want 1", got)
+	}
+
+	server.fail.Store(true)
+	expireAttempt(set)
+	for range 3 {
+		set.refreshIfStale(t.Context())
+	}
+	if got := server.requests.Load(); got != 2 {
+		t.Fatalf("got %d requests after failed refresh, want 2", got)
+	}
+	if _, ok := set.lookup("k1", "EdDSA"); !ok {
+		t.Error("failed refresh dropped the previous keys")
+	}
+}
+
+func TestJWKSRefreshIfStaleSharesFetch(t *testing.T) {
+	server := newTestJWKSServer(t)
+	set, err := newJWKS(server.URL, "", 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("newJWKS: %v", err)
+	}
+
+	server.release = make(chan struct{})
+	expireAttempt(set)
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			set.refreshIfStale(t.Context())
+		}()
+	}
+	// Даем вызовам дойти до загрузки, затем отпускаем ответ
+	time.Sleep(50 * time.Millisecond)
+	close(server.release)
+	wg.Wait()
+
+	if got := server.requests.Load(); got != 2 {
+		t.Errorf("got %d requests, want 2: initial load and one shared refresh", got)
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ec key: %v", err)
+	}
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ed25519 key: %v", err)
+	}
+	b64 := func(data []byte) string { return base64.RawURLEncoding.EncodeToString(data) }
+	rsaJWK := fmt.Sprintf(`{"kty":"RSA","kid":"rsa","alg":"RS256","n":%q,"e":%q}`,
+		b64(rsaKey.N.Bytes()), b64(big.NewInt(int64(rsaKey.E)).Bytes()))
+	ecJWK := fmt.Sprintf(`{"kty":"EC","kid":"ec","crv":"P-256","x":%q,"y":%q}`,
+		b64(ecKey.X.Bytes()), b64(ecKey.Y.Bytes()))
+	edJWK := fmt.Sprintf(`{"kty":"OKP","kid":"ed","crv":"Ed25519","use":"sig","x":%q}`, b64(edKey))
+
+	tests := []struct {
+		name     string
+		doc      string
+		wantKids []string
+		wantErr  bool
+	}{
+		{name: "all supported key types", doc: `{"keys":[` + rsaJWK + `,` + ecJWK + `,` + edJWK + `]}`, wantKids: []string{"rsa", "ec", "ed"}},
+		{name: "encryption key skipped", doc: `{"keys":[{"kty":"RSA","kid":"enc","use":"enc","n":"AQAB","e":"AQAB"},` + edJWK + `]}`, wantKids: []string{"ed"}},
+		{name: "unsupported key type skipped", doc: `{"keys":[{"kty":"oct","kid":"hmac","k":"c2VjcmV0"},` + edJWK + `]}`, wantKids: []string{"ed"}},
+		{name: "unsupported curve skipped", doc: `{"keys":[{"kty":"EC","kid":"k","crv":"secp256k1","x":"AQ","y":"AQ"}]}`, wantKids: []string{}},
+		{name: "point not on curve skipped", doc: `{"keys":[{"kty":"EC","kid":"k","crv":"P-256","x":"AQ","y":"AQ"}]}`, wantKids: []string{}},
+		{name: "short ed25519 key skipped", doc: `{"keys":[{"kty":"OKP","kid":"k","crv":"Ed25519","x":"AQID"}]}`, wantKids: []string{}},
+		{name: "invalid base64 skipped", doc: `{"keys":[{"kty":"RSA","kid":"k","n":"!!","e":"AQAB"}]}`, wantKids: []string{}},
+		{name: "empty set", doc: `{"keys":[]}`, wantKids: []string{}},
+		{name: "not json", doc: `keys`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := parseJWKS([]byte(tt.doc), slog.New(slog.NewTextHandler(io.Discard, nil)))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseJWKS: %v", err)
+			}
+			kids := make([]string, 0, len(keys))
+			for _, key := range keys {
+				kids = append(kids, key.kid)
+			}
+			if !slices.Equal(kids, tt.wantKids) {
+				t.Errorf("got keys %v, want %v", kids, tt.wantKids)
+			}
+		})
+	}
+}
+
+func TestJWKSLookup(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	edFirst, _, _ := ed25519.GenerateKey(rand.Reader)
+	edSecond, _, _ := ed25519.GenerateKey(rand.Reader)
+	set := &jwks{keys: []verificationKey{
+		{kid: "rsa", alg: "RS256", key: &rsaKey.PublicKey},
+		{kid: "ed-1", key: edFirst},
+		{kid: "ed-2", key: edSecond},
+	}}
+
+	tests := []struct {
+		name    string
+		kid     string
+		alg     string
+		wantKey any
+	}{
+		{name: "kid and alg match", kid: "rsa", alg: "RS256", wantKey: &rsaKey.PublicKey},
+		{name: "alg differs from key alg", kid: "rsa", alg: "RS512"},
+		{name: "key type does not fit alg", kid: "ed-1", alg: "RS256"},
+		{name: "kid without alg on key", kid: "ed-2", alg: "EdDSA", wantKey: edSecond},
+		{name: "no kid and single key for alg", alg: "RS256", wantKey: &rsaKey.PublicKey},
+		{name: "no kid and several keys for alg", alg: "EdDSA"},
+		{name: "unknown kid", kid: "other", alg: "EdDSA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, ok := set.lookup(tt.kid, tt.alg)
+			if ok != (tt.wantKey != nil) {
+				t.Fatalf("found = %v, want %v", ok, tt.wantKey != nil)
+			}
+			if ok && !key.(interface{ Equal(crypto.PublicKey) bool }).Equal(tt.wantKey) {
+				t.Error("found another key")
+			}
+		})
+	}
+}
@@ -1,55 +1,155 @@
 package token
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"user-service/config"
 )
 
 var (
-	ErrInvalidToken      = errors.New("token is invalid")
-	ErrSecretKeyNotFound = errors.New("secret key not found")
+	ErrInvalidToken       = errors.New("token is invalid")
+	ErrSecretKeyNotFound  = errors.New("secret key not found")
 	ErrAccessTokenExpired = errors.New("access token expired")
+	ErrUnknownSigningKey  = errors.New("unknown signing key")
+	ErrNoAlgorithms       = errors.New("no allowed signing algorithms")
+	ErrUnknownAlgorithm   = errors.New("unknown signing algorithm")
 )
 
+// defaultAlgorithms - разрешенные алгоритмы, если token.algorithms не задан
+var defaultAlgorithms = []string{"HS256"}
+
 var _ Token = (*token)(nil)
 
 type Token interface {
 	// ValidateToken - валидирует токен и возвращает его содержимое
 	ValidateToken(tokenString string) (bool, uuid.UUID, error)
+	// VerifyToken - валидирует токен и возвращает claims, нужные для проверки отзыва
+	VerifyToken(tokenString string) (Claims, error)
+}
+
+// Claims - проверенное содержимое токена доступа
+type Claims struct {
+	UserID uuid.UUID
+	// ID - идентификатор токена (jti), пустой если не задан
+	ID string
+	// IssuedAt - время выпуска (iat), нулевое если не задано
+	IssuedAt time.Time
+	// ExpiresAt - срок действия (exp)
+	ExpiresAt time.Time
 }
 
 type token struct {
-	secretKey string
+	secretKey  string
+	keys       *jwks
+	algorithms []string
+	parser     *jwt.Parser
 }
 
-func New(secretKey string) (*token, error) {
-	if secretKey == "" {
+// New - создает сервис проверки токенов.
+// HMAC-алгоритмы проверяются секретом token.secret, асимметричные (RS*, PS*, ES*, EdDSA) -
+// ключами из JWKS, выбранными по kid. Алгоритмы не из token.algorithms отклоняются.
+func New(cfg config.TokenConfig, log *slog.Logger) (*token, error) {
+	algorithms := cfg.Algorithms
+	if len(algorithms) == 0 {
+		algorithms = defaultAlgorithms
+	}
+
+	var needSecret, needKeys bool
+	for _, alg := range algorithms {
+		switch method := jwt.GetSigningMethod(alg); method.(type) {
+		case *jwt.SigningMethodHMAC:
+			needSecret = true
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA, *jwt.SigningMethodEd25519:
+			needKeys = true
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnknownAlgorithm, alg)
+		}
+	}
+	if !needSecret && !needKeys {
+		return nil, ErrNoAlgorithms
+	}
+	if needSecret && cfg.Secret == "" {
 		return nil, ErrSecretKeyNotFound
 	}
-	return &token{
-		secretKey: secretKey,
-	}, nil
+
+	t := &token{
+		secretKey:  cfg.Secret,
+		algorithms: algorithms,
+	}
+	if needKeys {
+		keys, err := newJWKS(cfg.JWKS.URL, cfg.JWKS.File, cfg.JWKS.RefreshInterval, log)
+		if err != nil {
+			return nil, err
+		}
+		t.keys = keys
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(algorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	t.parser = jwt.NewParser(options...)
+	return t, nil
+}
+
+// Close - останавливает фоновое обновление JWKS
+func (t *token) Close() {
+	if t.keys != nil {
+		t.keys.close()
+	}
 }
 
 func (t *token) ValidateToken(tokenString string) (bool, uuid.UUID, error) {
-	claims, err := t.parseToken(tokenString)
+	claims, err := t.VerifyToken(tokenString)
 	if err != nil {
 		return false, uuid.Nil, err
 	}
 
+	return true, claims.UserID, nil
+}
+
+func (t *token) VerifyToken(tokenString string) (Claims, error) {
+	claims, err := t.parseToken(tokenString)
+	if err != nil {
+		return Claims{}, err
+	}
+
 	if claims.ExpiresAt == nil || claims.ExpiresAt.Time.IsZero() {
-		return false, uuid.Nil, ErrAccessTokenExpired
+		return Claims{}, ErrAccessTokenExpired
+	}
+	userId, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
 	}
-	userId := uuid.MustParse(claims.Subject)
 
-	return true, userId, nil
+	verified := Claims{
+		UserID:    userId,
+		ID:        claims.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if claims.IssuedAt != nil {
+		verified.IssuedAt = claims.IssuedAt.Time
+	}
+	return verified, nil
 }
 
 func (t *token) parseToken(tokenString string) (*jwt.RegisteredClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(j *jwt.Token) (any, error) {
-		return []byte(t.secretKey), nil
-	})
+	token, err := t.parser.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, t.keyFunc)
 	if err != nil {
 		return nil, err
 	}
@@ -62,3 +162,28 @@ func (t *token) parseToken(tokenString string) (*jwt.RegisteredClaims, error) {
 	}
 	return claims, nil
 }
+
+// keyFunc - выбирает ключ проверки подписи по алгоритму и kid из заголовка токена
+func (t *token) keyFunc(j *jwt.Token) (any, error) {
+	alg := j.Method.Alg()
+	// Парсер уже проверил алгоритм, повторная проверка защищает от ошибок конфигурации
+	if !slices.Contains(t.algorithms, alg) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAlgorithm, alg)
+	}
+	if strings.HasPrefix(alg, "HS") {
+		return []byte(t.secretKey), nil
+	}
+	if t.keys == nil {
+		return nil, ErrUnknownSigningKey
+	}
+	kid, _ := j.Header["kid"].(string)
+	if key, ok := t.keys.lookup(kid, alg); ok {
+		return key, nil
+	}
+	// Неизвестный kid может означать, что ключи ротировали после последнего обновления
+	t.keys.refreshIfStale(context.Background())
+	if key, ok := t.keys.lookup(kid, alg); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: kid %q", ErrUnknownSigningKey, kid)
+}
@@ -3,102 +3,265 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"user-service/config"
 	"user-service/gen/user"
-	"user-service/internal/adapter/postgres"
 	"user-service/internal/adapter/token"
+	"user-service/internal/auth"
+	"user-service/internal/controller/gateway"
+	"user-service/internal/controller/grpc/catalog"
+	"user-service/internal/controller/grpc/revocation"
 	"user-service/internal/controller/grpc/user"
+	"user-service/internal/health"
+	"user-service/internal/logger"
+	"user-service/internal/metrics"
+	"user-service/internal/outbox"
 	"user-service/internal/repository"
+	"user-service/internal/tracing"
+	"user-service/internal/usecase/catalog"
+	"user-service/internal/usecase/revocation"
 	"user-service/internal/usecase/user"
 )
 
-// Run - запускает приложение
+// Run - запускает приложение и блокируется до SIGINT/SIGTERM, после чего
+// дожидается текущих запросов и останавливает компоненты в обратном порядке
 func Run(cfg *config.Config, devMode bool) {
 
-	// Инициализация дефолтного логгера
-	logger := log.Default()
+	// Инициализация логгера из конфигурации, он же становится логгером по умолчанию
+	log, err := logger.New(cfg.Log)
+	if err != nil {
+		fatal(slog.Default(), "Failed to initialize logger", err)
+	}
+	slog.SetDefault(log)
 
-	// Подключение к базе данных
-	dbpool, err := postgres.New(context.Background(), *cfg)
+	// Сигналы остановки
+	ctx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
 
+	lifecycle := newLifecycle(logger.Component(log, "lifecycle"))
+
+	// Трассировка останавливается последней, чтобы выгрузить спаны остальных шагов остановки
+	shutdownTracing, err := tracing.New(ctx, *cfg)
 	if err != nil {
-		logger.Fatalf("Unable to create connection pool: %v", err)
+		fatal(log, "Failed to initialize tracing", err)
 	}
+	lifecycle.onShutdown("tracing", shutdownTracing)
 
-	defer dbpool.Close()
+	// Создаем репозитории: в режиме разработки в памяти, иначе в Postgres
+	storage, err := newStorage(ctx, cfg, devMode, logger.Component(log, "repository"), lifecycle)
+	if err != nil {
+		fatal(log, "Unable to initialize storage", err)
+	}
+	userRepo, catalogRepo, revocationRepo := storage.users, storage.catalog, storage.revocations
 
-	logger.Printf("Database connection established")
+	// Продукты и предпочтения читаются через кэш, изменения других реплик сбрасывают его
+	if cfg.Cache.Enabled {
+		cacheStore, err := newCache(ctx, cfg.Cache, lifecycle)
+		if err != nil {
+			fatal(log, "Failed to initialize cache", err)
+		}
+		ttl := repository.CacheTTL{Products: cfg.Cache.ProductsTTL, Preferences: cfg.Cache.PreferencesTTL}
+		cached := repository.NewCached(userRepo, cacheStore, ttl, logger.Component(log, "cache"))
+		lifecycle.goWorker("cache invalidation watcher", cached.Run)
+		userRepo = cached
+	}
 
-	// Создаем репозитории
-	userRepo := repository.New(dbpool)
+	// Публикуем доменные события из outbox в брокер
+	if cfg.Outbox.Enabled {
+		publisher, err := newPublisher(cfg.Outbox, devMode, lifecycle)
+		if err != nil {
+			fatal(log, "Failed to initialize event broker", err)
+		}
+		relay := outbox.NewRelay(storage.outbox, publisher, cfg.Outbox, logger.Component(log, "outbox"))
+		lifecycle.goWorker("outbox relay", relay.Run)
+	}
 
 	// Создаем сервис работы с токенами
-	token, err := token.New(cfg.Token.Secret)
+	token, err := token.New(cfg.Token, logger.Component(log, "token"))
 	if err != nil {
-		logger.Fatalf("Failed to initialize token service: %v", err)
+		fatal(log, "Failed to initialize token service", err)
 	}
+	lifecycle.onShutdown("JWKS refresh", func(context.Context) error {
+		token.Close()
+		return nil
+	})
+
+	// Загружаем отозванные токены и подписываемся на новые отзывы
+	revocations := auth.NewRevocationList(revocationRepo, logger.Component(log, "auth"))
+	if err := revocations.Load(ctx); err != nil {
+		fatal(log, "Failed to load token revocations", err)
+	}
+	lifecycle.goWorker("token revocation watcher", revocations.Run)
 
 	// Создаем слой usecase
-	userUseCase := usecase.New(userRepo, token)
+	usecaseLog := logger.Component(log, "usecase")
+	userUseCase := usecase.New(userRepo, catalogRepo, usecaseLog)
+	catalogUseCase := catalog.New(catalogRepo, usecaseLog)
+	revocationUseCase := revocation.New(revocationRepo, revocations, usecaseLog)
+
+	grpcLog := logger.Component(log, "grpc")
+
+	// Интерсепторы: метрики, логирование, дедлайн, аутентификация и, если включено, rate limiting
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		metricsUnaryInterceptor,
+		grpcLogUnaryInterceptor(grpcLog, cfg.Log.PayloadSampleRate),
+		deadlineUnaryInterceptor(time.Duration(cfg.GRPC.Timeout) * time.Second),
+		authUnaryInterceptor(token, revocations),
+	}
+	if cfg.RateLimit.Enabled {
+		rateLimitLog := logger.Component(log, "ratelimit")
+		limiter, err := newRateLimiter(cfg.RateLimit, storage.db, rateLimitLog)
+		if err != nil {
+			fatal(log, "Failed to initialize rate limiter", err)
+		}
+		lifecycle.goWorker("rate limit cleanup", limiter.Run)
+		unaryInterceptors = append(unaryInterceptors, rateLimitUnaryInterceptor(limiter, cfg.RateLimit, rateLimitLog))
+	}
 
 	// Создаем gRPC-сервер
+	// Stats handler извлекает W3C trace context из метаданных и открывает серверный спан вызова
 	grpcServer := grpc.NewServer(
-		grpc.StreamInterceptor(grpcLogStreamInterceptor),
-		grpc.UnaryInterceptor(grpcLogUnaryInterceptor),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainStreamInterceptor(metricsStreamInterceptor, grpcLogStreamInterceptor(grpcLog), authStreamInterceptor(token, revocations)),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
 	)
 
 	// Создаем и регистрируем gRPC-сервис User
-	userController := grpcuser.New(userUseCase)
+	userController := grpcuser.New(userUseCase, grpcLog)
 	user.RegisterUserServiceServer(grpcServer, userController)
 
+	// Создаем и регистрируем административный gRPC-сервис каталога
+	catalogController := grpccatalog.New(catalogUseCase, cfg.Admin.APIKey, grpcLog)
+	user.RegisterCatalogServiceServer(grpcServer, catalogController)
+
+	// Создаем и регистрируем внутренний gRPC-сервис отзыва токенов
+	revocationController := grpcrevocation.New(revocationUseCase, cfg.Internal.APIKey, grpcLog)
+	user.RegisterTokenRevocationServiceServer(grpcServer, revocationController)
+
+	// Создаем и регистрируем gRPC health, статус зависит от доступности Postgres
+	healthMonitor := health.New(storage.pinger, logger.Component(log, "health"), cfg.Health.Interval, cfg.Health.Timeout,
+		user.UserService_ServiceDesc.ServiceName,
+		user.CatalogService_ServiceDesc.ServiceName,
+		user.TokenRevocationService_ServiceDesc.ServiceName,
+	)
+	healthpb.RegisterHealthServer(grpcServer, healthMonitor.Server())
+	lifecycle.goWorker("health monitor", healthMonitor.Run)
+
+	// Запускаем служебный HTTP-сервер с пробами /livez, /readyz и метриками /metrics.
+	// Он останавливается после gRPC-сервера, чтобы /readyz отвечал 503 во время остановки.
+	if cfg.Admin.Port > 0 {
+		adminMux := http.NewServeMux()
+		adminMux.Handle("/", healthMonitor.Handler())
+		adminMux.Handle("GET /metrics", metrics.Handler())
+		adminServer := &http.Server{
+			Addr:    fmt.Sprintf(":%d", cfg.Admin.Port),
+			Handler: adminMux,
+			// Пробам хватает короткого таймаута, он защищает от медленных клиентов
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		go func() {
+			log.Info("Starting admin HTTP server", "port", cfg.Admin.Port)
+			if err := adminServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fatal(log, "Failed to serve admin HTTP server", err)
+			}
+		}()
+		lifecycle.onShutdown("admin HTTP server", adminServer.Shutdown)
+	}
+
 	// Слушаем порт gRPC
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
 	if err != nil {
-		logger.Fatalf("Failed to listen on port %d: %v", cfg.GRPC.Port, err)
+		fatal(log, fmt.Sprintf("Failed to listen on port %d", cfg.GRPC.Port), err)
 	}
 
-	logger.Printf("Starting gRPC server on port %d\n", cfg.GRPC.Port)
-	// Запускаем gRPC-сервер
-	if err := grpcServer.Serve(lis); err != nil {
-		logger.Fatalf("Failed to serve gRPC server: %v", err)
+	// gRPC-сервер перестает принимать запросы, текущие дорабатывают не дольше drain_timeout
+	drainTimeout := cfg.GRPC.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = defaultDrainTimeout
 	}
-}
+	lifecycle.onShutdown("gRPC server", func(context.Context) error {
+		return gracefulStop(grpcServer, drainTimeout)
+	})
 
-// Интерсепторы для логирования
-func grpcLogStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-	logger := log.Default()
-	logger.Printf("gRPC Stream called: %s from %s", info.FullMethod, ss.Context().Value("peer").(*peer.Peer).Addr.String())
-	return handler(srv, ss)
-}
+	// Запускаем REST/JSON шлюз, он ходит в gRPC-сервер через loopback-соединение
+	// и останавливается раньше него
+	if cfg.Gateway.Port > 0 {
+		conn, err := grpc.NewClient(fmt.Sprintf("localhost:%d", cfg.GRPC.Port),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		if err != nil {
+			fatal(log, "Failed to create gateway connection", err)
+		}
+		lifecycle.onShutdown("gateway connection", func(context.Context) error {
+			return conn.Close()
+		})
+		handler, err := gateway.New(ctx, conn)
+		if err != nil {
+			fatal(log, "Failed to create gateway", err)
+		}
+		gatewayServer := &http.Server{
+			Addr:              fmt.Sprintf(":%d", cfg.Gateway.Port),
+			Handler:           handler,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		go func() {
+			log.Info("Starting REST gateway", "port", cfg.Gateway.Port)
+			if err := gatewayServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fatal(log, "Failed to serve REST gateway", err)
+			}
+		}()
+		lifecycle.onShutdown("REST gateway", gatewayServer.Shutdown)
+	}
 
-func grpcLogUnaryInterceptor(
-	ctx context.Context,
-	req any,
-	info *grpc.UnaryServerInfo,
-	handler grpc.UnaryHandler,
-) (any, error) {
-	logger := log.Default()
-	peerInfo, _ := peer.FromContext(ctx)
-	//if !ok {
-	//	logger.Printf("gRPC Unary called: %s from UNKNOWN", info.FullMethod)
-	//} else {
-	//	//logger.Printf("gRPC Unary called: %s from %s", info.FullMethod, peerInfo.Addr.String())
-	//}
-
-	resp, err := handler(ctx, req)
+	// Остановка начинается с перевода health в NOT_SERVING,
+	// чтобы балансировщики перестали направлять новые запросы
+	lifecycle.onShutdown("health reporting", func(context.Context) error {
+		healthMonitor.Shutdown()
+		return nil
+	})
+
+	log.Info("Starting gRPC server", "port", cfg.GRPC.Port)
+	// Запускаем gRPC-сервер
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- grpcServer.Serve(lis)
+	}()
+
+	select {
+	case <-ctx.Done():
+		log.Info("Shutdown signal received, draining in-flight requests")
+	case err = <-serveErr:
+		log.Error("gRPC server stopped unexpectedly", "error", err)
+	}
+	// Повторный сигнал завершает процесс сразу
+	stopSignals()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drainTimeout+closeTimeout)
+	defer cancel()
+	lifecycle.shutdown(shutdownCtx)
 
 	if err != nil {
-		logger.Printf("gRPC Unary response error: %s, method: %s, error: %v", peerInfo.Addr.String(), info.FullMethod, err)
-	} else {
-		logger.Printf("gRPC Unary response: %s, method: %s, response: %v", peerInfo.Addr.String(), info.FullMethod, resp)
+		fatal(log, "Failed to serve gRPC server", err)
 	}
+	log.Info("Server stopped")
+}
 
-	return resp, err
-}
\ No newline at end of file
+// fatal - пишет ошибку запуска и завершает процесс
+func fatal(log *slog.Logger, msg string, err error) {
+	log.Error(msg, "error", err)
+	os.Exit(1)
+}
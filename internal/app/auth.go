@@ -0,0 +1,120 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"user-service/gen/user"
+	"user-service/internal/adapter/token"
+	"user-service/internal/auth"
+	"user-service/internal/controller/grpc/grpcerr"
+	"user-service/internal/metrics"
+	"user-service/internal/tracing"
+)
+
+const (
+	// authorizationHeader - ключ метаданных с токеном доступа
+	authorizationHeader = "authorization"
+	// bearerScheme - схема авторизации в заголовке authorization
+	bearerScheme = "bearer"
+)
+
+// authenticatedServices - сервисы, методы которых требуют токен пользователя.
+// Административные сервисы проверяют свой ключ сами.
+var authenticatedServices = []string{
+	user.UserService_ServiceDesc.ServiceName,
+}
+
+// accessTokenCarrier - сообщения запросов со старым полем access_token
+type accessTokenCarrier interface {
+	GetAccessToken() string
+}
+
+// authUnaryInterceptor - проверяет токен доступа и его отзыв и кладет личность пользователя в контекст.
+// Токен берется из метаданных authorization: Bearer <token>, а если их нет -
+// из поля access_token сообщения (на время перехода клиентов).
+func authUnaryInterceptor(tokens token.Token, revocations *auth.RevocationList) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !requiresAuth(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		accessToken := bearerToken(ctx)
+		if accessToken == "" {
+			if carrier, ok := req.(accessTokenCarrier); ok {
+				accessToken = carrier.GetAccessToken()
+			}
+		}
+		identity, err := authenticate(ctx, tokens, revocations, accessToken)
+		if err != nil {
+			return nil, grpcerr.ToStatus(err)
+		}
+		return handler(auth.WithIdentity(ctx, identity), req)
+	}
+}
+
+// authStreamInterceptor - аналог authUnaryInterceptor для потоковых методов,
+// токен берется только из метаданных
+func authStreamInterceptor(tokens token.Token, revocations *auth.RevocationList) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !requiresAuth(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		identity, err := authenticate(ss.Context(), tokens, revocations, bearerToken(ss.Context()))
+		if err != nil {
+			return grpcerr.ToStatus(err)
+		}
+		return handler(srv, &authServerStream{
+			ServerStream: ss,
+			ctx:          auth.WithIdentity(ss.Context(), identity),
+		})
+	}
+}
+
+// authenticate - проверяет токен в отдельном спане и учитывает отказы в метрике по причине
+func authenticate(ctx context.Context, tokens token.Token, revocations *auth.RevocationList, accessToken string) (identity auth.Identity, err error) {
+	_, span := otel.Tracer("user-service/internal/app").Start(ctx, "auth.Authenticate")
+	defer tracing.End(span, &err)
+
+	identity, err = auth.Authenticate(tokens, revocations, accessToken)
+	if err != nil {
+		_, reason, _ := grpcerr.Classify(err)
+		metrics.TokenValidationFailed(reason)
+	}
+	return identity, err
+}
+
+// authServerStream - ServerStream с контекстом, содержащим личность пользователя
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// requiresAuth - требует ли метод токен пользователя
+func requiresAuth(fullMethod string) bool {
+	for _, service := range authenticatedServices {
+		if strings.HasPrefix(fullMethod, "/"+service+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// bearerToken - достает токен из метаданных authorization: Bearer <token>
+func bearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get(authorizationHeader) {
+		scheme, token, ok := strings.Cut(strings.TrimSpace(value), " ")
+		if ok && strings.EqualFold(scheme, bearerScheme) {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"user-service/config"
+	"user-service/internal/adapter/cache"
+)
+
+// newCache - создает хранилище кэша по cache.backend, соединение с Redis закрывается в lifecycle
+func newCache(ctx context.Context, cfg config.CacheConfig, lifecycle *lifecycle) (cache.Cache, error) {
+	switch cfg.Backend {
+	case "", "memory":
+		return cache.NewLRU(cfg.Size), nil
+	case "redis":
+		redis, err := cache.NewRedis(ctx, cfg.Redis)
+		if err != nil {
+			return nil, err
+		}
+		lifecycle.onShutdown("redis", func(context.Context) error {
+			return redis.Close()
+		})
+		return redis, nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"user-service/internal/controller/grpc/grpcerr"
+)
+
+// deadlineUnaryInterceptor - ограничивает время обработки запроса значением grpc.timeout.
+// Если клиент передал более короткий дедлайн, действует он; timeout <= 0 ничего не ограничивает.
+// Если контекст истек или отменен во время обработки, клиент получает DEADLINE_EXCEEDED
+// или CANCELED, даже если хранилище вернуло свою ошибку.
+func deadlineUnaryInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if deadline, ok := ctx.Deadline(); timeout > 0 && (!ok || time.Until(deadline) > timeout) {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		resp, err := handler(ctx, req)
+		if err != nil && ctx.Err() != nil {
+			return nil, grpcerr.ToStatus(ctx.Err())
+		}
+		return resp, err
+	}
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// ErrDrainTimeout - ошибка, когда текущие запросы не завершились за время остановки
+var ErrDrainTimeout = errors.New("grpc drain timed out, in-flight requests were canceled")
+
+const (
+	// defaultDrainTimeout - время на завершение текущих запросов, если grpc.drain_timeout не задан
+	defaultDrainTimeout = 25 * time.Second
+	// closeTimeout - время на остановку остальных компонентов после gRPC-сервера
+	closeTimeout = 10 * time.Second
+)
+
+// closer - шаг остановки приложения
+type closer struct {
+	name  string
+	close func(ctx context.Context) error
+}
+
+// lifecycle - останавливает компоненты приложения в порядке, обратном их запуску:
+// сначала перестают приниматься запросы, затем останавливаются фоновые задачи
+// и только потом закрываются соединения, которыми они пользуются
+type lifecycle struct {
+	logger  *slog.Logger
+	closers []closer
+}
+
+func newLifecycle(logger *slog.Logger) *lifecycle {
+	return &lifecycle{logger: logger}
+}
+
+// onShutdown - регистрирует шаг остановки
+func (l *lifecycle) onShutdown(name string, stop func(ctx context.Context) error) {
+	l.closers = append(l.closers, closer{name: name, close: stop})
+}
+
+// goWorker - запускает фоновую задачу и регистрирует ее остановку:
+// контекст задачи отменяется, и остановка ждет ее завершения
+func (l *lifecycle) goWorker(name string, run func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run(ctx)
+	}()
+	l.onShutdown(name, func(shutdownCtx context.Context) error {
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-shutdownCtx.Done():
+			return shutdownCtx.Err()
+		}
+	})
+}
+
+// shutdown - выполняет шаги остановки в обратном порядке, ошибка одного шага не прерывает остальные
+func (l *lifecycle) shutdown(ctx context.Context) {
+	for i := len(l.closers) - 1; i >= 0; i-- {
+		step := l.closers[i]
+		if err := step.close(ctx); err != nil {
+			l.logger.Error("Failed to stop", "step", step.name, "error", err)
+			continue
+		}
+		l.logger.Info("Stopped", "step", step.name)
+	}
+}
+
+// gracefulStop - дожидается завершения текущих запросов не дольше timeout,
+// после чего обрывает оставшиеся через Stop
+func gracefulStop(server *grpc.Server, timeout time.Duration) error {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-stopped:
+		return nil
+	case <-timer.C:
+		server.Stop()
+		<-stopped
+		return ErrDrainTimeout
+	}
+}
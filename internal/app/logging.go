@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"user-service/internal/logger"
+)
+
+// grpcLogUnaryInterceptor - пишет по записи на каждый вызов: метод, адрес клиента, код и длительность.
+// Тела запроса и ответа пишутся на уровне debug для доли вызовов sampleRate, секреты и персональные данные в них скрыты.
+func grpcLogUnaryInterceptor(log *slog.Logger, sampleRate float64) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		attrs := []slog.Attr{
+			slog.String("method", info.FullMethod),
+			slog.String("peer", clientAddress(ctx)),
+			slog.String("code", code.String()),
+			slog.Duration("duration", time.Since(start)),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+		}
+		if log.Enabled(ctx, slog.LevelDebug) && sampled(sampleRate) {
+			attrs = append(attrs, logger.Payload("request", req))
+			if err == nil {
+				attrs = append(attrs, logger.Payload("response", resp))
+			}
+		}
+		log.LogAttrs(ctx, codeLevel(code), "gRPC unary call", attrs...)
+
+		return resp, err
+	}
+}
+
+// grpcLogStreamInterceptor - пишет по записи на каждый потоковый вызов после его завершения
+func grpcLogStreamInterceptor(log *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+
+		code := status.Code(err)
+		attrs := []slog.Attr{
+			slog.String("method", info.FullMethod),
+			slog.String("peer", clientAddress(ss.Context())),
+			slog.String("code", code.String()),
+			slog.Duration("duration", time.Since(start)),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+		}
+		log.LogAttrs(ss.Context(), codeLevel(code), "gRPC stream call", attrs...)
+
+		return err
+	}
+}
+
+// codeLevel - уровень записи о вызове: ошибки сервера - error, остальное - info
+func codeLevel(code codes.Code) slog.Level {
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.Unimplemented:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// sampled - попадает ли вызов в выборку с долей rate
+func sampled(rate float64) bool {
+	return rate >= 1 || (rate > 0 && rand.Float64() < rate)
+}
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"user-service/internal/metrics"
+)
+
+// metricsUnaryInterceptor - учитывает длительность и код каждого вызова.
+// Идет первым в цепочке, чтобы учитывались и отказы auth и rate limiting.
+func metricsUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	metrics.ObserveRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+	return resp, err
+}
+
+// metricsStreamInterceptor - аналог metricsUnaryInterceptor для потоковых методов
+func metricsStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	metrics.ObserveRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+	return err
+}
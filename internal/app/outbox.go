@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"user-service/config"
+	"user-service/internal/adapter/broker"
+)
+
+// newPublisher - создает брокер событий по outbox.broker, соединение закрывается в lifecycle.
+// stdout и file пишут данные пользователей в открытом виде и не доставляют события подписчикам,
+// поэтому разрешены только в режиме разработки.
+func newPublisher(cfg config.OutboxConfig, devMode bool, lifecycle *lifecycle) (broker.Publisher, error) {
+	switch cfg.Broker {
+	case "":
+		return nil, errors.New("outbox.broker is required when outbox is enabled")
+	case "stdout", "file":
+		if !devMode {
+			return nil, fmt.Errorf("outbox broker %q is allowed only in dev mode, use kafka or nats", cfg.Broker)
+		}
+	}
+
+	var publisher broker.Publisher
+	switch cfg.Broker {
+	case "stdout":
+		publisher = broker.NewStdout()
+	case "file":
+		file, err := broker.NewFile(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		publisher = file
+	case "kafka":
+		publisher = broker.NewKafka(cfg.Kafka)
+	case "nats":
+		nats, err := broker.NewNATS(cfg.NATS)
+		if err != nil {
+			return nil, err
+		}
+		publisher = nats
+	default:
+		return nil, fmt.Errorf("unknown outbox broker %q", cfg.Broker)
+	}
+	lifecycle.onShutdown("event broker", func(context.Context) error {
+		return publisher.Close()
+	})
+	return publisher, nil
+}
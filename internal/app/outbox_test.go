@@ -0,0 +1,44 @@
+package app
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"user-service/config"
+)
+
+func TestNewPublisher(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "events.jsonl")
+	tests := []struct {
+		name    string
+		cfg     config.OutboxConfig
+		devMode bool
+		wantErr bool
+	}{
+		{name: "broker is not set", cfg: config.OutboxConfig{}, devMode: true, wantErr: true},
+		{name: "stdout outside dev mode", cfg: config.OutboxConfig{Broker: "stdout"}, wantErr: true},
+		{name: "file outside dev mode", cfg: config.OutboxConfig{Broker: "file", File: file}, wantErr: true},
+		{name: "stdout in dev mode", cfg: config.OutboxConfig{Broker: "stdout"}, devMode: true},
+		{name: "file in dev mode", cfg: config.OutboxConfig{Broker: "file", File: file}, devMode: true},
+		{name: "unknown broker", cfg: config.OutboxConfig{Broker: "redis"}, devMode: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lifecycle := newLifecycle(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			publisher, err := newPublisher(tt.cfg, tt.devMode, lifecycle)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newPublisher: %v", err)
+			}
+			publisher.Close()
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"user-service/config"
+	"user-service/internal/adapter/ratelimit"
+	"user-service/internal/auth"
+	"user-service/internal/controller/grpc/grpcerr"
+)
+
+const (
+	// retryAfterHeader - ключ метаданных ответа с числом секунд до повтора запроса
+	retryAfterHeader = "retry-after"
+	// forwardedForHeader - ключ метаданных, в который REST-шлюз кладет адрес клиента
+	forwardedForHeader = "x-forwarded-for"
+)
+
+// rateLimitExemptServices - сервисы, на которые лимиты не действуют
+var rateLimitExemptServices = []string{
+	healthpb.Health_ServiceDesc.ServiceName,
+}
+
+// newRateLimiter - создает хранилище корзин по rate_limit.backend; без Postgres корзины всегда в памяти
+func newRateLimiter(cfg config.RateLimitConfig, db *pgxpool.Pool, log *slog.Logger) (ratelimit.Limiter, error) {
+	switch cfg.Backend {
+	case "", "memory":
+		return ratelimit.NewMemory(), nil
+	case "postgres":
+		if db == nil {
+			log.Warn("Postgres is not available, rate limit buckets are kept in memory")
+			return ratelimit.NewMemory(), nil
+		}
+		return ratelimit.NewPostgres(db, log), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend %q", cfg.Backend)
+	}
+}
+
+// rateLimitUnaryInterceptor - ограничивает частоту вызовов каждого метода по адресу клиента
+// и по аутентифицированному пользователю. Должен идти после auth-интерсептора.
+// Если запрос отклоняет одна из корзин, токены, уже списанные из других, возвращаются,
+// чтобы отклоненные запросы пользователя не расходовали лимит общего адреса.
+// При ошибке хранилища корзин запрос пропускается, чтобы лимитер не останавливал сервис.
+func rateLimitUnaryInterceptor(limiter ratelimit.Limiter, cfg config.RateLimitConfig, log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if rateLimitExempt(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		rule := methodRateLimit(cfg, info.FullMethod)
+
+		type bucket struct {
+			key   string
+			limit config.RateLimit
+		}
+		var buckets []bucket
+		if address := clientAddress(ctx); address != "" {
+			buckets = append(buckets, bucket{"peer:" + info.FullMethod + ":" + address, rule.Peer})
+		}
+		if identity, ok := auth.FromContext(ctx); ok {
+			buckets = append(buckets, bucket{"user:" + info.FullMethod + ":" + identity.UserID.String(), rule.User})
+		}
+
+		var taken []bucket
+		for _, b := range buckets {
+			limit := ratelimit.Limit{Rate: b.limit.Rate, Burst: b.limit.Burst}
+			allowed, retryAfter, err := limiter.Allow(ctx, b.key, limit)
+			if err != nil {
+				log.WarnContext(ctx, "Rate limiter failed, allowing request", "error", err)
+				continue
+			}
+			if !allowed {
+				for _, t := range taken {
+					limit := ratelimit.Limit{Rate: t.limit.Rate, Burst: t.limit.Burst}
+					if err := limiter.Refund(ctx, t.key, limit); err != nil {
+						log.WarnContext(ctx, "Failed to refund rate limit token", "error", err)
+					}
+				}
+				seconds := strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+				_ = grpc.SetHeader(ctx, metadata.Pairs(retryAfterHeader, seconds))
+				return nil, grpcerr.ToStatus(&ratelimit.RetryError{RetryAfter: retryAfter})
+			}
+			taken = append(taken, b)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// methodRateLimit - лимиты метода: запись из rate_limit.methods поверх rate_limit.default
+func methodRateLimit(cfg config.RateLimitConfig, fullMethod string) config.RateLimitRule {
+	rule := cfg.Default
+	override, ok := cfg.Methods[fullMethod]
+	if !ok {
+		return rule
+	}
+	if override.User.Rate > 0 {
+		rule.User = override.User
+	}
+	if override.Peer.Rate > 0 {
+		rule.Peer = override.Peer
+	}
+	return rule
+}
+
+// rateLimitExempt - не действуют ли на метод лимиты
+func rateLimitExempt(fullMethod string) bool {
+	for _, service := range rateLimitExemptServices {
+		if strings.HasPrefix(fullMethod, "/"+service+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// clientAddress - IP-адрес клиента. Для запросов REST-шлюза (приходят с loopback)
+// берется последний адрес из x-forwarded-for - его видел сам шлюз, и клиент не может его подделать.
+func clientAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if forwarded := md.Get(forwardedForHeader); len(forwarded) > 0 {
+			hops := strings.Split(forwarded[len(forwarded)-1], ",")
+			if last := strings.TrimSpace(hops[len(hops)-1]); last != "" {
+				return last
+			}
+		}
+	}
+	return host
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"user-service/config"
+	"user-service/internal/adapter/ratelimit"
+	"user-service/internal/auth"
+)
+
+func TestRateLimitUnaryInterceptorRefundsPeerToken(t *testing.T) {
+	const method = "/user.UserService/GetUserProducts"
+	// Лимиты почти не пополняются за время теста
+	peerLimit := config.RateLimit{Rate: 0.001, Burst: 2}
+	cfg := config.RateLimitConfig{Default: config.RateLimitRule{
+		User: config.RateLimit{Rate: 0.001, Burst: 1},
+		Peer: peerLimit,
+	}}
+	limiter := ratelimit.NewMemory()
+	interceptor := rateLimitUnaryInterceptor(limiter, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	address := &net.TCPAddr{IP: net.ParseIP("192.0.2.10"), Port: 5000}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: address})
+	ctx = auth.WithIdentity(ctx, auth.Identity{UserID: uuid.New()})
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	if _, err := interceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	// Корзина пользователя пуста, запрос отклоняется, токен адреса возвращается
+	if _, err := interceptor(ctx, nil, info, handler); err == nil {
+		t.Fatal("second call was not rate limited")
+	}
+
+	key := "peer:" + method + ":192.0.2.10"
+	allowed, _, err := limiter.Allow(context.Background(), key, ratelimit.Limit{Rate: peerLimit.Rate, Burst: peerLimit.Burst})
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if !allowed {
+		t.Error("rejected request consumed a peer token")
+	}
+}
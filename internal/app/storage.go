@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"user-service/config"
+	"user-service/internal/adapter/postgres"
+	"user-service/internal/adapter/sqlite"
+	"user-service/internal/health"
+	"user-service/internal/metrics"
+	"user-service/internal/repository"
+)
+
+// storage - репозитории приложения и зависимость, по которой определяется готовность
+type storage struct {
+	users       repository.Repository
+	catalog     repository.CatalogRepository
+	revocations repository.RevocationRepository
+	outbox      repository.OutboxRepository
+	pinger      health.Pinger
+	// db - пул соединений Postgres, nil для остальных хранилищ
+	db *pgxpool.Pool
+}
+
+// newStorage - в режиме разработки создает хранилище в памяти, иначе открывает
+// хранилище storage.driver. Закрытие хранилища регистрируется в lifecycle.
+func newStorage(ctx context.Context, cfg *config.Config, devMode bool, log *slog.Logger, lifecycle *lifecycle) (storage, error) {
+	if devMode {
+		return newMemoryStorage(cfg.Dev, log, lifecycle)
+	}
+	switch cfg.Storage.Driver {
+	case "postgres":
+		return newPostgresStorage(ctx, cfg, log, lifecycle)
+	case "sqlite":
+		return newSQLiteStorage(ctx, cfg.Storage.SQLite, log, lifecycle)
+	default:
+		return storage{}, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
+
+// newPostgresStorage - пул соединений с Postgres и репозитории поверх него
+func newPostgresStorage(ctx context.Context, cfg *config.Config, log *slog.Logger, lifecycle *lifecycle) (storage, error) {
+	dbpool, err := postgres.New(ctx, *cfg)
+	if err != nil {
+		return storage{}, err
+	}
+	lifecycle.onShutdown("database pool", func(context.Context) error {
+		dbpool.Close()
+		return nil
+	})
+	log.Info("Database connection established")
+
+	// Изоляция данных пользователей держится на политиках RLS, поэтому сервис не запускается
+	// от роли, для которой они не действуют
+	if err := postgres.CheckRowLevelSecurity(ctx, dbpool); err != nil {
+		return storage{}, err
+	}
+
+	if err := metrics.RegisterPool(dbpool); err != nil {
+		return storage{}, err
+	}
+
+	return storage{
+		users:       repository.New(dbpool, log),
+		catalog:     repository.NewCatalog(dbpool, log),
+		revocations: repository.NewRevocation(dbpool, log),
+		outbox:      repository.NewOutbox(dbpool, log),
+		pinger:      dbpool,
+		db:          dbpool,
+	}, nil
+}
+
+// newSQLiteStorage - хранилище в файле SQLite, схема создается командой migrate
+func newSQLiteStorage(ctx context.Context, cfg config.SQLiteConfig, log *slog.Logger, lifecycle *lifecycle) (storage, error) {
+	db, err := sqlite.New(ctx, cfg)
+	if err != nil {
+		return storage{}, err
+	}
+	lifecycle.onShutdown("database", func(context.Context) error {
+		return db.Close()
+	})
+	log.Info("SQLite database opened", "path", cfg.Path)
+
+	repo := repository.NewSQLite(db, log)
+	return storage{
+		users:       repo,
+		catalog:     repo,
+		revocations: repo,
+		outbox:      repo,
+		pinger:      repo,
+	}, nil
+}
+
+// newMemoryStorage - хранилище в памяти; если задан dev.snapshot_file, состояние
+// читается из него при запуске и записывается в него при остановке
+func newMemoryStorage(cfg config.DevConfig, log *slog.Logger, lifecycle *lifecycle) (storage, error) {
+	memory := repository.NewMemory(log)
+	if cfg.SnapshotFile != "" {
+		if err := memory.LoadSnapshot(cfg.SnapshotFile); err != nil {
+			return storage{}, err
+		}
+		lifecycle.onShutdown("dev snapshot", func(context.Context) error {
+			return memory.SaveSnapshot(cfg.SnapshotFile)
+		})
+	}
+	log.Warn("Development mode: using in-memory storage")
+
+	return storage{
+		users:       memory,
+		catalog:     memory,
+		revocations: memory,
+		outbox:      memory,
+		pinger:      memory,
+	}, nil
+}
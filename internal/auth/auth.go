@@ -0,0 +1,68 @@
+// Package auth хранит личность аутентифицированного пользователя в контексте запроса.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"user-service/internal/adapter/token"
+	"user-service/internal/repository"
+)
+
+var (
+	// ErrMissingToken - ошибка, когда в запросе нет токена доступа
+	ErrMissingToken = errors.New("access token is missing")
+	// ErrInvalidToken - ошибка, когда токен недействителен
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrTokenRevoked - ошибка, когда токен отозван до истечения срока действия
+	ErrTokenRevoked = errors.New("token revoked")
+)
+
+// Identity - аутентифицированный пользователь запроса
+type Identity struct {
+	// UserID - идентификатор пользователя из subject токена
+	UserID uuid.UUID
+}
+
+// identityKey - ключ контекста для Identity
+type identityKey struct{}
+
+// WithIdentity - возвращает контекст с личностью пользователя.
+// Запросы Postgres в этом контексте видят только строки этого пользователя.
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	ctx = repository.WithUserScope(ctx, identity.UserID.String())
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext - достает личность пользователя из контекста
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// UserID - достает идентификатор пользователя из контекста,
+// возвращает ErrMissingToken, если запрос не был аутентифицирован
+func UserID(ctx context.Context) (uuid.UUID, error) {
+	identity, ok := FromContext(ctx)
+	if !ok {
+		return uuid.Nil, ErrMissingToken
+	}
+	return identity.UserID, nil
+}
+
+// Authenticate - проверяет токен доступа, его отзыв и возвращает личность пользователя
+func Authenticate(tokens token.Token, revocations *RevocationList, accessToken string) (Identity, error) {
+	if accessToken == "" {
+		return Identity{}, ErrMissingToken
+	}
+	claims, err := tokens.VerifyToken(accessToken)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if revocations.IsRevoked(claims) {
+		return Identity{}, ErrTokenRevoked
+	}
+	return Identity{UserID: claims.UserID}, nil
+}
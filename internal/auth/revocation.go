@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"user-service/internal/adapter/token"
+	"user-service/internal/entity"
+	"user-service/internal/repository"
+)
+
+const (
+	// revocationRetryDelay - пауза перед повторной подпиской на отзывы после разрыва соединения
+	revocationRetryDelay = 5 * time.Second
+	// revocationPruneInterval - период удаления из памяти отзывов истекших токенов
+	revocationPruneInterval = time.Minute
+)
+
+// RevocationList - кэш отзывов токенов в памяти.
+// Источник истины - таблицы отзывов в Postgres, изменения приходят через LISTEN/NOTIFY.
+type RevocationList struct {
+	repo repository.RevocationRepository
+	log  *slog.Logger
+
+	mu sync.RWMutex
+	// tokens - срок действия отозванных токенов по jti
+	tokens map[string]time.Time
+	// users - граница отзыва по идентификатору пользователя
+	users map[string]time.Time
+}
+
+// NewRevocationList - создает пустой кэш отзывов, его нужно заполнить через Load или Run
+func NewRevocationList(repo repository.RevocationRepository, log *slog.Logger) *RevocationList {
+	return &RevocationList{
+		repo:   repo,
+		log:    log,
+		tokens: make(map[string]time.Time),
+		users:  make(map[string]time.Time),
+	}
+}
+
+// IsRevoked - отозван ли токен по jti или границей отзыва его владельца.
+// iat хранится с точностью до секунды, поэтому граница тоже отбрасывает доли секунды:
+// токен отозван, если iat <= revoked_before, усеченного до секунд. Токены, выпущенные в ту же
+// секунду, что и граница, считаются отозванными, даже если выпущены чуть позже нее.
+// Токен без iat нельзя сравнить с границей, поэтому он считается отозванным.
+func (l *RevocationList) IsRevoked(claims token.Claims) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if claims.ID != "" {
+		if _, ok := l.tokens[claims.ID]; ok {
+			return true
+		}
+	}
+	if before, ok := l.users[claims.UserID.String()]; ok {
+		return claims.IssuedAt.IsZero() || !claims.IssuedAt.After(before.Truncate(time.Second))
+	}
+	return false
+}
+
+// Load - перечитывает все действующие отзывы из хранилища и заменяет ими кэш
+func (l *RevocationList) Load(ctx context.Context) error {
+	revocations, err := l.repo.ListRevocations(ctx)
+	if err != nil {
+		return err
+	}
+	tokens := make(map[string]time.Time, len(revocations.Tokens))
+	users := make(map[string]time.Time, len(revocations.Users))
+	for _, revoked := range revocations.Tokens {
+		tokens[revoked.TokenID] = revoked.ExpiresAt
+	}
+	for _, revocation := range revocations.Users {
+		users[revocation.UserID] = revocation.RevokedBefore
+	}
+	l.mu.Lock()
+	l.tokens = tokens
+	l.users = users
+	l.mu.Unlock()
+	return nil
+}
+
+// Apply - добавляет отзывы в кэш, границы отзыва пользователей не сдвигаются назад
+func (l *RevocationList) Apply(revocations entity.Revocations) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, revoked := range revocations.Tokens {
+		if revoked.ExpiresAt.After(l.tokens[revoked.TokenID]) {
+			l.tokens[revoked.TokenID] = revoked.ExpiresAt
+		}
+	}
+	for _, revocation := range revocations.Users {
+		if revocation.RevokedBefore.After(l.users[revocation.UserID]) {
+			l.users[revocation.UserID] = revocation.RevokedBefore
+		}
+	}
+}
+
+// Run - поддерживает кэш в актуальном состоянии до отмены ctx.
+// После каждой подписки на уведомления список перечитывается целиком,
+// чтобы не потерять отзывы, сделанные пока подписки не было.
+func (l *RevocationList) Run(ctx context.Context) {
+	pruned := make(chan struct{})
+	go func() {
+		defer close(pruned)
+		l.pruneLoop(ctx)
+	}()
+	defer func() { <-pruned }()
+	for {
+		err := l.repo.WatchRevocations(ctx, func() error { return l.Load(ctx) }, l.Apply)
+		if ctx.Err() != nil {
+			return
+		}
+		l.log.WarnContext(ctx, "Token revocation subscription lost, retrying", "retry_in", revocationRetryDelay, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(revocationRetryDelay):
+		}
+	}
+}
+
+// pruneLoop - периодически удаляет из кэша отзывы токенов, срок действия которых истек
+func (l *RevocationList) pruneLoop(ctx context.Context) {
+	ticker := time.NewTicker(revocationPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			l.mu.Lock()
+			for tokenId, expiresAt := range l.tokens {
+				if expiresAt.Before(now) {
+					delete(l.tokens, tokenId)
+				}
+			}
+			l.mu.Unlock()
+		}
+	}
+}
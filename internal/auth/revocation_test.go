@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"user-service/internal/adapter/token"
+	"user-service/internal/entity"
+)
+
+func TestRevocationListIsRevokedUserBoundary(t *testing.T) {
+	userId := uuid.New()
+	// Граница с долями секунды, iat в токенах - целые секунды
+	revokedBefore := time.Date(2026, time.March, 1, 12, 0, 0, 500_000_000, time.UTC)
+	second := revokedBefore.Truncate(time.Second)
+
+	tests := []struct {
+		name    string
+		claims  token.Claims
+		revoked bool
+	}{
+		{name: "issued a second earlier", claims: token.Claims{UserID: userId, IssuedAt: second.Add(-time.Second)}, revoked: true},
+		{name: "issued in the boundary second", claims: token.Claims{UserID: userId, IssuedAt: second}, revoked: true},
+		{name: "issued a second later", claims: token.Claims{UserID: userId, IssuedAt: second.Add(time.Second)}, revoked: false},
+		{name: "without iat", claims: token.Claims{UserID: userId}, revoked: true},
+		{name: "other user", claims: token.Claims{UserID: uuid.New(), IssuedAt: second}, revoked: false},
+	}
+
+	list := NewRevocationList(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	list.Apply(entity.Revocations{Users: []entity.UserTokensRevocation{{UserID: userId.String(), RevokedBefore: revokedBefore}}})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := list.IsRevoked(tt.claims); got != tt.revoked {
+				t.Errorf("IsRevoked = %v, want %v", got, tt.revoked)
+			}
+		})
+	}
+}
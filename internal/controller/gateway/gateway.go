@@ -0,0 +1,46 @@
+// Package gateway отдает UserService по REST/JSON.
+// Запросы транслируются в gRPC и отправляются в gRPC-сервер этого же процесса,
+// поэтому на них действуют те же интерсепторы, что и на обычные gRPC-вызовы.
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+
+	"user-service/gen/openapi"
+	pb "user-service/gen/user"
+)
+
+// New - создает HTTP-обработчик шлюза: маршруты /v1/... и OpenAPI-документ /openapi.json
+func New(ctx context.Context, conn *grpc.ClientConn) (http.Handler, error) {
+	gateway := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(incomingHeader))
+	if err := pb.RegisterUserServiceHandler(ctx, gateway, conn); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/", gateway)
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(openapi.Spec)
+	})
+	return mux, nil
+}
+
+// traceHeaders - заголовки W3C trace context, которые передаются в gRPC как есть,
+// чтобы трасса REST-запроса продолжилась на gRPC-сервере
+var traceHeaders = []string{"traceparent", "tracestate", "baggage"}
+
+// incomingHeader - к стандартным заголовкам, передаваемым в метаданные, добавляет trace context
+func incomingHeader(key string) (string, bool) {
+	for _, header := range traceHeaders {
+		if strings.EqualFold(key, header) {
+			return header, true
+		}
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
@@ -0,0 +1,27 @@
+// Package apikey проверяет ключи административных и внутренних RPC.
+package apikey
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Header - ключ метаданных, в котором передается ключ API
+const Header = "x-api-key"
+
+// Valid - передан ли в метаданных запроса ключ expected.
+// Пустой expected не совпадает ни с чем.
+func Valid(ctx context.Context, expected string) bool {
+	if expected == "" {
+		return false
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range md.Get(Header) {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(expected)) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package grpccatalog
+
+import (
+	"context"
+	"log/slog"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "user-service/gen/user"
+	"user-service/internal/controller/grpc/apikey"
+	"user-service/internal/controller/grpc/grpcerr"
+	"user-service/internal/entity"
+	"user-service/internal/usecase/catalog"
+)
+
+var _ pb.CatalogServiceServer = (*CatalogServer)(nil)
+
+// CatalogServer - структура для обработки административных RPC-методов каталога
+type CatalogServer struct {
+	pb.UnimplementedCatalogServiceServer
+	catalog catalog.CatalogUseCase
+	apiKey  string
+	log     *slog.Logger
+}
+
+// New - конструктор для CatalogServer, пустой apiKey выключает все методы
+func New(catalog catalog.CatalogUseCase, apiKey string, log *slog.Logger) *CatalogServer {
+	return &CatalogServer{catalog: catalog, apiKey: apiKey, log: log}
+}
+
+// UpsertCatalogProduct - метод для создания или обновления записи каталога
+func (s *CatalogServer) UpsertCatalogProduct(ctx context.Context, req *pb.UpsertCatalogProductRequest) (*pb.UpsertCatalogProductResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+	product := req.GetProduct()
+	saved, err := s.catalog.UpsertProduct(ctx, entity.CatalogProduct{
+		CanonicalName: product.GetCanonicalName(),
+		Category:      product.GetCategory(),
+		DisplayNames:  product.GetDisplayNames(),
+		Aliases:       product.GetAliases(),
+	})
+	if err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+
+	response := &pb.UpsertCatalogProductResponse{
+		Product: catalogProductToProto(saved),
+	}
+
+	return response, nil
+}
+
+// DeleteCatalogProduct - метод для удаления записи каталога
+func (s *CatalogServer) DeleteCatalogProduct(ctx context.Context, req *pb.DeleteCatalogProductRequest) (*pb.DeleteCatalogProductResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+	if err := s.catalog.DeleteProduct(ctx, req.Id); err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+
+	response := &pb.DeleteCatalogProductResponse{
+		Success: true,
+	}
+
+	return response, nil
+}
+
+// ListCatalogProducts - метод для получения страницы записей каталога
+func (s *CatalogServer) ListCatalogProducts(ctx context.Context, req *pb.ListCatalogProductsRequest) (*pb.ListCatalogProductsResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+	products, nextPageToken, err := s.catalog.ListProducts(ctx, req.NamePrefix, int(req.PageSize), req.PageToken)
+	if err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+
+	response := &pb.ListCatalogProductsResponse{
+		Products:      make([]*pb.CatalogProduct, 0, len(products)),
+		NextPageToken: nextPageToken,
+	}
+	for _, product := range products {
+		response.Products = append(response.Products, catalogProductToProto(product))
+	}
+
+	return response, nil
+}
+
+// authorize - проверяет ключ административного API из метаданных запроса
+func (s *CatalogServer) authorize(ctx context.Context) error {
+	if s.apiKey == "" {
+		return grpcerr.ErrAdminDisabled
+	}
+	if !apikey.Valid(ctx, s.apiKey) {
+		return grpcerr.ErrInvalidAPIKey
+	}
+	return nil
+}
+
+// catalogProductToProto - преобразует запись каталога в protobuf-сообщение
+func catalogProductToProto(product entity.CatalogProduct) *pb.CatalogProduct {
+	return &pb.CatalogProduct{
+		Id:            product.ID,
+		CanonicalName: product.CanonicalName,
+		Category:      product.Category,
+		DisplayNames:  product.DisplayNames,
+		Aliases:       product.Aliases,
+		CreatedAt:     timestamppb.New(product.CreatedAt),
+		UpdatedAt:     timestamppb.New(product.UpdatedAt),
+	}
+}
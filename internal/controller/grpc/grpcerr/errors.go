@@ -0,0 +1,191 @@
+// Package grpcerr переводит доменные ошибки в gRPC-статусы.
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"user-service/internal/adapter/ratelimit"
+	"user-service/internal/adapter/token"
+	"user-service/internal/auth"
+	"user-service/internal/repository"
+	usecase "user-service/internal/usecase/user"
+)
+
+// Ошибки транспортного уровня
+var (
+	// ErrAdminDisabled - административные RPC выключены, так как не задан admin.api_key
+	ErrAdminDisabled = errors.New("admin api is disabled")
+	// ErrInvalidAPIKey - в метаданных нет ключа административного API или он неверный
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	// ErrInternalDisabled - внутренние RPC выключены, так как не задан internal.api_key
+	ErrInternalDisabled = errors.New("internal api is disabled")
+)
+
+// ErrorDomain - домен, который указывается в google.rpc.ErrorInfo
+const ErrorDomain = "user-service"
+
+// Стабильные коды причин ошибок, на которые могут опираться клиенты
+const (
+	ReasonProductNotFound      = "PRODUCT_NOT_FOUND"
+	ReasonProductAlreadyExists = "PRODUCT_ALREADY_EXISTS"
+	ReasonPreferenceNotFound   = "PREFERENCE_NOT_FOUND"
+	ReasonPreferenceExists     = "PREFERENCE_ALREADY_EXISTS"
+	ReasonTokenExpired         = "TOKEN_EXPIRED"
+	ReasonInvalidToken         = "INVALID_TOKEN"
+	ReasonMissingToken         = "MISSING_TOKEN"
+	ReasonInvalidArgument      = "INVALID_ARGUMENT"
+	ReasonStorageUnavailable   = "STORAGE_UNAVAILABLE"
+	ReasonConcurrentUpdate     = "CONCURRENT_UPDATE"
+	ReasonDeadlineExceeded     = "DEADLINE_EXCEEDED"
+	ReasonCanceled             = "CANCELED"
+	ReasonBatchAborted         = "BATCH_ABORTED"
+	ReasonCatalogNotFound      = "CATALOG_PRODUCT_NOT_FOUND"
+	ReasonAliasConflict        = "CATALOG_ALIAS_CONFLICT"
+	ReasonInvalidAPIKey        = "INVALID_API_KEY"
+	ReasonAdminDisabled        = "ADMIN_API_DISABLED"
+	ReasonInternalDisabled     = "INTERNAL_API_DISABLED"
+	ReasonTokenRevoked         = "TOKEN_REVOKED"
+	ReasonRateLimited          = "RATE_LIMITED"
+	ReasonInternal             = "INTERNAL"
+)
+
+// errorMapping - соответствие доменной ошибки коду gRPC и причине
+type errorMapping struct {
+	target error
+	code   codes.Code
+	reason string
+}
+
+// errorMappings - таблица трансляции доменных ошибок.
+// Порядок важен: более конкретные ошибки должны идти раньше общих.
+var errorMappings = []errorMapping{
+	{repository.ErrProductNotFound, codes.NotFound, ReasonProductNotFound},
+	{repository.ErrPreferenceNotFound, codes.NotFound, ReasonPreferenceNotFound},
+	{repository.ErrProductAlreadyExists, codes.AlreadyExists, ReasonProductAlreadyExists},
+	{repository.ErrPreferenceExists, codes.AlreadyExists, ReasonPreferenceExists},
+	{repository.ErrCatalogProductNotFound, codes.NotFound, ReasonCatalogNotFound},
+	{repository.ErrCatalogAliasConflict, codes.AlreadyExists, ReasonAliasConflict},
+	{ErrInvalidAPIKey, codes.Unauthenticated, ReasonInvalidAPIKey},
+	{ErrAdminDisabled, codes.PermissionDenied, ReasonAdminDisabled},
+	{ErrInternalDisabled, codes.PermissionDenied, ReasonInternalDisabled},
+	{token.ErrAccessTokenExpired, codes.Unauthenticated, ReasonTokenExpired},
+	{jwt.ErrTokenExpired, codes.Unauthenticated, ReasonTokenExpired},
+	{auth.ErrMissingToken, codes.Unauthenticated, ReasonMissingToken},
+	{auth.ErrTokenRevoked, codes.Unauthenticated, ReasonTokenRevoked},
+	{auth.ErrInvalidToken, codes.Unauthenticated, ReasonInvalidToken},
+	{token.ErrInvalidToken, codes.Unauthenticated, ReasonInvalidToken},
+	{repository.ErrSerializationFailure, codes.Aborted, ReasonConcurrentUpdate},
+	// Повторять стоит только запросы, которые не дошли до хранилища; остальные ошибки
+	// хранилища повторятся и при следующей попытке
+	{repository.ErrConnectionFailed, codes.Unavailable, ReasonStorageUnavailable},
+	{repository.ErrInvalidData, codes.InvalidArgument, ReasonInvalidArgument},
+	{repository.ErrQueryFailed, codes.Internal, ReasonInternal},
+	{repository.ErrAddUserFailed, codes.Internal, ReasonInternal},
+	{repository.ErrPreferenceUpdateFailed, codes.Internal, ReasonInternal},
+	{repository.ErrTransactionFailed, codes.Internal, ReasonInternal},
+	{repository.ErrBatchAborted, codes.Aborted, ReasonBatchAborted},
+	{ratelimit.ErrRateLimited, codes.ResourceExhausted, ReasonRateLimited},
+	{context.DeadlineExceeded, codes.DeadlineExceeded, ReasonDeadlineExceeded},
+	{context.Canceled, codes.Canceled, ReasonCanceled},
+}
+
+// ToStatus - переводит ошибку usecase-слоя в gRPC-статус с деталями
+// google.rpc.ErrorInfo и, для ошибок валидации, google.rpc.BadRequest
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var fieldErr *usecase.FieldError
+	if errors.As(err, &fieldErr) {
+		return withDetails(status.New(codes.InvalidArgument, err.Error()),
+			&errdetails.ErrorInfo{
+				Reason:   ReasonInvalidArgument,
+				Domain:   ErrorDomain,
+				Metadata: map[string]string{"field": fieldErr.Field},
+			},
+			&errdetails.BadRequest{
+				FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+					Field:       fieldErr.Field,
+					Description: fieldErr.Err.Error(),
+				}},
+			},
+		)
+	}
+
+	code, reason, message := Classify(err)
+	info := &errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: ErrorDomain,
+	}
+
+	var retryErr *ratelimit.RetryError
+	if errors.As(err, &retryErr) {
+		return withDetails(status.New(code, message), info, &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(retryErr.RetryAfter),
+		})
+	}
+
+	return withDetails(status.New(code, message), info)
+}
+
+// Report - как ToStatus, но перед переводом пишет в лог исходную ошибку, если она не из-за клиента:
+// клиент получает только "internal error", а причину нужно искать в логе
+func Report(ctx context.Context, log *slog.Logger, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); !ok {
+		switch code, reason, _ := Classify(err); code {
+		case codes.Internal, codes.Unavailable, codes.Unknown:
+			log.ErrorContext(ctx, "Request failed", "code", code.String(), "reason", reason, "error", err)
+		}
+	}
+	return ToStatus(err)
+}
+
+// Classify - определяет код gRPC, причину и текст ошибки для клиента
+func Classify(err error) (code codes.Code, reason string, message string) {
+	var fieldErr *usecase.FieldError
+	if errors.As(err, &fieldErr) {
+		return codes.InvalidArgument, ReasonInvalidArgument, err.Error()
+	}
+	for _, m := range errorMappings {
+		if errors.Is(err, m.target) {
+			return m.code, m.reason, clientMessage(err)
+		}
+	}
+	// Неизвестные ошибки не раскрываем клиенту
+	return codes.Internal, ReasonInternal, "internal error"
+}
+
+// clientMessage - текст ошибки для клиента; ошибка драйвера из ошибки репозитория
+// остается только в логе
+func clientMessage(err error) string {
+	var repoErr *repository.Error
+	if errors.As(err, &repoErr) {
+		return repoErr.Kind.Error()
+	}
+	return err.Error()
+}
+
+// withDetails - прикрепляет детали к статусу, при неудаче возвращает статус без деталей
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,79 @@
+package grpcerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"user-service/internal/repository"
+)
+
+func TestToStatusStorageErrors(t *testing.T) {
+	driverErr := errors.New("driver error")
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{name: "query failed", err: repository.ErrQueryFailed,
+			wantCode: codes.Internal, wantReason: ReasonInternal},
+		{name: "add user failed", err: repository.ErrAddUserFailed,
+			wantCode: codes.Internal, wantReason: ReasonInternal},
+		{name: "preference update failed", err: repository.ErrPreferenceUpdateFailed,
+			wantCode: codes.Internal, wantReason: ReasonInternal},
+		{name: "transaction failed", err: repository.ErrTransactionFailed,
+			wantCode: codes.Internal, wantReason: ReasonInternal},
+		{name: "unclassified driver error",
+			err:      &repository.Error{Kind: repository.ErrQueryFailed, Err: driverErr},
+			wantCode: codes.Internal, wantReason: ReasonInternal},
+		{name: "connection failed",
+			err:      &repository.Error{Kind: repository.ErrQueryFailed, Class: repository.ErrConnectionFailed, Err: driverErr},
+			wantCode: codes.Unavailable, wantReason: ReasonStorageUnavailable},
+		{name: "connection failed in transaction",
+			err:      fmt.Errorf("commit: %w", &repository.Error{Kind: repository.ErrTransactionFailed, Class: repository.ErrConnectionFailed, Err: driverErr}),
+			wantCode: codes.Unavailable, wantReason: ReasonStorageUnavailable},
+		{name: "serialization failure",
+			err:      &repository.Error{Kind: repository.ErrTransactionFailed, Class: repository.ErrSerializationFailure, Err: driverErr},
+			wantCode: codes.Aborted, wantReason: ReasonConcurrentUpdate},
+		{name: "invalid data",
+			err:      &repository.Error{Kind: repository.ErrQueryFailed, Class: repository.ErrInvalidData, Err: driverErr},
+			wantCode: codes.InvalidArgument, wantReason: ReasonInvalidArgument},
+		{name: "invalid data when adding user",
+			err:      &repository.Error{Kind: repository.ErrAddUserFailed, Class: repository.ErrInvalidData, Err: driverErr},
+			wantCode: codes.InvalidArgument, wantReason: ReasonInvalidArgument},
+		{name: "domain error takes precedence over class",
+			err:      &repository.Error{Kind: repository.ErrProductAlreadyExists, Class: repository.ErrUniqueViolation, Err: driverErr},
+			wantCode: codes.AlreadyExists, wantReason: ReasonProductAlreadyExists},
+		{name: "unknown error", err: errors.New("boom"),
+			wantCode: codes.Internal, wantReason: ReasonInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := status.FromError(ToStatus(tt.err))
+			if !ok {
+				t.Fatal("ToStatus did not return a gRPC status")
+			}
+			if st.Code() != tt.wantCode {
+				t.Errorf("code = %s, want %s", st.Code(), tt.wantCode)
+			}
+			var reason string
+			for _, detail := range st.Details() {
+				if info, ok := detail.(*errdetails.ErrorInfo); ok {
+					reason = info.Reason
+				}
+			}
+			if reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", reason, tt.wantReason)
+			}
+			if errors.Is(tt.err, driverErr) && st.Message() == tt.err.Error() {
+				t.Errorf("message %q exposes the driver error", st.Message())
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package grpcrevocation
+
+import (
+	"context"
+	"log/slog"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "user-service/gen/user"
+	"user-service/internal/controller/grpc/apikey"
+	"user-service/internal/controller/grpc/grpcerr"
+	"user-service/internal/entity"
+	"user-service/internal/usecase/revocation"
+)
+
+var _ pb.TokenRevocationServiceServer = (*RevocationServer)(nil)
+
+// RevocationServer - структура для обработки внутренних RPC-методов отзыва токенов
+type RevocationServer struct {
+	pb.UnimplementedTokenRevocationServiceServer
+	revocation revocation.RevocationUseCase
+	apiKey     string
+	log        *slog.Logger
+}
+
+// New - конструктор для RevocationServer, пустой apiKey выключает все методы
+func New(revocation revocation.RevocationUseCase, apiKey string, log *slog.Logger) *RevocationServer {
+	return &RevocationServer{revocation: revocation, apiKey: apiKey, log: log}
+}
+
+// RevokeToken - метод для отзыва одного токена по jti
+func (s *RevocationServer) RevokeToken(ctx context.Context, req *pb.RevokeTokenRequest) (*pb.RevokeTokenResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+	token := entity.RevokedToken{
+		TokenID: req.Jti,
+		UserID:  req.UserId,
+	}
+	if req.ExpiresAt != nil {
+		token.ExpiresAt = req.ExpiresAt.AsTime()
+	}
+	if err := s.revocation.RevokeToken(ctx, token); err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+
+	response := &pb.RevokeTokenResponse{
+		Success: true,
+	}
+
+	return response, nil
+}
+
+// RevokeUserTokens - метод для отзыва всех токенов пользователя, выпущенных раньше заданного момента
+func (s *RevocationServer) RevokeUserTokens(ctx context.Context, req *pb.RevokeUserTokensRequest) (*pb.RevokeUserTokensResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+	revocation := entity.UserTokensRevocation{
+		UserID: req.UserId,
+	}
+	if req.RevokedBefore != nil {
+		revocation.RevokedBefore = req.RevokedBefore.AsTime()
+	}
+	saved, err := s.revocation.RevokeUserTokens(ctx, revocation)
+	if err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+
+	response := &pb.RevokeUserTokensResponse{
+		RevokedBefore: timestamppb.New(saved.RevokedBefore),
+	}
+
+	return response, nil
+}
+
+// authorize - проверяет ключ внутреннего API из метаданных запроса
+func (s *RevocationServer) authorize(ctx context.Context) error {
+	if s.apiKey == "" {
+		return grpcerr.ErrInternalDisabled
+	}
+	if !apikey.Valid(ctx, s.apiKey) {
+		return grpcerr.ErrInvalidAPIKey
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package grpcuser
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "user-service/gen/user"
+	"user-service/internal/controller/grpc/grpcerr"
+	"user-service/internal/entity"
+	"user-service/internal/repository"
+	usecase "user-service/internal/usecase/user"
+)
+
+// productToProto - преобразует доменный продукт в protobuf-сообщение
+func productToProto(product entity.Product) *pb.Product {
+	return &pb.Product{
+		Id:               product.ID,
+		Name:             product.Name,
+		CatalogProductId: product.CatalogProductID,
+		Category:         product.Category,
+		Quantity:         product.Quantity,
+		Unit:             product.Unit,
+		ExpiresAt:        timeToProto(product.ExpiresAt),
+		CreatedAt:        timestamppb.New(product.CreatedAt),
+		UpdatedAt:        timestamppb.New(product.UpdatedAt),
+	}
+}
+
+// productsToProto - преобразует список доменных продуктов в protobuf-сообщения
+func productsToProto(products []entity.Product) []*pb.Product {
+	result := make([]*pb.Product, 0, len(products))
+	for _, product := range products {
+		result = append(result, productToProto(product))
+	}
+	return result
+}
+
+// timeToProto - преобразует необязательное время в Timestamp, nil остается nil
+func timeToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// timeFromProto - преобразует необязательный Timestamp во время, nil остается nil
+func timeFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// productSortFields - соответствие полей сортировки из protobuf полям репозитория
+var productSortFields = map[pb.ProductSortField]repository.ProductSortField{
+	pb.ProductSortField_PRODUCT_SORT_FIELD_UNSPECIFIED: repository.SortByCreatedAt,
+	pb.ProductSortField_PRODUCT_SORT_FIELD_CREATED_AT:  repository.SortByCreatedAt,
+	pb.ProductSortField_PRODUCT_SORT_FIELD_NAME:        repository.SortByName,
+	pb.ProductSortField_PRODUCT_SORT_FIELD_EXPIRES_AT:  repository.SortByExpiresAt,
+}
+
+// productsRequestFromProto - преобразует запрос списка продуктов в параметры usecase
+func productsRequestFromProto(req *pb.GetProductsRequest) usecase.ProductsRequest {
+	filter := req.GetFilter()
+	return usecase.ProductsRequest{
+		PageSize:  int(req.PageSize),
+		PageToken: req.PageToken,
+		Filter: repository.ProductFilter{
+			NamePrefix:     filter.GetNamePrefix(),
+			ExpiringBefore: timeFromProto(filter.GetExpiringBefore()),
+			Category:       filter.GetCategory(),
+		},
+		SortBy:     productSortFields[req.SortBy],
+		Descending: req.Descending,
+	}
+}
+
+// productFromItem - преобразует элемент пакетного добавления в доменный продукт
+func productFromItem(item *pb.AddProductItem) entity.Product {
+	return entity.Product{
+		Name:      item.GetProductName(),
+		Quantity:  item.GetQuantity(),
+		Unit:      item.GetUnit(),
+		Category:  item.GetCategory(),
+		ExpiresAt: timeFromProto(item.GetExpiresAt()),
+	}
+}
+
+// batchResultsToProto - преобразует результаты пакетного запроса в protobuf-сообщения
+func batchResultsToProto(results []usecase.BatchResult, removal bool) []*pb.BatchItemResult {
+	items := make([]*pb.BatchItemResult, 0, len(results))
+	for i, result := range results {
+		item := &pb.BatchItemResult{Index: int32(i)}
+		switch {
+		case result.Err != nil:
+			code, reason, message := grpcerr.Classify(result.Err)
+			item.Code, item.Reason, item.Message = int32(code), reason, message
+		case removal:
+			item.ProductId = result.Product.ID
+		default:
+			item.Product = productToProto(result.Product)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// preferenceKinds - соответствие типов предпочтений из protobuf доменным типам
+var preferenceKinds = map[pb.PreferenceKind]entity.PreferenceKind{
+	pb.PreferenceKind_PREFERENCE_KIND_DIET:                entity.PreferenceDiet,
+	pb.PreferenceKind_PREFERENCE_KIND_ALLERGEN:            entity.PreferenceAllergen,
+	pb.PreferenceKind_PREFERENCE_KIND_DISLIKED_INGREDIENT: entity.PreferenceDislikedIngredient,
+}
+
+// preferenceEntryFromProto - преобразует запрос изменения одного предпочтения в доменное значение.
+// Неизвестный тип превращается в пустой и отклоняется валидацией usecase.
+func preferenceEntryFromProto(req *pb.PreferenceEntryRequest) entity.PreferenceEntry {
+	return entity.PreferenceEntry{
+		Kind:  preferenceKinds[req.Kind],
+		Value: req.Value,
+	}
+}
+
+// preferencesToProto - преобразует набор предпочтений в protobuf-сообщение
+func preferencesToProto(preferences entity.Preferences) *pb.Preferences {
+	result := &pb.Preferences{
+		Diets:               preferences.Diets,
+		Allergens:           preferences.Allergens,
+		DislikedIngredients: preferences.DislikedIngredients,
+		Targets: &pb.NutritionTargets{
+			Calories: preferences.Targets.Calories,
+			ProteinG: preferences.Targets.ProteinG,
+			FatG:     preferences.Targets.FatG,
+			CarbsG:   preferences.Targets.CarbsG,
+		},
+	}
+	if !preferences.UpdatedAt.IsZero() {
+		result.UpdatedAt = timestamppb.New(preferences.UpdatedAt)
+	}
+	return result
+}
+
+// preferencesFromUpdateRequest - достает новый набор предпочтений из запроса обновления.
+// Старые клиенты передают только preference_name, он становится единственным типом питания.
+func preferencesFromUpdateRequest(req *pb.UpdatePreferenceRequest) (entity.Preferences, error) {
+	if req.Preferences == nil {
+		//nolint:staticcheck // поле устарело, но поддерживается для старых клиентов
+		if req.PreferenceName == "" {
+			return entity.Preferences{}, &usecase.FieldError{Field: "preferences", Err: usecase.ErrEmptyPreferenceName}
+		}
+		//nolint:staticcheck
+		return entity.Preferences{Diets: []string{req.PreferenceName}}, nil
+	}
+	targets := req.Preferences.GetTargets()
+	return entity.Preferences{
+		Diets:               req.Preferences.Diets,
+		Allergens:           req.Preferences.Allergens,
+		DislikedIngredients: req.Preferences.DislikedIngredients,
+		Targets: entity.NutritionTargets{
+			Calories: targets.GetCalories(),
+			ProteinG: targets.GetProteinG(),
+			FatG:     targets.GetFatG(),
+			CarbsG:   targets.GetCarbsG(),
+		},
+	}, nil
+}
@@ -2,8 +2,11 @@ package grpcuser
 
 import (
 	"context"
+	"log/slog"
 
 	pb "user-service/gen/user"
+	"user-service/internal/controller/grpc/grpcerr"
+	"user-service/internal/entity"
 	usecase "user-service/internal/usecase/user"
 )
 
@@ -13,22 +16,24 @@ var _ pb.UserServiceServer = (*UserServer)(nil)
 type UserServer struct {
 	pb.UnimplementedUserServiceServer
 	user usecase.UserUseCase
+	log  *slog.Logger
 }
 
 // New - конструктор для UserServer
-func New(user usecase.UserUseCase) *UserServer {
-	return &UserServer{user: user}
+func New(user usecase.UserUseCase, log *slog.Logger) *UserServer {
+	return &UserServer{user: user, log: log}
 }
 
 // GetUserProducts - метод для получения продуктов пользователя
-func (s *UserServer) GetUserProducts(ctx context.Context, req *pb.UserRequest) (*pb.GetProductsResponse, error) {
-	products, err := s.user.GetUserProducts(req.AccessToken)
+func (s *UserServer) GetUserProducts(ctx context.Context, req *pb.GetProductsRequest) (*pb.GetProductsResponse, error) {
+	page, err := s.user.GetUserProducts(ctx, productsRequestFromProto(req))
 	if err != nil {
-		return nil, err
+		return nil, grpcerr.Report(ctx, s.log, err)
 	}
 
 	response := &pb.GetProductsResponse{
-		ProductNames: products,
+		Products:      productsToProto(page.Products),
+		NextPageToken: page.NextPageToken,
 	}
 
 	return response, nil
@@ -36,13 +41,16 @@ func (s *UserServer) GetUserProducts(ctx context.Context, req *pb.UserRequest) (
 
 // GetUserPreference - метод для получения предпочтений пользователя
 func (s *UserServer) GetUserPreference(ctx context.Context, req *pb.UserRequest) (*pb.GetPreferenceResponse, error) {
-	preference, err := s.user.GetUserPreference(req.AccessToken)
+	preferences, err := s.user.GetUserPreference(ctx)
 	if err != nil {
-		return nil, err
+		return nil, grpcerr.Report(ctx, s.log, err)
 	}
 
 	response := &pb.GetPreferenceResponse{
-		PreferenceName: preference,
+		Preferences: preferencesToProto(preferences),
+	}
+	if len(preferences.Diets) > 0 {
+		response.PreferenceName = preferences.Diets[0]
 	}
 
 	return response, nil
@@ -50,9 +58,13 @@ func (s *UserServer) GetUserPreference(ctx context.Context, req *pb.UserRequest)
 
 // UpdateUserPreference - метод для обновления предпочтений пользователя
 func (s *UserServer) UpdateUserPreference(ctx context.Context, req *pb.UpdatePreferenceRequest) (*pb.UpdatePreferenceResponse, error) {
-	err := s.user.UpdateUserPreference(req.AccessToken, req.PreferenceName)
+	preferences, err := preferencesFromUpdateRequest(req)
+	if err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+	err = s.user.UpdateUserPreference(ctx, preferences)
 	if err != nil {
-		return nil, err
+		return nil, grpcerr.Report(ctx, s.log, err)
 	}
 
 	response := &pb.UpdatePreferenceResponse{
@@ -64,9 +76,9 @@ func (s *UserServer) UpdateUserPreference(ctx context.Context, req *pb.UpdatePre
 
 // RemoveUserPreference - метод для удаления предпочтений пользователя
 func (s *UserServer) RemoveUserPreference(ctx context.Context, req *pb.RemovePreferenceRequest) (*pb.RemovePreferenceResponse, error) {
-	err := s.user.RemoveUserPreference(req.AccessToken)
+	err := s.user.RemoveUserPreference(ctx)
 	if err != nil {
-		return nil, err
+		return nil, grpcerr.Report(ctx, s.log, err)
 	}
 	response := &pb.RemovePreferenceResponse{
 		Success: true,
@@ -74,15 +86,52 @@ func (s *UserServer) RemoveUserPreference(ctx context.Context, req *pb.RemovePre
 	return response, nil
 }
 
+// AddUserPreferenceEntry - метод для добавления одного значения предпочтения
+func (s *UserServer) AddUserPreferenceEntry(ctx context.Context, req *pb.PreferenceEntryRequest) (*pb.PreferenceEntryResponse, error) {
+	preferences, err := s.user.AddUserPreferenceEntry(ctx, preferenceEntryFromProto(req))
+	if err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+
+	response := &pb.PreferenceEntryResponse{
+		Success:     true,
+		Preferences: preferencesToProto(preferences),
+	}
+
+	return response, nil
+}
+
+// RemoveUserPreferenceEntry - метод для удаления одного значения предпочтения
+func (s *UserServer) RemoveUserPreferenceEntry(ctx context.Context, req *pb.PreferenceEntryRequest) (*pb.PreferenceEntryResponse, error) {
+	preferences, err := s.user.RemoveUserPreferenceEntry(ctx, preferenceEntryFromProto(req))
+	if err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+
+	response := &pb.PreferenceEntryResponse{
+		Success:     true,
+		Preferences: preferencesToProto(preferences),
+	}
+
+	return response, nil
+}
+
 // AddUserProduct - метод для добавления продукта пользователю
 func (s *UserServer) AddUserProduct(ctx context.Context, req *pb.AddProductRequest) (*pb.AddProductResponse, error) {
-	err := s.user.AddUserProduct(req.AccessToken, req.ProductName)
+	product, err := s.user.AddUserProduct(ctx, entity.Product{
+		Name:      req.ProductName,
+		Quantity:  req.Quantity,
+		Unit:      req.Unit,
+		Category:  req.Category,
+		ExpiresAt: timeFromProto(req.ExpiresAt),
+	})
 	if err != nil {
-		return nil, err
+		return nil, grpcerr.Report(ctx, s.log, err)
 	}
 
 	response := &pb.AddProductResponse{
 		Success: true,
+		Product: productToProto(product),
 	}
 
 	return response, nil
@@ -90,9 +139,9 @@ func (s *UserServer) AddUserProduct(ctx context.Context, req *pb.AddProductReque
 
 // RemoveUserProduct - метод для удаления продукта у пользователя
 func (s *UserServer) RemoveUserProduct(ctx context.Context, req *pb.RemoveProductRequest) (*pb.RemoveProductResponse, error) {
-	err := s.user.RemoveUserProduct(req.AccessToken, req.ProductName)
+	err := s.user.RemoveUserProduct(ctx, req.ProductId, req.ProductName)
 	if err != nil {
-		return nil, err
+		return nil, grpcerr.Report(ctx, s.log, err)
 	}
 
 	response := &pb.RemoveProductResponse{
@@ -101,3 +150,37 @@ func (s *UserServer) RemoveUserProduct(ctx context.Context, req *pb.RemoveProduc
 
 	return response, nil
 }
+
+// BatchAddUserProducts - метод для пакетного добавления продуктов пользователю
+func (s *UserServer) BatchAddUserProducts(ctx context.Context, req *pb.BatchAddProductsRequest) (*pb.BatchAddProductsResponse, error) {
+	products := make([]entity.Product, 0, len(req.Items))
+	for _, item := range req.Items {
+		products = append(products, productFromItem(item))
+	}
+	result, err := s.user.BatchAddUserProducts(ctx, products, req.AllOrNothing)
+	if err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+
+	response := &pb.BatchAddProductsResponse{
+		Committed: result.Committed,
+		Results:   batchResultsToProto(result.Results, false),
+	}
+
+	return response, nil
+}
+
+// BatchRemoveUserProducts - метод для пакетного удаления продуктов у пользователя
+func (s *UserServer) BatchRemoveUserProducts(ctx context.Context, req *pb.BatchRemoveProductsRequest) (*pb.BatchRemoveProductsResponse, error) {
+	result, err := s.user.BatchRemoveUserProducts(ctx, req.ProductIds, req.AllOrNothing)
+	if err != nil {
+		return nil, grpcerr.Report(ctx, s.log, err)
+	}
+
+	response := &pb.BatchRemoveProductsResponse{
+		Committed: result.Committed,
+		Results:   batchResultsToProto(result.Results, true),
+	}
+
+	return response, nil
+}
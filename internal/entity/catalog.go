@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// CatalogProduct - запись канонического каталога продуктов
+type CatalogProduct struct {
+	// ID - канонический идентификатор продукта
+	ID string
+	// CanonicalName - каноническое название продукта
+	CanonicalName string
+	// Category - категория, которая подставляется продуктам пользователей
+	Category string
+	// DisplayNames - локализованные названия, ключ - локаль (ru, en и т.д.)
+	DisplayNames map[string]string
+	// Aliases - нормализованные синонимы, по которым находится продукт
+	Aliases []string
+	// CreatedAt - время создания записи
+	CreatedAt time.Time
+	// UpdatedAt - время последнего изменения записи
+	UpdatedAt time.Time
+}
+
+// NormalizeProductName - приводит название продукта к ключу поиска по синонимам:
+// нижний регистр, без пробелов по краям и с одинарными пробелами между словами
+func NormalizeProductName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// EventType - тип доменного события
+type EventType string
+
+const (
+	// EventProductAdded - пользователь добавил продукт
+	EventProductAdded EventType = "ProductAdded"
+	// EventProductRemoved - пользователь удалил продукт
+	EventProductRemoved EventType = "ProductRemoved"
+	// EventPreferenceChanged - изменился набор предпочтений пользователя
+	EventPreferenceChanged EventType = "PreferenceChanged"
+)
+
+// Event - доменное событие об изменении данных пользователя
+type Event struct {
+	// ID - порядковый номер события в outbox, задается хранилищем
+	ID     int64
+	Type   EventType
+	UserID string
+	// Payload - данные события в JSON
+	Payload []byte
+	// CreatedAt - время записи события в outbox
+	CreatedAt time.Time
+}
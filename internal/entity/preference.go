@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// PreferenceKind - тип пищевого предпочтения
+type PreferenceKind string
+
+const (
+	// PreferenceDiet - тип питания (вегетарианство, без лактозы и т.д.)
+	PreferenceDiet PreferenceKind = "diet"
+	// PreferenceAllergen - аллерген, который нужно исключить
+	PreferenceAllergen PreferenceKind = "allergen"
+	// PreferenceDislikedIngredient - нелюбимый ингредиент
+	PreferenceDislikedIngredient PreferenceKind = "disliked_ingredient"
+)
+
+// PreferenceEntry - одно значение предпочтения определенного типа
+type PreferenceEntry struct {
+	Kind  PreferenceKind
+	Value string
+}
+
+// NutritionTargets - суточные цели по калориям и БЖУ, нулевые значения означают "не задано"
+type NutritionTargets struct {
+	Calories int32
+	ProteinG float64
+	FatG     float64
+	CarbsG   float64
+}
+
+// Preferences - набор пищевых предпочтений пользователя
+type Preferences struct {
+	Diets               []string
+	Allergens           []string
+	DislikedIngredients []string
+	Targets             NutritionTargets
+	// UpdatedAt - время последнего изменения набора
+	UpdatedAt time.Time
+}
+
+// Entries - все значения набора в виде списка PreferenceEntry
+func (p Preferences) Entries() []PreferenceEntry {
+	entries := make([]PreferenceEntry, 0, len(p.Diets)+len(p.Allergens)+len(p.DislikedIngredients))
+	for _, value := range p.Diets {
+		entries = append(entries, PreferenceEntry{Kind: PreferenceDiet, Value: value})
+	}
+	for _, value := range p.Allergens {
+		entries = append(entries, PreferenceEntry{Kind: PreferenceAllergen, Value: value})
+	}
+	for _, value := range p.DislikedIngredients {
+		entries = append(entries, PreferenceEntry{Kind: PreferenceDislikedIngredient, Value: value})
+	}
+	return entries
+}
+
+// Add - добавляет значение в список, соответствующий его типу
+func (p *Preferences) Add(entry PreferenceEntry) {
+	switch entry.Kind {
+	case PreferenceDiet:
+		p.Diets = append(p.Diets, entry.Value)
+	case PreferenceAllergen:
+		p.Allergens = append(p.Allergens, entry.Value)
+	case PreferenceDislikedIngredient:
+		p.DislikedIngredients = append(p.DislikedIngredients, entry.Value)
+	}
+}
+
+// NormalizePreferenceValue - приводит значение предпочтения к единому виду:
+// нижний регистр и одинарные пробелы между словами
+func NormalizePreferenceValue(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
@@ -0,0 +1,26 @@
+// Package entity содержит доменные сущности сервиса.
+package entity
+
+import "time"
+
+// Product - продукт в кладовой пользователя
+type Product struct {
+	// ID - стабильный идентификатор записи
+	ID string
+	// Name - название продукта
+	Name string
+	// CatalogProductID - идентификатор записи каталога, пустой для пользовательских продуктов
+	CatalogProductID string
+	// Category - категория продукта (молочные продукты, крупы и т.д.)
+	Category string
+	// Quantity - количество продукта в единицах Unit
+	Quantity float64
+	// Unit - единица измерения (кг, л, шт и т.д.)
+	Unit string
+	// ExpiresAt - срок годности, nil если не указан
+	ExpiresAt *time.Time
+	// CreatedAt - время добавления продукта
+	CreatedAt time.Time
+	// UpdatedAt - время последнего изменения продукта
+	UpdatedAt time.Time
+}
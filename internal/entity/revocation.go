@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// RevokedToken - отзыв одного токена доступа по его jti
+type RevokedToken struct {
+	// TokenID - идентификатор токена (claim jti)
+	TokenID string
+	// UserID - владелец токена, пустой если неизвестен
+	UserID string
+	// ExpiresAt - срок действия токена, после него отзыв можно забыть
+	ExpiresAt time.Time
+}
+
+// UserTokensRevocation - отзыв всех токенов пользователя, выпущенных не позже RevokedBefore
+// с точностью до секунды (iat <= RevokedBefore, усеченного до секунд)
+type UserTokensRevocation struct {
+	UserID        string
+	RevokedBefore time.Time
+}
+
+// Revocations - действующие отзывы токенов
+type Revocations struct {
+	Tokens []RevokedToken
+	Users  []UserTokensRevocation
+}
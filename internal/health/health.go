@@ -0,0 +1,137 @@
+// Package health следит за готовностью сервиса и публикует ее
+// через grpc.health.v1.Health и HTTP-пробы /livez и /readyz.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// defaultInterval - период проверки зависимостей, если он не задан
+	defaultInterval = 5 * time.Second
+	// defaultTimeout - таймаут одной проверки, если он не задан
+	defaultTimeout = 2 * time.Second
+)
+
+// Pinger - зависимость, без которой сервис не готов обслуживать запросы
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Monitor - периодически проверяет зависимость и выставляет статус сервисов
+type Monitor struct {
+	pinger   Pinger
+	log      *slog.Logger
+	interval time.Duration
+	timeout  time.Duration
+	server   *grpchealth.Server
+	// services - сервисы, статус которых зависит от проверки; пустое имя - сервер целиком
+	services []string
+	ready    atomic.Bool
+	// stopping - сервис останавливается, проверки больше не меняют статус
+	stopping atomic.Bool
+}
+
+// New - создает монитор, до первой успешной проверки все сервисы в статусе NOT_SERVING
+func New(pinger Pinger, log *slog.Logger, interval, timeout time.Duration, services ...string) *Monitor {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	m := &Monitor{
+		pinger:   pinger,
+		log:      log,
+		interval: interval,
+		timeout:  timeout,
+		server:   grpchealth.NewServer(),
+		services: append([]string{""}, services...),
+	}
+	m.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	return m
+}
+
+// Server - реализация grpc.health.v1.Health для регистрации на gRPC-сервере
+func (m *Monitor) Server() healthpb.HealthServer {
+	return m.server
+}
+
+// Ready - прошла ли последняя проверка зависимости
+func (m *Monitor) Ready() bool {
+	return m.ready.Load()
+}
+
+// Run - проверяет зависимость сразу и далее с заданным периодом до отмены ctx
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shutdown - окончательно переводит все сервисы в NOT_SERVING перед остановкой,
+// чтобы балансировщики перестали направлять новые запросы
+func (m *Monitor) Shutdown() {
+	m.stopping.Store(true)
+	m.ready.Store(false)
+	m.server.Shutdown()
+}
+
+// check - выполняет одну проверку и обновляет статус при его изменении
+func (m *Monitor) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+	err := m.pinger.Ping(ctx)
+	ready := err == nil
+	if m.stopping.Load() || m.ready.Swap(ready) == ready {
+		return
+	}
+	if ready {
+		m.log.InfoContext(ctx, "Dependencies are reachable, reporting SERVING")
+		m.setStatus(healthpb.HealthCheckResponse_SERVING)
+	} else {
+		m.log.WarnContext(ctx, "Dependency check failed, reporting NOT_SERVING", "error", err)
+		m.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+}
+
+// setStatus - выставляет статус всем отслеживаемым сервисам
+func (m *Monitor) setStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	for _, service := range m.services {
+		m.server.SetServingStatus(service, status)
+	}
+}
+
+// Handler - HTTP-пробы: /livez отвечает 200, пока процесс жив,
+// /readyz - 200 при доступных зависимостях и 503 иначе
+func (m *Monitor) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /livez", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !m.Ready() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("not ready\n"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	return mux
+}
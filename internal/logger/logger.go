@@ -0,0 +1,109 @@
+// Package logger создает структурированный логгер log/slog из конфигурации:
+// формат JSON или текст, уровни по компонентам и автоматическое скрытие секретов и персональных данных.
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"user-service/config"
+)
+
+// ComponentKey - атрибут с именем компонента, по нему выбирается уровень из logger.packages
+const ComponentKey = "component"
+
+// New - создает корневой логгер
+func New(cfg config.LogConfig) (*slog.Logger, error) {
+	level, err := parseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	levels := make(map[string]slog.Level, len(cfg.Packages))
+	// Внутренний обработчик пропускает все, что может понадобиться хотя бы одному компоненту
+	minLevel := level
+	for component, value := range cfg.Packages {
+		componentLevel, err := parseLevel(value)
+		if err != nil {
+			return nil, fmt.Errorf("logger.packages.%s: %w", component, err)
+		}
+		levels[component] = componentLevel
+		minLevel = min(minLevel, componentLevel)
+	}
+
+	options := &slog.HandlerOptions{
+		Level:       minLevel,
+		ReplaceAttr: redactAttr,
+	}
+	var inner slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "", "json":
+		inner = slog.NewJSONHandler(os.Stdout, options)
+	case "text":
+		inner = slog.NewTextHandler(os.Stdout, options)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+
+	return slog.New(&componentHandler{inner: inner, levels: levels, level: level}), nil
+}
+
+// Component - логгер компонента; его уровень берется из logger.packages, если он там задан
+func Component(log *slog.Logger, name string) *slog.Logger {
+	return log.With(ComponentKey, name)
+}
+
+// parseLevel - разбирает уровень debug, info, warn или error
+func parseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if value == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q", value)
+	}
+	return level, nil
+}
+
+// componentHandler - применяет уровень компонента, заданный атрибутом ComponentKey
+type componentHandler struct {
+	inner  slog.Handler
+	levels map[string]slog.Level
+	level  slog.Level
+}
+
+func (h *componentHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// Handle - добавляет к записи идентификаторы трассы и спана, если запись сделана в контексте спана
+func (h *componentHandler) Handle(ctx context.Context, record slog.Record) error {
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+	return h.inner.Handle(ctx, record)
+}
+
+func (h *componentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	level := h.level
+	for _, attr := range attrs {
+		if attr.Key != ComponentKey {
+			continue
+		}
+		if componentLevel, ok := h.levels[attr.Value.String()]; ok {
+			level = componentLevel
+		}
+	}
+	return &componentHandler{inner: h.inner.WithAttrs(attrs), levels: h.levels, level: level}
+}
+
+func (h *componentHandler) WithGroup(name string) slog.Handler {
+	return &componentHandler{inner: h.inner.WithGroup(name), levels: h.levels, level: h.level}
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// redacted - значение, которое пишется вместо скрытого поля
+const redacted = "[REDACTED]"
+
+// sensitiveKeys - ключи атрибутов и полей сообщений, значения которых не попадают в лог:
+// токены, ключи и персональные данные пользователя
+var sensitiveKeys = map[string]struct{}{
+	"access_token":         {},
+	"token":                {},
+	"authorization":        {},
+	"api_key":              {},
+	"x-api-key":            {},
+	"password":             {},
+	"secret":               {},
+	"jti":                  {},
+	"token_id":             {},
+	"email":                {},
+	"phone":                {},
+	"name":                 {},
+	"product_name":         {},
+	"value":                {},
+	"diets":                {},
+	"allergens":            {},
+	"disliked_ingredients": {},
+	"preference_name":      {}, // устаревшее поле, сервер заполняет его первым типом питания из diets
+}
+
+// sensitive - нужно ли скрыть значение с этим ключом
+func sensitive(key string) bool {
+	_, ok := sensitiveKeys[strings.ToLower(key)]
+	return ok
+}
+
+// redactAttr - ReplaceAttr обработчика: скрывает значения чувствительных атрибутов
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	if sensitive(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+	return attr
+}
+
+// Payload - атрибут с телом сообщения, в котором скрыты чувствительные поля
+func Payload(key string, message any) slog.Attr {
+	m, ok := message.(proto.Message)
+	if !ok || m == nil {
+		return slog.Any(key, message)
+	}
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	if err != nil {
+		return slog.String(key, "<unmarshalable "+string(proto.MessageName(m))+">")
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return slog.String(key, "<unmarshalable "+string(proto.MessageName(m))+">")
+	}
+	return slog.Any(key, redactValue(fields))
+}
+
+// redactValue - рекурсивно скрывает чувствительные поля в разобранном JSON
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if sensitive(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
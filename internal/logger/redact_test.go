@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	userpb "user-service/gen/user"
+)
+
+func TestRedactAttr(t *testing.T) {
+	tests := []struct {
+		name     string
+		attr     slog.Attr
+		redacted bool
+	}{
+		{name: "access token", attr: slog.String("access_token", "eyJhbGciOi"), redacted: true},
+		{name: "key in other case", attr: slog.String("Authorization", "Bearer eyJ"), redacted: true},
+		{name: "api key header", attr: slog.String("x-api-key", "secret"), redacted: true},
+		{name: "product name", attr: slog.String("product_name", "milk"), redacted: true},
+		{name: "non-string value", attr: slog.Any("allergens", []string{"nuts"}), redacted: true},
+		{name: "user id", attr: slog.String("user_id", "6f1c3e2a"), redacted: false},
+		{name: "method", attr: slog.String("method", "/user.UserService/GetUserProducts"), redacted: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactAttr(nil, tt.attr)
+			if got.Key != tt.attr.Key {
+				t.Errorf("key changed to %q", got.Key)
+			}
+			if isRedacted := got.Value.String() == redacted; isRedacted != tt.redacted {
+				t.Errorf("value %q, redacted = %v, want %v", got.Value, isRedacted, tt.redacted)
+			}
+		})
+	}
+}
+
+func TestPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		message any
+		// want - атрибут в JSON-записи лога
+		want string
+	}{
+		{
+			name:    "top-level fields",
+			message: &userpb.AddProductRequest{AccessToken: "eyJ", ProductName: "milk", Quantity: 2},
+			want:    `{"access_token":"[REDACTED]","product_name":"[REDACTED]","quantity":2}`,
+		},
+		{
+			name: "nested message",
+			message: &userpb.UpdatePreferenceRequest{AccessToken: "eyJ", Preferences: &userpb.Preferences{
+				Diets: []string{"vegan"}, Allergens: []string{"nuts"}, Targets: &userpb.NutritionTargets{Calories: 2000},
+			}},
+			want: `{"access_token":"[REDACTED]","preferences":{"diets":"[REDACTED]","allergens":"[REDACTED]","targets":{"calories":2000}}}`,
+		},
+		{
+			name:    "deprecated preference name in request",
+			message: &userpb.UpdatePreferenceRequest{AccessToken: "eyJ", PreferenceName: "vegan"},
+			want:    `{"access_token":"[REDACTED]","preference_name":"[REDACTED]"}`,
+		},
+		{
+			name: "deprecated preference name in response",
+			message: &userpb.GetPreferenceResponse{PreferenceName: "vegan", Preferences: &userpb.Preferences{
+				Diets: []string{"vegan"},
+			}},
+			want: `{"preference_name":"[REDACTED]","preferences":{"diets":"[REDACTED]"}}`,
+		},
+		{
+			name:    "repeated messages",
+			message: &userpb.GetProductsResponse{Products: []*userpb.Product{{Id: "p1", Name: "milk"}, {Id: "p2", Name: "eggs"}}},
+			want:    `{"products":[{"id":"p1","name":"[REDACTED]"},{"id":"p2","name":"[REDACTED]"}]}`,
+		},
+		{name: "not a proto message", message: "plain", want: `"plain"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: redactAttr}))
+			log.Info("call", Payload("payload", tt.message))
+
+			var record map[string]json.RawMessage
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("failed to decode log record: %v", err)
+			}
+			var got, want any
+			if err := json.Unmarshal(record["payload"], &got); err != nil {
+				t.Fatalf("failed to decode payload: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("failed to decode expected payload: %v", err)
+			}
+			if gotJSON, wantJSON := mustJSON(t, got), mustJSON(t, want); gotJSON != wantJSON {
+				t.Errorf("got payload %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+// mustJSON - JSON с отсортированными ключами для сравнения значений
+func mustJSON(t *testing.T, value any) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to encode json: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,145 @@
+// Package metrics собирает метрики Prometheus сервиса и отдает их по /metrics.
+package metrics
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace - префикс имен всех метрик сервиса
+const namespace = "user_service"
+
+// registry - реестр метрик сервиса; глобальный реестр Prometheus не используется,
+// чтобы в /metrics не попадали метрики сторонних библиотек
+var registry = prometheus.NewRegistry()
+
+var (
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "server_handling_seconds",
+		Help:      "Duration of gRPC calls by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of repository queries by query name.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"query"})
+
+	productsAdded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "products_added_total",
+		Help:      "Products added to users' lists.",
+	})
+
+	productsRemoved = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "products_removed_total",
+		Help:      "Products removed from users' lists.",
+	})
+
+	tokenValidationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "token_validation_failures_total",
+		Help:      "Rejected access tokens by reason.",
+	}, []string{"reason"})
+
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Cache lookups by cached data and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	eventsPublished = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "events_published_total",
+		Help:      "Domain events published from the outbox to the broker.",
+	})
+
+	eventPublishFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "publish_failures_total",
+		Help:      "Failed attempts to publish outbox events.",
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		rpcDuration,
+		queryDuration,
+		productsAdded,
+		productsRemoved,
+		tokenValidationFailures,
+		cacheRequests,
+		eventsPublished,
+		eventPublishFailures,
+	)
+}
+
+// Handler - HTTP-обработчик, отдающий метрики в формате Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// ObserveRPC - учитывает длительность gRPC-вызова
+func ObserveRPC(method string, code string, duration time.Duration) {
+	rpcDuration.WithLabelValues(method, code).Observe(duration.Seconds())
+}
+
+// ObserveQuery - засекает длительность запроса к базе, возвращаемую функцию нужно вызвать по его завершении:
+//
+//	defer metrics.ObserveQuery("get_products")()
+func ObserveQuery(query string) func() {
+	start := time.Now()
+	return func() {
+		queryDuration.WithLabelValues(query).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ProductsAdded - учитывает добавленные продукты
+func ProductsAdded(n int) {
+	productsAdded.Add(float64(n))
+}
+
+// ProductsRemoved - учитывает удаленные продукты
+func ProductsRemoved(n int) {
+	productsRemoved.Add(float64(n))
+}
+
+// TokenValidationFailed - учитывает отклоненный токен; reason - причина ошибки, например TOKEN_EXPIRED
+func TokenValidationFailed(reason string) {
+	tokenValidationFailures.WithLabelValues(strings.ToLower(reason)).Inc()
+}
+
+// CacheLookup - учитывает обращение к кэшу cache (например, products) с попаданием или промахом
+func CacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// EventsPublished - учитывает события, опубликованные из outbox
+func EventsPublished(n int) {
+	eventsPublished.Add(float64(n))
+}
+
+// EventPublishFailed - учитывает неудачную попытку публикации событий
+func EventPublishFailed() {
+	eventPublishFailures.Inc()
+}
@@ -0,0 +1,78 @@
+// Package outbox переносит доменные события из outbox в брокер сообщений.
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"user-service/config"
+	"user-service/internal/adapter/broker"
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
+	"user-service/internal/repository"
+)
+
+// defaultPollInterval - период опроса outbox, если outbox.poll_interval не задан
+const defaultPollInterval = time.Second
+
+// Relay - публикует события из outbox в брокер. События публикуются по порядку, а после ошибки
+// публикация повторяется с первого неопубликованного события: доставка "хотя бы один раз",
+// и события одного пользователя не обгоняют друг друга.
+type Relay struct {
+	repo      repository.OutboxRepository
+	publisher broker.Publisher
+	interval  time.Duration
+	batchSize int
+	log       *slog.Logger
+}
+
+// NewRelay - создает relay с периодом опроса и размером пачки из cfg
+func NewRelay(repo repository.OutboxRepository, publisher broker.Publisher, cfg config.OutboxConfig, log *slog.Logger) *Relay {
+	interval := cfg.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	return &Relay{
+		repo:      repo,
+		publisher: publisher,
+		interval:  interval,
+		batchSize: max(cfg.BatchSize, 1),
+		log:       log,
+	}
+}
+
+// Run - публикует события до отмены ctx
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		r.drain(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain - публикует события пачками, пока outbox не опустеет или публикация не завершится ошибкой
+func (r *Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		published, err := r.repo.PublishEvents(ctx, r.batchSize, func(ctx context.Context, events []entity.Event) (int, error) {
+			return r.publisher.Publish(ctx, events)
+		})
+		metrics.EventsPublished(published)
+		if err != nil {
+			if ctx.Err() == nil {
+				metrics.EventPublishFailed()
+				r.log.WarnContext(ctx, "Failed to publish outbox events, retrying", "retry_in", r.interval, "error", err)
+			}
+			return
+		}
+		if published < r.batchSize {
+			return
+		}
+		r.log.DebugContext(ctx, "Outbox events published", "count", published)
+	}
+}
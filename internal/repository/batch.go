@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
+)
+
+// BatchResult - результат обработки одного элемента пакета
+type BatchResult struct {
+	// Product - сохраненный продукт, заполняется при успешном добавлении
+	Product entity.Product
+	// Err - ошибка элемента, nil при успехе.
+	// Если пакет атомарный и откатился, успешные элементы получают ErrBatchAborted.
+	Err error
+}
+
+// AddProducts - добавляет продукты в одной транзакции.
+// Каждый элемент выполняется в своей точке сохранения, поэтому ошибка одного
+// элемента не прерывает транзакцию. При atomic = true любая ошибка откатывает весь пакет.
+func (r *repository) AddProducts(ctx context.Context, userId string, products []entity.Product, atomic bool) ([]BatchResult, error) {
+	defer metrics.ObserveQuery("add_products")()
+	return r.runBatch(ctx, len(products), atomic, func(ctx context.Context, tx pgx.Tx, i int) (entity.Product, error) {
+		return insertProduct(ctx, tx, userId, products[i])
+	})
+}
+
+// RemoveProducts - удаляет продукты по идентификаторам в одной транзакции,
+// семантика такая же, как у AddProducts
+func (r *repository) RemoveProducts(ctx context.Context, userId string, productIds []string, atomic bool) ([]BatchResult, error) {
+	defer metrics.ObserveQuery("remove_products")()
+	return r.runBatch(ctx, len(productIds), atomic, func(ctx context.Context, tx pgx.Tx, i int) (entity.Product, error) {
+		removed, err := deleteProduct(ctx, tx, userId, productIds[i])
+		if err != nil {
+			return entity.Product{}, err
+		}
+		if removed == 0 {
+			return entity.Product{}, ErrProductNotFound
+		}
+		return entity.Product{ID: productIds[i]}, nil
+	})
+}
+
+// runBatch - выполняет n элементов пакета в одной транзакции, каждый в своей точке сохранения.
+// При временной ошибке хранилища пакет повторяется целиком.
+func (r *repository) runBatch(
+	ctx context.Context,
+	n int,
+	atomic bool,
+	apply func(ctx context.Context, tx pgx.Tx, i int) (entity.Product, error),
+) ([]BatchResult, error) {
+	return retryResult(ctx, r.retries, func() ([]BatchResult, error) {
+		tx, err := r.db.Begin(ctx)
+		if err != nil {
+			return nil, pgError(err, ErrTransactionFailed)
+		}
+		defer tx.Rollback(ctx)
+
+		results := make([]BatchResult, n)
+		failed := false
+		for i := range n {
+			savepoint, err := tx.Begin(ctx)
+			if err != nil {
+				return nil, pgError(err, ErrTransactionFailed)
+			}
+			product, err := apply(ctx, savepoint, i)
+			if err != nil {
+				if rbErr := savepoint.Rollback(ctx); rbErr != nil {
+					return nil, pgError(rbErr, ErrTransactionFailed)
+				}
+				results[i].Err = err
+				failed = true
+				continue
+			}
+			if err := savepoint.Commit(ctx); err != nil {
+				return nil, pgError(err, ErrTransactionFailed)
+			}
+			results[i].Product = product
+		}
+
+		if atomic && failed {
+			for i := range results {
+				if results[i].Err == nil {
+					results[i] = BatchResult{Err: ErrBatchAborted}
+				}
+			}
+			r.log.DebugContext(ctx, "Batch rolled back")
+			return results, nil
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return nil, pgError(err, ErrTransactionFailed)
+		}
+		r.log.DebugContext(ctx, "Batch committed successfully")
+		return results, nil
+	})
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"user-service/internal/adapter/cache"
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
+)
+
+// changeRetryDelay - пауза перед повторной подпиской на изменения после разрыва соединения
+var changeRetryDelay = 5 * time.Second
+
+var _ Repository = (*cachedRepository)(nil)
+
+// CacheTTL - сколько живут значения кэша, если их раньше не сбросило изменение
+type CacheTTL struct {
+	Products    time.Duration
+	Preferences time.Duration
+}
+
+// cachedRepository - декоратор Repository, который читает продукты и предпочтения через кэш.
+// Запись через декоратор сбрасывает кэш пользователя, изменения других реплик
+// приходят через WatchChanges (см. Run).
+type cachedRepository struct {
+	repo  Repository
+	cache cache.Cache
+	ttl   CacheTTL
+	log   *slog.Logger
+
+	// mu и epoch защищают от записи в кэш устаревшего значения: прочитанное из хранилища
+	// значение кладется в кэш, только если за время чтения кэш не сбрасывался
+	mu    sync.RWMutex
+	epoch uint64
+	// pending - ключи, измененные внутри WithTx, они сбрасываются после транзакции; nil вне ее
+	pending *[]string
+}
+
+// NewCached - оборачивает repo кэшем
+func NewCached(repo Repository, cache cache.Cache, ttl CacheTTL, log *slog.Logger) *cachedRepository {
+	return &cachedRepository{
+		repo:  repo,
+		cache: cache,
+		ttl:   ttl,
+		log:   log,
+	}
+}
+
+// Run - сбрасывает кэш по изменениям из WatchChanges до отмены ctx.
+// После каждой подписки кэш очищается целиком, чтобы в нем не осталось значений,
+// измененных пока подписки не было.
+func (r *cachedRepository) Run(ctx context.Context) {
+	for {
+		err := r.repo.WatchChanges(ctx, func() error { return r.clear(ctx) }, func(change Change) {
+			r.invalidate(ctx, changeKey(change))
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		r.log.WarnContext(ctx, "User data change subscription lost, retrying", "retry_in", changeRetryDelay, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(changeRetryDelay):
+		}
+	}
+}
+
+func (r *cachedRepository) GetProducts(ctx context.Context, userId string, query ProductQuery) (ProductPage, error) {
+	if r.pending != nil {
+		return r.repo.GetProducts(ctx, userId, query)
+	}
+	// Страницы пользователя различаются всеми параметрами запроса
+	field, err := json.Marshal(query)
+	if err != nil {
+		return ProductPage{}, err
+	}
+	return readThrough(ctx, r, "products", productsCacheKey(userId), string(field), r.ttl.Products, func() (ProductPage, error) {
+		return r.repo.GetProducts(ctx, userId, query)
+	})
+}
+
+func (r *cachedRepository) GetPreference(ctx context.Context, userId string) (entity.Preferences, error) {
+	if r.pending != nil {
+		return r.repo.GetPreference(ctx, userId)
+	}
+	return readThrough(ctx, r, "preferences", preferencesCacheKey(userId), "", r.ttl.Preferences, func() (entity.Preferences, error) {
+		return r.repo.GetPreference(ctx, userId)
+	})
+}
+
+func (r *cachedRepository) UpdatePreference(ctx context.Context, userId string, preferences entity.Preferences) error {
+	defer r.invalidate(ctx, preferencesCacheKey(userId))
+	return r.repo.UpdatePreference(ctx, userId, preferences)
+}
+
+func (r *cachedRepository) RemovePreference(ctx context.Context, userId string) error {
+	defer r.invalidate(ctx, preferencesCacheKey(userId))
+	return r.repo.RemovePreference(ctx, userId)
+}
+
+func (r *cachedRepository) AddPreferenceEntry(ctx context.Context, userId string, entry entity.PreferenceEntry) error {
+	defer r.invalidate(ctx, preferencesCacheKey(userId))
+	return r.repo.AddPreferenceEntry(ctx, userId, entry)
+}
+
+func (r *cachedRepository) RemovePreferenceEntry(ctx context.Context, userId string, entry entity.PreferenceEntry) error {
+	defer r.invalidate(ctx, preferencesCacheKey(userId))
+	return r.repo.RemovePreferenceEntry(ctx, userId, entry)
+}
+
+func (r *cachedRepository) AddProduct(ctx context.Context, userId string, product entity.Product) (entity.Product, error) {
+	defer r.invalidate(ctx, productsCacheKey(userId))
+	return r.repo.AddProduct(ctx, userId, product)
+}
+
+func (r *cachedRepository) RemoveProduct(ctx context.Context, userId string, productId string) error {
+	defer r.invalidate(ctx, productsCacheKey(userId))
+	return r.repo.RemoveProduct(ctx, userId, productId)
+}
+
+func (r *cachedRepository) RemoveProductByName(ctx context.Context, userId string, productName string) (int64, error) {
+	defer r.invalidate(ctx, productsCacheKey(userId))
+	return r.repo.RemoveProductByName(ctx, userId, productName)
+}
+
+func (r *cachedRepository) AddProducts(ctx context.Context, userId string, products []entity.Product, atomic bool) ([]BatchResult, error) {
+	defer r.invalidate(ctx, productsCacheKey(userId))
+	return r.repo.AddProducts(ctx, userId, products, atomic)
+}
+
+func (r *cachedRepository) RemoveProducts(ctx context.Context, userId string, productIds []string, atomic bool) ([]BatchResult, error) {
+	defer r.invalidate(ctx, productsCacheKey(userId))
+	return r.repo.RemoveProducts(ctx, userId, productIds, atomic)
+}
+
+// WithTx - внутри транзакции чтения идут мимо кэша, а измененные ключи сбрасываются
+// после ее завершения, чтобы другие запросы не закэшировали незафиксированное состояние
+func (r *cachedRepository) WithTx(ctx context.Context, fn func(Repository) error, opts ...TxOption) error {
+	var pending []string
+	err := r.repo.WithTx(ctx, func(tx Repository) error {
+		return fn(&cachedRepository{
+			repo:    tx,
+			cache:   r.cache,
+			ttl:     r.ttl,
+			log:     r.log,
+			pending: &pending,
+		})
+	}, opts...)
+	r.invalidate(ctx, pending...)
+	return err
+}
+
+func (r *cachedRepository) AddEvents(ctx context.Context, events ...entity.Event) error {
+	return r.repo.AddEvents(ctx, events...)
+}
+
+func (r *cachedRepository) WatchChanges(ctx context.Context, subscribed func() error, changed func(Change)) error {
+	return r.repo.WatchChanges(ctx, subscribed, changed)
+}
+
+// readThrough - читает значение из кэша, а при промахе - через load и кладет его в кэш.
+// Ошибки кэша не прерывают запрос: значение читается из хранилища.
+func readThrough[T any](ctx context.Context, r *cachedRepository, name string, key string, field string, ttl time.Duration, load func() (T, error)) (T, error) {
+	data, ok, err := r.cache.Get(ctx, key, field)
+	if err != nil {
+		r.log.WarnContext(ctx, "Cache read failed", "cache", name, "error", err)
+	}
+	if ok {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			metrics.CacheLookup(name, true)
+			return value, nil
+		}
+		r.log.WarnContext(ctx, "Skipping malformed cache value", "cache", name, "error", err)
+	}
+	metrics.CacheLookup(name, false)
+
+	r.mu.RLock()
+	epoch := r.epoch
+	r.mu.RUnlock()
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	if data, err = json.Marshal(value); err != nil {
+		return value, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.epoch != epoch {
+		return value, nil
+	}
+	if err := r.cache.Set(ctx, key, field, data, ttl); err != nil {
+		r.log.WarnContext(ctx, "Cache write failed", "cache", name, "error", err)
+	}
+	return value, nil
+}
+
+// invalidate - сбрасывает ключи кэша; внутри WithTx только запоминает их до конца транзакции.
+// Вызывается и после неудачной записи: по ошибке нельзя понять, применилась ли она.
+func (r *cachedRepository) invalidate(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	if r.pending != nil {
+		*r.pending = append(*r.pending, keys...)
+		return
+	}
+	r.mu.Lock()
+	r.epoch++
+	r.mu.Unlock()
+	// Запись уже выполнена, поэтому кэш сбрасывается, даже если клиент отменил запрос
+	if err := r.cache.Delete(context.WithoutCancel(ctx), keys...); err != nil {
+		r.log.WarnContext(ctx, "Cache invalidation failed", "error", err)
+	}
+}
+
+// clear - сбрасывает весь кэш
+func (r *cachedRepository) clear(ctx context.Context) error {
+	r.mu.Lock()
+	r.epoch++
+	r.mu.Unlock()
+	return r.cache.Clear(ctx)
+}
+
+// productsCacheKey - ключ кэша со страницами продуктов пользователя
+func productsCacheKey(userId string) string {
+	return "products:" + userId
+}
+
+// preferencesCacheKey - ключ кэша с набором предпочтений пользователя
+func preferencesCacheKey(userId string) string {
+	return "preferences:" + userId
+}
+
+// changeKey - ключ кэша, который сбрасывает изменение
+func changeKey(change Change) string {
+	if change.Kind == ChangeProducts {
+		return productsCacheKey(change.UserID)
+	}
+	return preferencesCacheKey(change.UserID)
+}
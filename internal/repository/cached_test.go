@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"user-service/internal/adapter/cache"
+	"user-service/internal/entity"
+)
+
+// loadHookRepository - репозиторий, который вызывает afterLoad между чтением предпочтений
+// из хранилища и возвратом значения, то есть до того, как декоратор положит его в кэш
+type loadHookRepository struct {
+	Repository
+	afterLoad func()
+}
+
+func (r *loadHookRepository) GetPreference(ctx context.Context, userId string) (entity.Preferences, error) {
+	preferences, err := r.Repository.GetPreference(ctx, userId)
+	if hook := r.afterLoad; hook != nil {
+		r.afterLoad = nil
+		hook()
+	}
+	return preferences, err
+}
+
+// watchRepository - репозиторий, подпиской на изменения которого управляет тест
+type watchRepository struct {
+	Repository
+	// subscribed - сигнал после каждой подписки
+	subscribed chan struct{}
+	// changes - изменения, которые получит подписчик; после обработки приходит сигнал в delivered
+	changes   chan Change
+	delivered chan struct{}
+	// drop - обрывает текущую подписку
+	drop chan struct{}
+}
+
+func (r *watchRepository) WatchChanges(ctx context.Context, subscribed func() error, changed func(Change)) error {
+	if err := subscribed(); err != nil {
+		return err
+	}
+	r.subscribed <- struct{}{}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case change := <-r.changes:
+			changed(change)
+			r.delivered <- struct{}{}
+		case <-r.drop:
+			return errors.New("connection lost")
+		}
+	}
+}
+
+// newTestCached - декоратор с LRU над repo; возвращает и хранилище кэша, чтобы проверять его содержимое
+func newTestCached(repo Repository) (*cachedRepository, cache.Cache) {
+	store := cache.NewLRU(100)
+	return NewCached(repo, store, CacheTTL{Products: time.Minute, Preferences: time.Minute}, testLog()), store
+}
+
+// cachedKey - есть ли в кэше значение ключа key (поле field)
+func cachedKey(t *testing.T, store cache.Cache, key string, field string) bool {
+	t.Helper()
+	_, ok, err := store.Get(context.Background(), key, field)
+	if err != nil {
+		t.Fatalf("cache Get(%s): %v", key, err)
+	}
+	return ok
+}
+
+// getDiets - типы питания пользователя, прочитанные через repo
+func getDiets(t *testing.T, ctx context.Context, repo Repository, userId string) []string {
+	t.Helper()
+	preferences, err := repo.GetPreference(ctx, userId)
+	if err != nil {
+		t.Fatalf("GetPreference: %v", err)
+	}
+	return preferences.Diets
+}
+
+func TestCachedRepositoryReadThrough(t *testing.T) {
+	ctx, userId := testUser()
+	memory := NewMemory(testLog())
+	cached, store := newTestCached(memory)
+	if err := memory.UpdatePreference(ctx, userId, entity.Preferences{Diets: []string{"vegan"}}); err != nil {
+		t.Fatalf("UpdatePreference: %v", err)
+	}
+
+	getDiets(t, ctx, cached, userId)
+	// Изменение в обход декоратора не сбрасывает кэш, значит второе чтение идет из кэша
+	if err := memory.UpdatePreference(ctx, userId, entity.Preferences{Diets: []string{"keto"}}); err != nil {
+		t.Fatalf("UpdatePreference: %v", err)
+	}
+	if got := getDiets(t, ctx, cached, userId); !slices.Equal(got, []string{"vegan"}) {
+		t.Errorf("second read = %v, want cached [vegan]", got)
+	}
+
+	if err := cached.AddPreferenceEntry(ctx, userId, entity.PreferenceEntry{Kind: entity.PreferenceDiet, Value: "paleo"}); err != nil {
+		t.Fatalf("AddPreferenceEntry: %v", err)
+	}
+	if cachedKey(t, store, preferencesCacheKey(userId), "") {
+		t.Error("write through the decorator did not invalidate the cache")
+	}
+	if got := getDiets(t, ctx, cached, userId); !slices.Equal(got, []string{"keto", "paleo"}) {
+		t.Errorf("read after write = %v, want [keto paleo]", got)
+	}
+}
+
+func TestCachedRepositoryInvalidationDuringLoad(t *testing.T) {
+	ctx, userId := testUser()
+	hooked := &loadHookRepository{Repository: NewMemory(testLog())}
+	cached, store := newTestCached(hooked)
+	if err := hooked.UpdatePreference(ctx, userId, entity.Preferences{Diets: []string{"vegan"}}); err != nil {
+		t.Fatalf("UpdatePreference: %v", err)
+	}
+
+	// Запись завершается после того, как чтение получило старое значение, но до записи его в кэш
+	hooked.afterLoad = func() {
+		if err := cached.UpdatePreference(ctx, userId, entity.Preferences{Diets: []string{"keto"}}); err != nil {
+			t.Errorf("UpdatePreference: %v", err)
+		}
+	}
+	if got := getDiets(t, ctx, cached, userId); !slices.Equal(got, []string{"vegan"}) {
+		t.Errorf("read = %v, want the value loaded before the write", got)
+	}
+	if cachedKey(t, store, preferencesCacheKey(userId), "") {
+		t.Error("value loaded before the invalidation was cached")
+	}
+	if got := getDiets(t, ctx, cached, userId); !slices.Equal(got, []string{"keto"}) {
+		t.Errorf("next read = %v, want [keto]", got)
+	}
+}
+
+func TestCachedRepositoryWithTx(t *testing.T) {
+	ctx, userId := testUser()
+	memory := NewMemory(testLog())
+	cached, store := newTestCached(memory)
+	if err := memory.UpdatePreference(ctx, userId, entity.Preferences{Diets: []string{"vegan"}}); err != nil {
+		t.Fatalf("UpdatePreference: %v", err)
+	}
+	key := preferencesCacheKey(userId)
+
+	tests := []struct {
+		name string
+		// fail - откатить транзакцию
+		fail bool
+	}{
+		{name: "commit"},
+		{name: "rollback", fail: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getDiets(t, ctx, cached, userId)
+			if !cachedKey(t, store, key, "") {
+				t.Fatal("value is not cached before the transaction")
+			}
+
+			errRollback := errors.New("rollback")
+			err := cached.WithTx(ctx, func(tx Repository) error {
+				if err := tx.UpdatePreference(ctx, userId, entity.Preferences{Diets: []string{tt.name}}); err != nil {
+					return err
+				}
+				if !cachedKey(t, store, key, "") {
+					t.Error("cache invalidated before the transaction ended")
+				}
+				// Чтение внутри транзакции видит ее изменения, а не значение из кэша
+				if got := getDiets(t, ctx, tx, userId); !slices.Equal(got, []string{tt.name}) {
+					t.Errorf("read in transaction = %v, want [%s]", got, tt.name)
+				}
+				if tt.fail {
+					return errRollback
+				}
+				return nil
+			})
+			if tt.fail != errors.Is(err, errRollback) {
+				t.Fatalf("WithTx: %v", err)
+			}
+			if cachedKey(t, store, key, "") {
+				t.Error("cache not invalidated after the transaction")
+			}
+		})
+	}
+
+	t.Run("reads bypass cache", func(t *testing.T) {
+		if err := store.Clear(ctx); err != nil {
+			t.Fatalf("Clear: %v", err)
+		}
+		query := ProductQuery{Limit: 10}
+		err := cached.WithTx(ctx, func(tx Repository) error {
+			getDiets(t, ctx, tx, userId)
+			_, err := tx.GetProducts(ctx, userId, query)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("WithTx: %v", err)
+		}
+		if cachedKey(t, store, key, "") || cachedKey(t, store, productsCacheKey(userId), productsField(t, query)) {
+			t.Error("values read in a transaction were cached")
+		}
+	})
+}
+
+// productsField - поле ключа productsCacheKey со страницей продуктов запроса query
+func productsField(t *testing.T, query ProductQuery) string {
+	t.Helper()
+	field, err := json.Marshal(query)
+	if err != nil {
+		t.Fatalf("failed to encode query: %v", err)
+	}
+	return string(field)
+}
+
+func TestCachedRepositoryRun(t *testing.T) {
+	delay := changeRetryDelay
+	t.Cleanup(func() { changeRetryDelay = delay })
+	changeRetryDelay = time.Millisecond
+
+	ctx, userId := testUser()
+	_, otherId := testUser()
+	watch := &watchRepository{
+		Repository: NewMemory(testLog()),
+		subscribed: make(chan struct{}),
+		changes:    make(chan Change),
+		delivered:  make(chan struct{}),
+		drop:       make(chan struct{}),
+	}
+	cached, store := newTestCached(watch)
+	for _, id := range []string{userId, otherId} {
+		if err := watch.UpdatePreference(ctx, id, entity.Preferences{Diets: []string{"vegan"}}); err != nil {
+			t.Fatalf("UpdatePreference: %v", err)
+		}
+	}
+
+	query := ProductQuery{Limit: 10}
+	// fill - кладет в кэш предпочтения обоих пользователей и страницу продуктов userId
+	fill := func() {
+		t.Helper()
+		getDiets(t, ctx, cached, userId)
+		getDiets(t, ctx, cached, otherId)
+		if _, err := cached.GetProducts(ctx, userId, query); err != nil {
+			t.Fatalf("GetProducts: %v", err)
+		}
+	}
+	// cachedKeys - какие из значений, которые кладет fill, есть в кэше
+	cachedKeys := func() []bool {
+		t.Helper()
+		return []bool{
+			cachedKey(t, store, preferencesCacheKey(userId), ""),
+			cachedKey(t, store, preferencesCacheKey(otherId), ""),
+			cachedKey(t, store, productsCacheKey(userId), productsField(t, query)),
+		}
+	}
+	wait := func(signal chan struct{}, what string) {
+		t.Helper()
+		select {
+		case <-signal:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+
+	// Значения, закэшированные до подписки, могли устареть, пока изменения не приходили
+	fill()
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cached.Run(runCtx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	wait(watch.subscribed, "subscription")
+	if got := cachedKeys(); !slices.Equal(got, []bool{false, false, false}) {
+		t.Errorf("cached after subscription = %v, want nothing", got)
+	}
+
+	// Изменение сбрасывает только ключ своего пользователя и вида данных
+	fill()
+	watch.changes <- Change{UserID: userId, Kind: ChangePreferences}
+	wait(watch.delivered, "change")
+	if got := cachedKeys(); !slices.Equal(got, []bool{false, true, true}) {
+		t.Errorf("cached after change = %v, want [false true true]", got)
+	}
+
+	// После разрыва подписки кэш очищается целиком при повторной подписке
+	watch.drop <- struct{}{}
+	wait(watch.subscribed, "resubscription")
+	if got := cachedKeys(); !slices.Equal(got, []bool{false, false, false}) {
+		t.Errorf("cached after resubscription = %v, want nothing", got)
+	}
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
+)
+
+var (
+	ErrCatalogProductNotFound = errors.New("catalog product not found")
+	ErrCatalogAliasConflict   = errors.New("alias belongs to another catalog product")
+)
+
+var _ CatalogRepository = (*catalogRepository)(nil)
+
+type CatalogRepository interface {
+	// ResolveProduct - найти запись каталога по названию или синониму
+	ResolveProduct(ctx context.Context, name string) (entity.CatalogProduct, error)
+	// UpsertProduct - создать или обновить запись каталога по каноническому названию,
+	// локализованные названия и синонимы заменяются целиком
+	UpsertProduct(ctx context.Context, product entity.CatalogProduct) (entity.CatalogProduct, error)
+	// DeleteProduct - удалить запись каталога, продукты пользователей становятся пользовательскими
+	DeleteProduct(ctx context.Context, productId string) error
+	// ListProducts - получить записи каталога по возрастанию канонического названия
+	ListProducts(ctx context.Context, namePrefix string, after string, limit int) ([]entity.CatalogProduct, error)
+}
+
+// catalogSelect - выборка записи каталога вместе с названиями и синонимами
+const catalogSelect = `SELECT p.id, p.canonical_name, p.category, p.created_at, p.updated_at,
+	COALESCE((SELECT jsonb_object_agg(n.locale, n.display_name) FROM product_names n WHERE n.product_id = p.id), '{}'::jsonb),
+	COALESCE((SELECT array_agg(a.alias ORDER BY a.alias) FROM product_aliases a WHERE a.product_id = p.id), '{}')
+	FROM products p`
+
+// scanCatalogProduct - сканирует строку catalogSelect в entity.CatalogProduct
+func scanCatalogProduct(row rowScanner) (entity.CatalogProduct, error) {
+	var product entity.CatalogProduct
+	err := row.Scan(
+		&product.ID,
+		&product.CanonicalName,
+		&product.Category,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+		&product.DisplayNames,
+		&product.Aliases,
+	)
+	return product, err
+}
+
+type catalogRepository struct {
+	db      *pgxpool.Pool
+	log     *slog.Logger
+	retries retryPolicy
+}
+
+func NewCatalog(db *pgxpool.Pool, log *slog.Logger) *catalogRepository {
+	return &catalogRepository{
+		db:      db,
+		log:     log,
+		retries: newRetryPolicy(log),
+	}
+}
+
+func (r *catalogRepository) ResolveProduct(ctx context.Context, name string) (entity.CatalogProduct, error) {
+	defer metrics.ObserveQuery("catalog_resolve_product")()
+	query := catalogSelect + ` JOIN product_aliases pa ON pa.product_id = p.id WHERE pa.alias = $1`
+	return retryResult(ctx, r.retries, func() (entity.CatalogProduct, error) {
+		product, err := scanCatalogProduct(r.db.QueryRow(ctx, query, entity.NormalizeProductName(name)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.CatalogProduct{}, ErrCatalogProductNotFound
+		}
+		if err != nil {
+			return entity.CatalogProduct{}, pgError(err, ErrQueryFailed)
+		}
+		return product, nil
+	})
+}
+
+func (r *catalogRepository) UpsertProduct(ctx context.Context, product entity.CatalogProduct) (entity.CatalogProduct, error) {
+	defer metrics.ObserveQuery("catalog_upsert_product")()
+	saved, err := retryResult(ctx, r.retries, func() (entity.CatalogProduct, error) {
+		return r.upsertProduct(ctx, product)
+	})
+	if err != nil {
+		return entity.CatalogProduct{}, err
+	}
+	r.log.DebugContext(ctx, "Catalog product saved successfully")
+	return saved, nil
+}
+
+// upsertProduct - одна попытка транзакции UpsertProduct
+func (r *catalogRepository) upsertProduct(ctx context.Context, product entity.CatalogProduct) (entity.CatalogProduct, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return entity.CatalogProduct{}, pgError(err, ErrTransactionFailed)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO products (canonical_name, category) VALUES ($1, $2)
+		ON CONFLICT (canonical_name) DO UPDATE
+		SET category = EXCLUDED.category, updated_at = CURRENT_TIMESTAMP
+		RETURNING id`
+	var productId string
+	if err := tx.QueryRow(ctx, query, product.CanonicalName, product.Category).Scan(&productId); err != nil {
+		return entity.CatalogProduct{}, pgError(err, ErrQueryFailed)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM product_names WHERE product_id = $1`, productId); err != nil {
+		return entity.CatalogProduct{}, pgError(err, ErrQueryFailed)
+	}
+	for locale, displayName := range product.DisplayNames {
+		query = `INSERT INTO product_names (product_id, locale, display_name) VALUES ($1, $2, $3)`
+		if _, err := tx.Exec(ctx, query, productId, locale, displayName); err != nil {
+			return entity.CatalogProduct{}, pgError(err, ErrQueryFailed)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM product_aliases WHERE product_id = $1`, productId); err != nil {
+		return entity.CatalogProduct{}, pgError(err, ErrQueryFailed)
+	}
+	for _, alias := range catalogAliases(product) {
+		query = `INSERT INTO product_aliases (alias, product_id) VALUES ($1, $2)`
+		if _, err := tx.Exec(ctx, query, alias, productId); err != nil {
+			repoErr := pgError(err, ErrQueryFailed)
+			if repoErr.Class == ErrUniqueViolation {
+				repoErr.Kind = ErrCatalogAliasConflict
+			}
+			return entity.CatalogProduct{}, repoErr
+		}
+	}
+
+	saved, err := scanCatalogProduct(tx.QueryRow(ctx, catalogSelect+` WHERE p.id = $1`, productId))
+	if err != nil {
+		return entity.CatalogProduct{}, pgError(err, ErrQueryFailed)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return entity.CatalogProduct{}, pgError(err, ErrTransactionFailed)
+	}
+	return saved, nil
+}
+
+func (r *catalogRepository) DeleteProduct(ctx context.Context, productId string) error {
+	defer metrics.ObserveQuery("catalog_delete_product")()
+	removed, err := retryResult(ctx, r.retries, func() (int64, error) {
+		tag, err := r.db.Exec(ctx, `DELETE FROM products WHERE id = $1`, productId)
+		if err != nil {
+			return 0, pgError(err, ErrQueryFailed)
+		}
+		return tag.RowsAffected(), nil
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrCatalogProductNotFound
+	}
+	r.log.DebugContext(ctx, "Catalog product removed successfully")
+	return nil
+}
+
+func (r *catalogRepository) ListProducts(ctx context.Context, namePrefix string, after string, limit int) ([]entity.CatalogProduct, error) {
+	defer metrics.ObserveQuery("catalog_list_products")()
+	query := catalogSelect + ` WHERE starts_with(lower(p.canonical_name), lower($1)) AND p.canonical_name > $2
+		ORDER BY p.canonical_name LIMIT $3`
+	return retryResult(ctx, r.retries, func() ([]entity.CatalogProduct, error) {
+		rows, err := r.db.Query(ctx, query, namePrefix, after, limit)
+		if err != nil {
+			return nil, pgError(err, ErrQueryFailed)
+		}
+		defer rows.Close()
+		var products []entity.CatalogProduct
+		for rows.Next() {
+			product, err := scanCatalogProduct(rows)
+			if err != nil {
+				return nil, pgError(err, ErrNoRows)
+			}
+			products = append(products, product)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, pgError(err, ErrQueryFailed)
+		}
+		return products, nil
+	})
+}
+
+// catalogAliases - полный набор нормализованных синонимов записи каталога:
+// каноническое название, локализованные названия и явно заданные синонимы
+func catalogAliases(product entity.CatalogProduct) []string {
+	seen := make(map[string]struct{})
+	var aliases []string
+	add := func(name string) {
+		alias := entity.NormalizeProductName(name)
+		if alias == "" {
+			return
+		}
+		if _, ok := seen[alias]; ok {
+			return
+		}
+		seen[alias] = struct{}{}
+		aliases = append(aliases, alias)
+	}
+	add(product.CanonicalName)
+	for _, displayName := range product.DisplayNames {
+		add(displayName)
+	}
+	for _, alias := range product.Aliases {
+		add(alias)
+	}
+	return aliases
+}
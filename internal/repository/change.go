@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// changeChannel - канал LISTEN/NOTIFY, в который триггеры пишут изменения данных пользователей
+const changeChannel = "user_data_changes"
+
+// ChangeKind - какие данные пользователя изменились
+type ChangeKind string
+
+const (
+	// ChangeProducts - изменились продукты пользователя
+	ChangeProducts ChangeKind = "products"
+	// ChangePreferences - изменились предпочтения пользователя
+	ChangePreferences ChangeKind = "preferences"
+)
+
+// Change - изменение продуктов или предпочтений пользователя
+type Change struct {
+	UserID string     `json:"user_id"`
+	Kind   ChangeKind `json:"kind"`
+}
+
+func (r *repository) WatchChanges(ctx context.Context, subscribed func() error, changed func(Change)) error {
+	// LISTEN держит соединение все время работы, поэтому оно не берется из пула
+	conn, err := pgx.ConnectConfig(ctx, r.pool.Config().ConnConfig.Copy())
+	if err != nil {
+		return fmt.Errorf("failed to connect for %s notifications: %w", changeChannel, err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+changeChannel); err != nil {
+		return fmt.Errorf("failed to listen %s: %w", changeChannel, err)
+	}
+	if err := subscribed(); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		change, err := decodeChangeNotification(notification.Payload)
+		if err != nil {
+			r.log.WarnContext(ctx, "Skipping malformed notification", "channel", changeChannel, "error", err)
+			continue
+		}
+		changed(change)
+	}
+}
+
+// decodeChangeNotification - разбирает payload уведомления, который формирует триггер notify_user_data_change
+func decodeChangeNotification(payload string) (Change, error) {
+	var change Change
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		return Change{}, err
+	}
+	switch {
+	case change.UserID == "":
+		return Change{}, fmt.Errorf("empty user in change payload %q", payload)
+	case change.Kind != ChangeProducts && change.Kind != ChangePreferences:
+		return Change{}, fmt.Errorf("unknown change kind in payload %q", payload)
+	}
+	return change, nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Классы ошибок хранилища. Ошибка репозитория (*Error) содержит доменную ошибку,
+// класс и исходную ошибку драйвера, errors.Is находит каждую из них.
+var (
+	// ErrUniqueViolation - нарушено ограничение уникальности
+	ErrUniqueViolation = errors.New("unique violation")
+	// ErrForeignKeyViolation - запись ссылается на несуществующую запись
+	ErrForeignKeyViolation = errors.New("foreign key violation")
+	// ErrSerializationFailure - транзакция откатилась из-за конфликта с параллельной транзакцией
+	ErrSerializationFailure = errors.New("serialization failure")
+	// ErrConnectionFailed - соединение с хранилищем недоступно или оборвалось
+	ErrConnectionFailed = errors.New("connection failed")
+	// ErrInvalidData - значение не помещается в колонку или нарушает ограничение CHECK;
+	// повтор того же запроса снова завершится ошибкой
+	ErrInvalidData = errors.New("invalid data")
+)
+
+// Коды ошибок Postgres (SQLSTATE)
+const (
+	uniqueViolation      = "23505"
+	foreignKeyViolation  = "23503"
+	checkViolation       = "23514"
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+	// dataException - класс ошибок данных 22xxx: переполнение, слишком длинная строка и т.д.
+	dataException = "22"
+	// connectionException - класс ошибок соединения 08xxx
+	connectionException = "08"
+	adminShutdown       = "57P01"
+	crashShutdown       = "57P02"
+	cannotConnectNow    = "57P03"
+)
+
+// Повтор временных ошибок хранилища
+const (
+	// retryAttempts - сколько всего раз выполняется операция вне транзакции WithTx
+	retryAttempts = 3
+	// retryBaseDelay - задержка перед первым повтором, затем она удваивается
+	retryBaseDelay = 50 * time.Millisecond
+	// retryMaxDelay - максимальная задержка между повторами
+	retryMaxDelay = 500 * time.Millisecond
+)
+
+// Error - ошибка репозитория с исходной ошибкой драйвера
+type Error struct {
+	// Kind - доменная ошибка (ErrProductAlreadyExists, ErrQueryFailed и т.д.),
+	// по которой вызывающие слои выбирают ответ клиенту
+	Kind error
+	// Class - класс ошибки хранилища, nil если ошибка не классифицирована
+	Class error
+	// Err - исходная ошибка драйвера
+	Err error
+	// transient - операцию можно безопасно повторить
+	transient bool
+}
+
+func (e *Error) Error() string {
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Class == nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind, e.Class, e.Err}
+}
+
+// Transient - временная ли ошибка: транзакция откатилась сервером или запрос до него не дошел,
+// поэтому операцию можно повторить
+func (e *Error) Transient() bool {
+	return e.transient
+}
+
+// pgError - оборачивает ошибку pgx в Error с доменной ошибкой kind и классом по коду Postgres
+func pgError(err error, kind error) *Error {
+	repoErr := &Error{Kind: kind, Err: err}
+	var pgErr *pgconn.PgError
+	var connectErr *pgconn.ConnectError
+	switch {
+	case errors.As(err, &pgErr):
+		switch code := pgErr.Code; {
+		case code == uniqueViolation:
+			repoErr.Class = ErrUniqueViolation
+		case code == foreignKeyViolation:
+			repoErr.Class = ErrForeignKeyViolation
+		case code == checkViolation, strings.HasPrefix(code, dataException):
+			repoErr.Class = ErrInvalidData
+		case code == serializationFailure, code == deadlockDetected:
+			repoErr.Class, repoErr.transient = ErrSerializationFailure, true
+		case strings.HasPrefix(code, connectionException), code == adminShutdown, code == crashShutdown, code == cannotConnectNow:
+			// Сервер сам сообщил об ошибке соединения, значит запрос не выполнен
+			repoErr.Class, repoErr.transient = ErrConnectionFailed, true
+		}
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+	case errors.As(err, &connectErr):
+		repoErr.Class, repoErr.transient = ErrConnectionFailed, true
+	case pgconn.SafeToRetry(err):
+		repoErr.Class, repoErr.transient = ErrConnectionFailed, true
+	case pgconn.Timeout(err):
+		repoErr.Class = ErrConnectionFailed
+	}
+	return repoErr
+}
+
+// sqliteError - оборачивает ошибку SQLite в Error с доменной ошибкой kind и классом по коду SQLite
+func sqliteError(err error, kind error) *Error {
+	repoErr := &Error{Kind: kind, Err: err}
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return repoErr
+	}
+	switch code := sqliteErr.Code(); {
+	case code == sqlite3.SQLITE_CONSTRAINT_UNIQUE, code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		repoErr.Class = ErrUniqueViolation
+	case code == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		repoErr.Class = ErrForeignKeyViolation
+	case code == sqlite3.SQLITE_CONSTRAINT_CHECK:
+		repoErr.Class = ErrInvalidData
+	case code&0xff == sqlite3.SQLITE_BUSY, code&0xff == sqlite3.SQLITE_LOCKED:
+		// Файл заблокирован другой транзакцией дольше busy_timeout, запрос не выполнен
+		repoErr.Class, repoErr.transient = ErrSerializationFailure, true
+	}
+	return repoErr
+}
+
+// retryPolicy - повтор временных ошибок хранилища. Операции внутри транзакции WithTx
+// не повторяются по отдельности: после ошибки транзакция прервана, повторяется она целиком.
+type retryPolicy struct {
+	log *slog.Logger
+	// attempts - сколько всего раз выполняется операция
+	attempts int
+}
+
+// newRetryPolicy - повтор не больше retryAttempts раз
+func newRetryPolicy(log *slog.Logger) retryPolicy {
+	return retryPolicy{log: log, attempts: retryAttempts}
+}
+
+// retry - выполняет op и повторяет ее, пока она возвращает временную ошибку хранилища:
+// не больше policy.attempts раз, с экспоненциальной задержкой со случайным разбросом
+// и не дольше, чем живет ctx. Транзакция повторяется целиком.
+func retry(ctx context.Context, policy retryPolicy, op func() error) error {
+	_, err := retryResult(ctx, policy, func() (struct{}, error) {
+		return struct{}{}, op()
+	})
+	return err
+}
+
+// retryResult - как retry, но для операций, возвращающих значение
+func retryResult[T any](ctx context.Context, policy retryPolicy, op func() (T, error)) (T, error) {
+	delay := retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		result, err := op()
+		var repoErr *Error
+		if err == nil || attempt >= policy.attempts || !errors.As(err, &repoErr) || !repoErr.Transient() {
+			return result, err
+		}
+		policy.log.WarnContext(ctx, "Retrying after transient storage error", "attempt", attempt, "error", err)
+
+		timer := time.NewTimer(delay/2 + rand.N(delay/2))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+		delay = min(2*delay, retryMaxDelay)
+	}
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"user-service/config"
+	"user-service/internal/adapter/sqlite"
+)
+
+// safeToRetryError - ошибка, про которую pgx знает, что запрос не дошел до сервера
+type safeToRetryError struct{}
+
+func (safeToRetryError) Error() string     { return "connection closed before sending" }
+func (safeToRetryError) SafeToRetry() bool { return true }
+
+func TestPgErrorClassification(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantClass     error
+		wantTransient bool
+	}{
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, wantClass: ErrUniqueViolation},
+		{name: "foreign key violation", err: &pgconn.PgError{Code: "23503"}, wantClass: ErrForeignKeyViolation},
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, wantClass: ErrSerializationFailure, wantTransient: true},
+		{name: "deadlock", err: &pgconn.PgError{Code: "40P01"}, wantClass: ErrSerializationFailure, wantTransient: true},
+		{name: "connection failure", err: &pgconn.PgError{Code: "08006"}, wantClass: ErrConnectionFailed, wantTransient: true},
+		{name: "admin shutdown", err: &pgconn.PgError{Code: "57P01"}, wantClass: ErrConnectionFailed, wantTransient: true},
+		{name: "wrapped server error", err: fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505"}), wantClass: ErrUniqueViolation},
+		{name: "invalid input", err: &pgconn.PgError{Code: "22P02"}, wantClass: ErrInvalidData},
+		{name: "string too long", err: &pgconn.PgError{Code: "22001"}, wantClass: ErrInvalidData},
+		{name: "numeric overflow", err: &pgconn.PgError{Code: "22003"}, wantClass: ErrInvalidData},
+		{name: "check violation", err: &pgconn.PgError{Code: "23514"}, wantClass: ErrInvalidData},
+		{name: "not null violation", err: &pgconn.PgError{Code: "23502"}},
+		{name: "connect error", err: &pgconn.ConnectError{Config: &pgconn.Config{}}, wantClass: ErrConnectionFailed, wantTransient: true},
+		{name: "not sent to server", err: safeToRetryError{}, wantClass: ErrConnectionFailed, wantTransient: true},
+		{name: "canceled", err: context.Canceled},
+		{name: "deadline exceeded", err: context.DeadlineExceeded},
+		{name: "unknown", err: errors.New("unknown")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoErr := pgError(tt.err, ErrQueryFailed)
+			if repoErr.Class != tt.wantClass {
+				t.Errorf("class = %v, want %v", repoErr.Class, tt.wantClass)
+			}
+			if repoErr.Transient() != tt.wantTransient {
+				t.Errorf("transient = %v, want %v", repoErr.Transient(), tt.wantTransient)
+			}
+			if !errors.Is(repoErr, ErrQueryFailed) || !errors.Is(repoErr, tt.err) {
+				t.Error("error does not wrap the domain error and the driver error")
+			}
+		})
+	}
+}
+
+func TestSQLiteErrorClassification(t *testing.T) {
+	db, err := sqlite.New(context.Background(), config.SQLiteConfig{Path: filepath.Join(t.TempDir(), "errors.db")})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+	setup := `CREATE TABLE parents (id INTEGER PRIMARY KEY, name TEXT UNIQUE);
+		CREATE TABLE children (parent_id INTEGER REFERENCES parents (id));
+		CREATE TABLE amounts (amount INTEGER CHECK (amount >= 0));
+		INSERT INTO parents (id, name) VALUES (1, 'a');`
+	if _, err := db.Exec(setup); err != nil {
+		t.Fatalf("failed to create tables: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		wantClass error
+	}{
+		{name: "unique violation", query: `INSERT INTO parents (id, name) VALUES (2, 'a')`, wantClass: ErrUniqueViolation},
+		{name: "primary key violation", query: `INSERT INTO parents (id, name) VALUES (1, 'b')`, wantClass: ErrUniqueViolation},
+		{name: "foreign key violation", query: `INSERT INTO children (parent_id) VALUES (42)`, wantClass: ErrForeignKeyViolation},
+		{name: "check violation", query: `INSERT INTO amounts (amount) VALUES (-1)`, wantClass: ErrInvalidData},
+		{name: "syntax error", query: `INSERT INTO`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.Exec(tt.query)
+			if err == nil {
+				t.Fatal("query succeeded")
+			}
+			repoErr := sqliteError(err, ErrQueryFailed)
+			if repoErr.Class != tt.wantClass {
+				t.Errorf("class = %v, want %v (driver error: %v)", repoErr.Class, tt.wantClass, err)
+			}
+			if repoErr.Transient() {
+				t.Error("constraint and syntax errors must not be transient")
+			}
+		})
+	}
+}
+
+func TestRetryResult(t *testing.T) {
+	transient := &Error{Kind: ErrQueryFailed, Class: ErrSerializationFailure, Err: errors.New("40001"), transient: true}
+	permanent := &Error{Kind: ErrQueryFailed, Class: ErrUniqueViolation, Err: errors.New("23505")}
+
+	tests := []struct {
+		name string
+		// errs - ошибки попыток по порядку, после них операция успешна
+		errs      []error
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "success", errs: nil, wantCalls: 1},
+		{name: "transient then success", errs: []error{transient}, wantCalls: 2},
+		{name: "transient until attempts run out", errs: []error{transient, transient, transient, transient}, wantCalls: retryAttempts, wantErr: transient},
+		{name: "permanent", errs: []error{permanent}, wantCalls: 1, wantErr: permanent},
+		{name: "not a repository error", errs: []error{ErrProductNotFound}, wantCalls: 1, wantErr: ErrProductNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			_, err := retryResult(context.Background(), newRetryPolicy(testLog()), func() (int, error) {
+				calls++
+				if calls <= len(tt.errs) {
+					return 0, tt.errs[calls-1]
+				}
+				return calls, nil
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("op called %d times, want %d", calls, tt.wantCalls)
+			}
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("canceled context stops retries", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		calls := 0
+		err := retry(ctx, newRetryPolicy(testLog()), func() error {
+			calls++
+			return transient
+		})
+		if calls != 1 || !errors.Is(err, ErrSerializationFailure) {
+			t.Errorf("got %d calls and %v, want 1 call and the transient error", calls, err)
+		}
+	})
+}
@@ -0,0 +1,420 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"user-service/internal/entity"
+)
+
+var (
+	_ Repository           = (*memoryRepository)(nil)
+	_ CatalogRepository    = (*memoryRepository)(nil)
+	_ RevocationRepository = (*memoryRepository)(nil)
+)
+
+// memoryRepository - хранилище в памяти процесса для режима разработки.
+// Реализует Repository, CatalogRepository и RevocationRepository с той же семантикой
+// и теми же ошибками, что и Postgres, и может сохранять снимок состояния в JSON-файл.
+type memoryRepository struct {
+	log *slog.Logger
+
+	mu    sync.RWMutex
+	state memoryState
+}
+
+// memoryState - все данные хранилища; в таком виде они попадают в снимок
+type memoryState struct {
+	// Products - продукты пользователей по user_id и идентификатору продукта
+	Products map[string]map[string]entity.Product `json:"products"`
+	// Preferences - предпочтения по user_id
+	Preferences map[string]memoryPreferences `json:"preferences"`
+	// Catalog - записи каталога по идентификатору
+	Catalog map[string]entity.CatalogProduct `json:"catalog"`
+	// Aliases - идентификатор записи каталога по нормализованному синониму
+	Aliases map[string]string `json:"aliases"`
+	// RevokedTokens - отозванные токены по jti
+	RevokedTokens map[string]entity.RevokedToken `json:"revoked_tokens"`
+	// UserRevocations - граница отзыва токенов по user_id
+	UserRevocations map[string]time.Time `json:"user_revocations"`
+	// Outbox - неопубликованные доменные события по возрастанию ID
+	Outbox []entity.Event `json:"outbox"`
+	// NextEventID - ID последнего записанного события
+	NextEventID int64 `json:"next_event_id"`
+}
+
+// memoryPreferences - аналог строки user_preferences и строк user_preference_entries пользователя
+type memoryPreferences struct {
+	// Targets - цели по калориям и БЖУ, nil если строки user_preferences нет
+	Targets   *entity.NutritionTargets `json:"targets,omitempty"`
+	UpdatedAt time.Time                `json:"updated_at"`
+	Entries   []memoryPreferenceEntry  `json:"entries"`
+}
+
+type memoryPreferenceEntry struct {
+	entity.PreferenceEntry
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewMemory - создает пустое хранилище в памяти
+func NewMemory(log *slog.Logger) *memoryRepository {
+	return &memoryRepository{
+		log:   log,
+		state: newMemoryState(),
+	}
+}
+
+func newMemoryState() memoryState {
+	return memoryState{
+		Products:        make(map[string]map[string]entity.Product),
+		Preferences:     make(map[string]memoryPreferences),
+		Catalog:         make(map[string]entity.CatalogProduct),
+		Aliases:         make(map[string]string),
+		RevokedTokens:   make(map[string]entity.RevokedToken),
+		UserRevocations: make(map[string]time.Time),
+	}
+}
+
+// LoadSnapshot - заменяет состояние хранилища снимком из файла; отсутствие файла не ошибка
+func (r *memoryRepository) LoadSnapshot(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	state := newMemoryState()
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to decode snapshot %s: %w", path, err)
+	}
+	r.mu.Lock()
+	r.state = state
+	r.mu.Unlock()
+	r.log.Info("Snapshot loaded", "path", path)
+	return nil
+}
+
+// SaveSnapshot - записывает состояние хранилища в файл; файл заменяется атомарно
+func (r *memoryRepository) SaveSnapshot(path string) error {
+	r.mu.RLock()
+	data, err := json.MarshalIndent(r.state, "", "  ")
+	r.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	r.log.Info("Snapshot saved", "path", path)
+	return nil
+}
+
+// Ping - хранилище в памяти всегда доступно
+func (r *memoryRepository) Ping(context.Context) error {
+	return nil
+}
+
+// WatchChanges - других реплик у хранилища в памяти нет, а его изменения
+// кэш сбрасывает сам, поэтому после подписки остается только ждать отмены ctx
+func (r *memoryRepository) WatchChanges(ctx context.Context, subscribed func() error, changed func(Change)) error {
+	if err := subscribed(); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// WithTx - выполняет fn над копией состояния и, если fn завершилась без ошибки, заменяет ею
+// состояние хранилища. Пока fn выполняется, хранилище заблокировано, что соответствует
+// уровню Serializable при любых opts; внутри fn нужно использовать только переданный репозиторий.
+func (r *memoryRepository) WithTx(ctx context.Context, fn func(Repository) error, opts ...TxOption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tx := &memoryRepository{
+		log:   r.log,
+		state: r.state.clone(),
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	r.state = tx.state
+	return nil
+}
+
+// clone - копия состояния, не разделяющая память с исходным
+func (s memoryState) clone() memoryState {
+	clone := newMemoryState()
+	for userId, products := range s.Products {
+		userProducts := make(map[string]entity.Product, len(products))
+		for id, product := range products {
+			userProducts[id] = copyProduct(product)
+		}
+		clone.Products[userId] = userProducts
+	}
+	for userId, preferences := range s.Preferences {
+		if preferences.Targets != nil {
+			targets := *preferences.Targets
+			preferences.Targets = &targets
+		}
+		preferences.Entries = slices.Clone(preferences.Entries)
+		clone.Preferences[userId] = preferences
+	}
+	for id, product := range s.Catalog {
+		clone.Catalog[id] = copyCatalogProduct(product)
+	}
+	maps.Copy(clone.Aliases, s.Aliases)
+	maps.Copy(clone.RevokedTokens, s.RevokedTokens)
+	maps.Copy(clone.UserRevocations, s.UserRevocations)
+	clone.Outbox = slices.Clone(s.Outbox)
+	clone.NextEventID = s.NextEventID
+	return clone
+}
+
+// memoryNow - текущее время с точностью TIMESTAMP в Postgres
+func memoryNow() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func (r *memoryRepository) GetProducts(ctx context.Context, userId string, productQuery ProductQuery) (ProductPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var after *entity.Product
+	if productQuery.After != nil {
+		after = &entity.Product{
+			ID:        productQuery.After.ID,
+			Name:      productQuery.After.Name,
+			CreatedAt: productQuery.After.CreatedAt,
+			ExpiresAt: productQuery.After.ExpiresAt,
+		}
+	}
+	var page ProductPage
+	for _, product := range r.state.Products[userId] {
+		if !productQuery.Filter.matches(product) {
+			continue
+		}
+		if after != nil && productQuery.compare(product, *after) <= 0 {
+			continue
+		}
+		page.Products = append(page.Products, copyProduct(product))
+	}
+	slices.SortFunc(page.Products, productQuery.compare)
+
+	if len(page.Products) > productQuery.Limit {
+		page.Products = page.Products[:productQuery.Limit]
+		next := CursorFromProduct(page.Products[len(page.Products)-1])
+		page.Next = &next
+	}
+	return page, nil
+}
+
+// matches - проходит ли продукт фильтр; аналог условий WHERE из ProductQuery.build
+func (f ProductFilter) matches(product entity.Product) bool {
+	if f.NamePrefix != "" && !strings.HasPrefix(strings.ToLower(product.Name), strings.ToLower(f.NamePrefix)) {
+		return false
+	}
+	if f.ExpiringBefore != nil && (product.ExpiresAt == nil || !product.ExpiresAt.Before(*f.ExpiringBefore)) {
+		return false
+	}
+	if f.Category != "" && product.Category != f.Category {
+		return false
+	}
+	return true
+}
+
+// compare - сравнивает продукты в порядке выдачи страницы; аналог ORDER BY из ProductQuery.build
+func (q ProductQuery) compare(a, b entity.Product) int {
+	var result int
+	switch q.SortBy {
+	case SortByName:
+		result = strings.Compare(a.Name, b.Name)
+	case SortByExpiresAt:
+		// Продукты без срока годности идут последними при любом направлении сортировки
+		switch {
+		case a.ExpiresAt == nil && b.ExpiresAt != nil:
+			return 1
+		case a.ExpiresAt != nil && b.ExpiresAt == nil:
+			return -1
+		case a.ExpiresAt != nil:
+			result = a.ExpiresAt.Compare(*b.ExpiresAt)
+		}
+	default:
+		result = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	result = cmp.Or(result, strings.Compare(a.ID, b.ID))
+	if q.Descending {
+		return -result
+	}
+	return result
+}
+
+func (r *memoryRepository) AddProduct(ctx context.Context, userId string, product entity.Product) (entity.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	products := r.userProducts(userId)
+	added, err := r.insertProduct(products, product)
+	if err != nil {
+		return entity.Product{}, err
+	}
+	return added, nil
+}
+
+func (r *memoryRepository) RemoveProduct(ctx context.Context, userId string, productId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.state.Products[userId][productId]; !ok {
+		return ErrProductNotFound
+	}
+	delete(r.state.Products[userId], productId)
+	return nil
+}
+
+func (r *memoryRepository) RemoveProductByName(ctx context.Context, userId string, productName string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var removed int64
+	for id, product := range r.state.Products[userId] {
+		if product.Name == productName {
+			delete(r.state.Products[userId], id)
+			removed++
+		}
+	}
+	if removed == 0 {
+		return 0, ErrProductNotFound
+	}
+	return removed, nil
+}
+
+// AddProducts - добавляет продукты пакетом с той же семантикой, что и в Postgres:
+// при atomic = true любая ошибка оставляет продукты пользователя без изменений
+func (r *memoryRepository) AddProducts(ctx context.Context, userId string, products []entity.Product, atomic bool) ([]BatchResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.runBatch(userId, len(products), atomic, func(staged map[string]entity.Product, i int) (entity.Product, error) {
+		return r.insertProduct(staged, products[i])
+	})
+}
+
+// RemoveProducts - удаляет продукты пакетом, семантика такая же, как у AddProducts
+func (r *memoryRepository) RemoveProducts(ctx context.Context, userId string, productIds []string, atomic bool) ([]BatchResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.runBatch(userId, len(productIds), atomic, func(staged map[string]entity.Product, i int) (entity.Product, error) {
+		if _, ok := staged[productIds[i]]; !ok {
+			return entity.Product{}, ErrProductNotFound
+		}
+		delete(staged, productIds[i])
+		return entity.Product{ID: productIds[i]}, nil
+	})
+}
+
+// runBatch - применяет элементы пакета к копии продуктов пользователя
+// и сохраняет копию, если пакет не откатывается. Вызывается под блокировкой.
+func (r *memoryRepository) runBatch(
+	userId string,
+	n int,
+	atomic bool,
+	apply func(staged map[string]entity.Product, i int) (entity.Product, error),
+) ([]BatchResult, error) {
+	staged := make(map[string]entity.Product, len(r.state.Products[userId])+n)
+	for id, product := range r.state.Products[userId] {
+		staged[id] = product
+	}
+
+	results := make([]BatchResult, n)
+	failed := false
+	for i := range results {
+		product, err := apply(staged, i)
+		if err != nil {
+			results[i].Err = err
+			failed = true
+			continue
+		}
+		results[i].Product = product
+	}
+
+	if atomic && failed {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i] = BatchResult{Err: ErrBatchAborted}
+			}
+		}
+		return results, nil
+	}
+	r.state.Products[userId] = staged
+	return results, nil
+}
+
+// sameExpiry - совпадают ли сроки годности двух партий продукта; продукты без срока - одна партия
+func sameExpiry(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// userProducts - продукты пользователя, создает пустой набор при первом обращении.
+// Вызывается под блокировкой на запись.
+func (r *memoryRepository) userProducts(userId string) map[string]entity.Product {
+	products, ok := r.state.Products[userId]
+	if !ok {
+		products = make(map[string]entity.Product)
+		r.state.Products[userId] = products
+	}
+	return products
+}
+
+// insertProduct - аналог insertProduct для Postgres: проверяет уникальность названия вместе со сроком годности
+// и ссылку на каталог, назначает идентификатор и время создания
+func (r *memoryRepository) insertProduct(products map[string]entity.Product, product entity.Product) (entity.Product, error) {
+	for _, existing := range products {
+		if existing.Name == product.Name && sameExpiry(existing.ExpiresAt, product.ExpiresAt) {
+			return entity.Product{}, ErrProductAlreadyExists
+		}
+	}
+	if product.CatalogProductID != "" {
+		if _, ok := r.state.Catalog[product.CatalogProductID]; !ok {
+			return entity.Product{}, ErrCatalogProductNotFound
+		}
+	}
+	now := memoryNow()
+	product.ID = uuid.NewString()
+	product.CreatedAt = now
+	product.UpdatedAt = now
+	products[product.ID] = product
+	return copyProduct(product), nil
+}
+
+// copyProduct - копия продукта, не разделяющая память со значением в хранилище
+func copyProduct(product entity.Product) entity.Product {
+	if product.ExpiresAt != nil {
+		expiresAt := *product.ExpiresAt
+		product.ExpiresAt = &expiresAt
+	}
+	return product
+}
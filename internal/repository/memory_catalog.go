@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"user-service/internal/entity"
+)
+
+func (r *memoryRepository) ResolveProduct(ctx context.Context, name string) (entity.CatalogProduct, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	productId, ok := r.state.Aliases[entity.NormalizeProductName(name)]
+	if !ok {
+		return entity.CatalogProduct{}, ErrCatalogProductNotFound
+	}
+	return copyCatalogProduct(r.state.Catalog[productId]), nil
+}
+
+func (r *memoryRepository) UpsertProduct(ctx context.Context, product entity.CatalogProduct) (entity.CatalogProduct, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := memoryNow()
+	saved := entity.CatalogProduct{ID: uuid.NewString(), CreatedAt: now}
+	for _, existing := range r.state.Catalog {
+		if existing.CanonicalName == product.CanonicalName {
+			saved = existing
+			break
+		}
+	}
+	aliases := catalogAliases(product)
+	// Как и транзакция в Postgres, конфликт синонима не меняет ничего
+	for _, alias := range aliases {
+		if owner, ok := r.state.Aliases[alias]; ok && owner != saved.ID {
+			return entity.CatalogProduct{}, ErrCatalogAliasConflict
+		}
+	}
+
+	for _, alias := range saved.Aliases {
+		delete(r.state.Aliases, alias)
+	}
+	for _, alias := range aliases {
+		r.state.Aliases[alias] = saved.ID
+	}
+	slices.Sort(aliases)
+	saved.CanonicalName = product.CanonicalName
+	saved.Category = product.Category
+	saved.DisplayNames = maps.Clone(product.DisplayNames)
+	if saved.DisplayNames == nil {
+		saved.DisplayNames = map[string]string{}
+	}
+	saved.Aliases = aliases
+	saved.UpdatedAt = now
+	r.state.Catalog[saved.ID] = saved
+	return copyCatalogProduct(saved), nil
+}
+
+func (r *memoryRepository) DeleteProduct(ctx context.Context, productId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	product, ok := r.state.Catalog[productId]
+	if !ok {
+		return ErrCatalogProductNotFound
+	}
+	delete(r.state.Catalog, productId)
+	for _, alias := range product.Aliases {
+		delete(r.state.Aliases, alias)
+	}
+	// ON DELETE SET NULL: продукты пользователей становятся пользовательскими
+	for _, products := range r.state.Products {
+		for id, userProduct := range products {
+			if userProduct.CatalogProductID == productId {
+				userProduct.CatalogProductID = ""
+				products[id] = userProduct
+			}
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) ListProducts(ctx context.Context, namePrefix string, after string, limit int) ([]entity.CatalogProduct, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var products []entity.CatalogProduct
+	for _, product := range r.state.Catalog {
+		if strings.HasPrefix(strings.ToLower(product.CanonicalName), strings.ToLower(namePrefix)) && product.CanonicalName > after {
+			products = append(products, copyCatalogProduct(product))
+		}
+	}
+	slices.SortFunc(products, func(a, b entity.CatalogProduct) int {
+		return strings.Compare(a.CanonicalName, b.CanonicalName)
+	})
+	if len(products) > limit {
+		products = products[:limit]
+	}
+	return products, nil
+}
+
+// copyCatalogProduct - копия записи каталога, не разделяющая память со значением в хранилище
+func copyCatalogProduct(product entity.CatalogProduct) entity.CatalogProduct {
+	product.DisplayNames = maps.Clone(product.DisplayNames)
+	product.Aliases = slices.Clone(product.Aliases)
+	return product
+}
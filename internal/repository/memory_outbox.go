@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"user-service/internal/entity"
+)
+
+var _ OutboxRepository = (*memoryRepository)(nil)
+
+func (r *memoryRepository) AddEvents(ctx context.Context, events ...entity.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := memoryNow()
+	for _, event := range events {
+		r.state.NextEventID++
+		event.ID, event.CreatedAt = r.state.NextEventID, now
+		r.state.Outbox = append(r.state.Outbox, event)
+	}
+	return nil
+}
+
+// PublishEvents - события публикуются без блокировки хранилища, а удаляются после публикации.
+// Новые события добавляются в конец outbox, поэтому опубликованные - это его начало.
+func (r *memoryRepository) PublishEvents(ctx context.Context, limit int, publish func(context.Context, []entity.Event) (int, error)) (int, error) {
+	r.mu.RLock()
+	events := slices.Clone(r.state.Outbox[:min(limit, len(r.state.Outbox))])
+	r.mu.RUnlock()
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, outboxPublishTimeout)
+	published, err := publish(publishCtx, events)
+	cancel()
+	if published == 0 {
+		return 0, err
+	}
+	last := events[published-1].ID
+	r.mu.Lock()
+	r.state.Outbox = slices.DeleteFunc(r.state.Outbox, func(event entity.Event) bool { return event.ID <= last })
+	r.mu.Unlock()
+	return published, err
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"user-service/internal/entity"
+)
+
+func (r *memoryRepository) GetPreference(ctx context.Context, userId string) (entity.Preferences, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	stored, ok := r.state.Preferences[userId]
+	if !ok || (stored.Targets == nil && len(stored.Entries) == 0) {
+		return entity.Preferences{}, ErrPreferenceNotFound
+	}
+
+	var preferences entity.Preferences
+	if stored.Targets != nil {
+		preferences.Targets = *stored.Targets
+		preferences.UpdatedAt = stored.UpdatedAt
+	}
+	entries := slices.Clone(stored.Entries)
+	slices.SortFunc(entries, func(a, b memoryPreferenceEntry) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Value, b.Value))
+	})
+	for _, entry := range entries {
+		preferences.Add(entry.PreferenceEntry)
+		if entry.CreatedAt.After(preferences.UpdatedAt) {
+			preferences.UpdatedAt = entry.CreatedAt
+		}
+	}
+	return preferences, nil
+}
+
+func (r *memoryRepository) UpdatePreference(ctx context.Context, userId string, preferences entity.Preferences) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := memoryNow()
+	targets := preferences.Targets
+	stored := memoryPreferences{Targets: &targets, UpdatedAt: now}
+	for _, entry := range preferences.Entries() {
+		if !slices.ContainsFunc(stored.Entries, func(e memoryPreferenceEntry) bool { return e.PreferenceEntry == entry }) {
+			stored.Entries = append(stored.Entries, memoryPreferenceEntry{PreferenceEntry: entry, CreatedAt: now})
+		}
+	}
+	r.state.Preferences[userId] = stored
+	return nil
+}
+
+func (r *memoryRepository) RemovePreference(ctx context.Context, userId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.state.Preferences, userId)
+	return nil
+}
+
+func (r *memoryRepository) AddPreferenceEntry(ctx context.Context, userId string, entry entity.PreferenceEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.state.Preferences[userId]
+	if slices.ContainsFunc(stored.Entries, func(e memoryPreferenceEntry) bool { return e.PreferenceEntry == entry }) {
+		return ErrPreferenceExists
+	}
+	stored.Entries = append(stored.Entries, memoryPreferenceEntry{PreferenceEntry: entry, CreatedAt: memoryNow()})
+	r.state.Preferences[userId] = stored
+	return nil
+}
+
+func (r *memoryRepository) RemovePreferenceEntry(ctx context.Context, userId string, entry entity.PreferenceEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.state.Preferences[userId]
+	i := slices.IndexFunc(stored.Entries, func(e memoryPreferenceEntry) bool { return e.PreferenceEntry == entry })
+	if i < 0 {
+		return ErrPreferenceNotFound
+	}
+	stored.Entries = slices.Delete(stored.Entries, i, i+1)
+	r.state.Preferences[userId] = stored
+	return nil
+}
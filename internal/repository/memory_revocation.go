@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"user-service/internal/entity"
+)
+
+func (r *memoryRepository) RevokeToken(ctx context.Context, token entity.RevokedToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.state.RevokedTokens[token.TokenID]; ok && existing.ExpiresAt.After(token.ExpiresAt) {
+		token.ExpiresAt = existing.ExpiresAt
+	}
+	r.state.RevokedTokens[token.TokenID] = token
+	// Отзывы истекших токенов больше ни на что не влияют
+	now := time.Now()
+	for tokenId, revoked := range r.state.RevokedTokens {
+		if revoked.ExpiresAt.Before(now) {
+			delete(r.state.RevokedTokens, tokenId)
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) RevokeUserTokens(ctx context.Context, revocation entity.UserTokensRevocation) (entity.UserTokensRevocation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.state.UserRevocations[revocation.UserID]; ok && existing.After(revocation.RevokedBefore) {
+		revocation.RevokedBefore = existing
+	}
+	r.state.UserRevocations[revocation.UserID] = revocation.RevokedBefore
+	return revocation, nil
+}
+
+func (r *memoryRepository) ListRevocations(ctx context.Context) (entity.Revocations, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var revocations entity.Revocations
+	now := time.Now()
+	for _, revoked := range r.state.RevokedTokens {
+		if !revoked.ExpiresAt.Before(now) {
+			revocations.Tokens = append(revocations.Tokens, revoked)
+		}
+	}
+	for userId, revokedBefore := range r.state.UserRevocations {
+		revocations.Users = append(revocations.Users, entity.UserTokensRevocation{UserID: userId, RevokedBefore: revokedBefore})
+	}
+	return revocations, nil
+}
+
+// WatchRevocations - других реплик у хранилища в памяти нет, а отзывы этого процесса
+// usecase применяет к кэшу сам, поэтому после подписки остается только ждать отмены ctx
+func (r *memoryRepository) WatchRevocations(ctx context.Context, subscribed func() error, changed func(entity.Revocations)) error {
+	if err := subscribed(); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
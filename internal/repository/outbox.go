@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
+)
+
+// Классы advisory-блокировок outbox, объект блокировки - второй аргумент pg_advisory_xact_lock
+const (
+	// outboxUserLock - блокировка по пользователю: транзакции с событиями одного пользователя
+	// выполняются по очереди, поэтому порядок id его событий совпадает с порядком фиксации
+	outboxUserLock int32 = 1001
+	// outboxRelayLock - блокировка relay: события захватывает одна реплика за раз
+	outboxRelayLock int32 = 1002
+)
+
+const (
+	// outboxClaimTimeout - на сколько relay захватывает события; если реплика упала,
+	// не успев их опубликовать, после этого срока события публикует другая
+	outboxClaimTimeout = time.Minute
+	// outboxPublishTimeout - сколько ждать публикации пачки, с запасом меньше outboxClaimTimeout,
+	// чтобы захват не истек, пока события еще публикуются
+	outboxPublishTimeout = 45 * time.Second
+)
+
+var _ OutboxRepository = (*outboxRepository)(nil)
+
+type OutboxRepository interface {
+	// PublishEvents - передает в publish до limit самых старых событий outbox по порядку
+	// и удаляет опубликованные; publish возвращает, сколько первых событий опубликовано.
+	// Контекст publish ограничен outboxPublishTimeout. Если события сейчас публикует
+	// другая реплика, ничего не делает.
+	PublishEvents(ctx context.Context, limit int, publish func(context.Context, []entity.Event) (int, error)) (int, error)
+}
+
+type outboxRepository struct {
+	db  *pgxpool.Pool
+	log *slog.Logger
+}
+
+func NewOutbox(db *pgxpool.Pool, log *slog.Logger) *outboxRepository {
+	return &outboxRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *repository) AddEvents(ctx context.Context, events ...entity.Event) error {
+	defer metrics.ObserveQuery("add_events")()
+	if len(events) == 0 {
+		return nil
+	}
+	userIds := make([]string, len(events))
+	types := make([]string, len(events))
+	payloads := make([]string, len(events))
+	for i, event := range events {
+		userIds[i], types[i], payloads[i] = event.UserID, string(event.Type), string(event.Payload)
+	}
+	// Блокировки берутся в одном порядке, чтобы транзакции не ждали друг друга по кругу
+	locked := slices.Compact(slices.Sorted(slices.Values(userIds)))
+
+	query := `INSERT INTO outbox_events (user_id, type, payload)
+		SELECT e.user_id::uuid, e.type, e.payload::jsonb
+		FROM unnest($1::text[], $2::text[], $3::text[]) WITH ORDINALITY AS e(user_id, type, payload, n)
+		ORDER BY e.n`
+	return retry(ctx, r.retries, func() error {
+		for _, userId := range locked {
+			if _, err := r.db.Exec(ctx, `SELECT pg_advisory_xact_lock($1, hashtext($2))`, outboxUserLock, userId); err != nil {
+				return pgError(err, ErrQueryFailed)
+			}
+		}
+		if _, err := r.db.Exec(ctx, query, userIds, types, payloads); err != nil {
+			return pgError(err, ErrQueryFailed)
+		}
+		return nil
+	})
+}
+
+// PublishEvents - захватывает события в короткой транзакции, публикует их вне транзакции
+// и удаляет опубликованные во второй короткой транзакции, поэтому ни соединение пула,
+// ни блокировка relay не заняты, пока брокер отвечает
+func (r *outboxRepository) PublishEvents(ctx context.Context, limit int, publish func(context.Context, []entity.Event) (int, error)) (int, error) {
+	defer metrics.ObserveQuery("publish_events")()
+	events, err := r.claimEvents(ctx, limit)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, outboxPublishTimeout)
+	published, publishErr := publish(publishCtx, events)
+	cancel()
+
+	// Опубликованные события удаляются и при отмене ctx, иначе они уйдут в брокер повторно
+	if err := r.completeEvents(context.WithoutCancel(ctx), events, published); err != nil {
+		return published, err
+	}
+	return published, publishErr
+}
+
+// claimEvents - захватывает до limit самых старых событий на outboxClaimTimeout.
+// Пока действует захват другой реплики, новые события не захватываются: иначе события
+// одного пользователя могли бы уйти в брокер не по порядку.
+func (r *outboxRepository) claimEvents(ctx context.Context, limit int) ([]entity.Event, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, pgError(err, ErrTransactionFailed)
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1, 0)`, outboxRelayLock).Scan(&locked); err != nil {
+		return nil, pgError(err, ErrQueryFailed)
+	}
+	if !locked {
+		return nil, nil
+	}
+
+	query := `WITH claimed AS (
+			SELECT id FROM outbox_events
+			WHERE NOT EXISTS (SELECT 1 FROM outbox_events WHERE claimed_until > now())
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE outbox_events e SET claimed_until = now() + make_interval(secs => $2), attempts = e.attempts + 1
+		FROM claimed WHERE e.id = claimed.id
+		RETURNING e.id, e.user_id::text, e.type, e.payload, e.created_at, e.attempts`
+	rows, err := tx.Query(ctx, query, limit, outboxClaimTimeout.Seconds())
+	if err != nil {
+		return nil, pgError(err, ErrQueryFailed)
+	}
+	var retried int
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Event, error) {
+		var event entity.Event
+		var attempts int
+		err := row.Scan(&event.ID, &event.UserID, &event.Type, &event.Payload, &event.CreatedAt, &attempts)
+		if attempts > 1 {
+			retried++
+		}
+		return event, err
+	})
+	if err != nil {
+		return nil, pgError(err, ErrQueryFailed)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, pgError(err, ErrTransactionFailed)
+	}
+
+	// RETURNING не сохраняет порядок подзапроса
+	slices.SortFunc(events, func(a, b entity.Event) int { return cmp.Compare(a.ID, b.ID) })
+	if retried > 0 {
+		r.log.WarnContext(ctx, "Republishing outbox events claimed by an unfinished attempt", "count", retried)
+	}
+	return events, nil
+}
+
+// completeEvents - удаляет первые published событий и снимает захват с остальных,
+// чтобы следующая попытка не ждала истечения захвата
+func (r *outboxRepository) completeEvents(ctx context.Context, events []entity.Event, published int) error {
+	ids := make([]int64, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return pgError(err, ErrTransactionFailed)
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, `DELETE FROM outbox_events WHERE id = ANY($1)`, ids[:published]); err != nil {
+		return pgError(err, ErrQueryFailed)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE outbox_events SET claimed_until = NULL WHERE id = ANY($1)`, ids[published:]); err != nil {
+		return pgError(err, ErrQueryFailed)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return pgError(err, ErrTransactionFailed)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"user-service/internal/entity"
+)
+
+// testOutbox - хранилище, в которое пишутся события, и outbox того же хранилища
+type testOutbox struct {
+	repo   Repository
+	outbox OutboxRepository
+}
+
+// testOutboxBackends - outbox всех хранилищ, которые можно поднять в тесте
+func testOutboxBackends(t *testing.T) map[string]testOutbox {
+	t.Helper()
+	memory := NewMemory(testLog())
+	sqlite := newTestSQLite(t)
+	backends := map[string]testOutbox{
+		"memory": {repo: memory, outbox: memory},
+		"sqlite": {repo: sqlite, outbox: sqlite},
+	}
+	if os.Getenv(testPostgresEnv) != "" {
+		pool := newTestPostgresPool(t)
+		backends["postgres"] = testOutbox{repo: New(pool, testLog()), outbox: NewOutbox(pool, testLog())}
+	}
+	return backends
+}
+
+// drainOutbox - публикует все события, оставшиеся от других тестов
+func drainOutbox(t *testing.T, outbox OutboxRepository) {
+	t.Helper()
+	for {
+		published, err := outbox.PublishEvents(context.Background(), 100, func(_ context.Context, events []entity.Event) (int, error) {
+			return len(events), nil
+		})
+		if err != nil {
+			t.Fatalf("failed to drain outbox: %v", err)
+		}
+		if published == 0 {
+			return
+		}
+	}
+}
+
+func TestPublishEventsKeepsUnpublished(t *testing.T) {
+	errBroker := errors.New("broker is unavailable")
+	for backend, b := range testOutboxBackends(t) {
+		t.Run(backend, func(t *testing.T) {
+			drainOutbox(t, b.outbox)
+			ctx, userId := testUser()
+			events := []entity.Event{
+				{Type: entity.EventProductAdded, UserID: userId, Payload: []byte(`{"n":1}`)},
+				{Type: entity.EventProductAdded, UserID: userId, Payload: []byte(`{"n":2}`)},
+				{Type: entity.EventProductRemoved, UserID: userId, Payload: []byte(`{"n":3}`)},
+			}
+			if err := b.repo.AddEvents(ctx, events...); err != nil {
+				t.Fatalf("AddEvents: %v", err)
+			}
+
+			// Брокер принял только первое событие
+			published, err := b.outbox.PublishEvents(ctx, 10, func(ctx context.Context, batch []entity.Event) (int, error) {
+				if _, ok := ctx.Deadline(); !ok {
+					t.Error("publish context has no deadline")
+				}
+				return 1, errBroker
+			})
+			if published != 1 || !errors.Is(err, errBroker) {
+				t.Fatalf("got %d published and %v, want 1 and the broker error", published, err)
+			}
+
+			// Следующая попытка начинается с первого неопубликованного события и не ждет истечения захвата
+			var numbers []int
+			_, err = b.outbox.PublishEvents(ctx, 10, func(_ context.Context, batch []entity.Event) (int, error) {
+				for _, event := range batch {
+					var payload struct{ N int }
+					if err := json.Unmarshal(event.Payload, &payload); err != nil {
+						t.Errorf("failed to decode payload %s: %v", event.Payload, err)
+					}
+					numbers = append(numbers, payload.N)
+				}
+				return len(batch), nil
+			})
+			if err != nil {
+				t.Fatalf("PublishEvents: %v", err)
+			}
+			if want := []int{2, 3}; !slices.Equal(numbers, want) {
+				t.Errorf("got events %v, want %v", numbers, want)
+			}
+		})
+	}
+}
+
+func TestPostgresPublishEventsClaim(t *testing.T) {
+	if os.Getenv(testPostgresEnv) == "" {
+		t.Skipf("%s is not set", testPostgresEnv)
+	}
+	b := testOutboxBackends(t)["postgres"]
+	drainOutbox(t, b.outbox)
+	ctx, userId := testUser()
+	if err := b.repo.AddEvents(ctx, entity.Event{Type: entity.EventProductAdded, UserID: userId, Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("AddEvents: %v", err)
+	}
+
+	published, err := b.outbox.PublishEvents(ctx, 10, func(ctx context.Context, batch []entity.Event) (int, error) {
+		// Пока события публикуются, другая реплика их не получает и не ждет блокировки
+		other, err := b.outbox.PublishEvents(ctx, 10, func(context.Context, []entity.Event) (int, error) {
+			t.Error("claimed events were published by another relay")
+			return 0, nil
+		})
+		if other != 0 || err != nil {
+			t.Errorf("another relay got %d events and %v", other, err)
+		}
+		return len(batch), nil
+	})
+	if published != 1 || err != nil {
+		t.Fatalf("got %d published and %v, want 1 and no error", published, err)
+	}
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
+)
+
+func (r *repository) GetPreference(ctx context.Context, userId string) (entity.Preferences, error) {
+	defer metrics.ObserveQuery("get_preference")()
+	return retryResult(ctx, r.retries, func() (entity.Preferences, error) {
+		var preferences entity.Preferences
+		found := false
+
+		query := `SELECT calories, protein_g, fat_g, carbs_g, updated_at FROM user_preferences WHERE user_id = $1`
+		err := r.db.QueryRow(ctx, query, userId).Scan(
+			&preferences.Targets.Calories,
+			&preferences.Targets.ProteinG,
+			&preferences.Targets.FatG,
+			&preferences.Targets.CarbsG,
+			&preferences.UpdatedAt,
+		)
+		switch {
+		case err == nil:
+			found = true
+		case !errors.Is(err, pgx.ErrNoRows):
+			return entity.Preferences{}, pgError(err, ErrQueryFailed)
+		}
+
+		query = `SELECT kind, value, created_at FROM user_preference_entries WHERE user_id = $1 ORDER BY kind, value`
+		rows, err := r.db.Query(ctx, query, userId)
+		if err != nil {
+			return entity.Preferences{}, pgError(err, ErrQueryFailed)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var entry entity.PreferenceEntry
+			var createdAt time.Time
+			if err := rows.Scan(&entry.Kind, &entry.Value, &createdAt); err != nil {
+				return entity.Preferences{}, pgError(err, ErrNoRows)
+			}
+			preferences.Add(entry)
+			if createdAt.After(preferences.UpdatedAt) {
+				preferences.UpdatedAt = createdAt
+			}
+			found = true
+		}
+		if err := rows.Err(); err != nil {
+			return entity.Preferences{}, pgError(err, ErrQueryFailed)
+		}
+
+		if !found {
+			return entity.Preferences{}, ErrPreferenceNotFound
+		}
+		return preferences, nil
+	})
+}
+
+func (r *repository) UpdatePreference(ctx context.Context, userId string, preferences entity.Preferences) error {
+	defer metrics.ObserveQuery("update_preference")()
+	err := retry(ctx, r.retries, func() error {
+		tx, err := r.db.Begin(ctx)
+		if err != nil {
+			return pgError(err, ErrTransactionFailed)
+		}
+		defer tx.Rollback(ctx)
+
+		query := `INSERT INTO user_preferences (user_id, calories, protein_g, fat_g, carbs_g)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (user_id) DO UPDATE
+			SET calories = EXCLUDED.calories, protein_g = EXCLUDED.protein_g,
+				fat_g = EXCLUDED.fat_g, carbs_g = EXCLUDED.carbs_g, updated_at = CURRENT_TIMESTAMP`
+		targets := preferences.Targets
+		if _, err := tx.Exec(ctx, query, userId, targets.Calories, targets.ProteinG, targets.FatG, targets.CarbsG); err != nil {
+			return pgError(err, ErrPreferenceUpdateFailed)
+		}
+
+		query = `DELETE FROM user_preference_entries WHERE user_id = $1`
+		if _, err := tx.Exec(ctx, query, userId); err != nil {
+			return pgError(err, ErrPreferenceUpdateFailed)
+		}
+		for _, entry := range preferences.Entries() {
+			query = `INSERT INTO user_preference_entries (user_id, kind, value) VALUES ($1, $2, $3)
+				ON CONFLICT DO NOTHING`
+			if _, err := tx.Exec(ctx, query, userId, entry.Kind, entry.Value); err != nil {
+				return pgError(err, ErrPreferenceUpdateFailed)
+			}
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return pgError(err, ErrTransactionFailed)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.log.DebugContext(ctx, "Preference updated successfully")
+	return nil
+}
+
+func (r *repository) RemovePreference(ctx context.Context, userId string) error {
+	defer metrics.ObserveQuery("remove_preference")()
+	err := retry(ctx, r.retries, func() error {
+		tx, err := r.db.Begin(ctx)
+		if err != nil {
+			return pgError(err, ErrTransactionFailed)
+		}
+		defer tx.Rollback(ctx)
+
+		if _, err := tx.Exec(ctx, `DELETE FROM user_preference_entries WHERE user_id = $1`, userId); err != nil {
+			return pgError(err, ErrQueryFailed)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM user_preferences WHERE user_id = $1`, userId); err != nil {
+			return pgError(err, ErrQueryFailed)
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return pgError(err, ErrTransactionFailed)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.log.DebugContext(ctx, "Preference removed successfully")
+	return nil
+}
+
+func (r *repository) AddPreferenceEntry(ctx context.Context, userId string, entry entity.PreferenceEntry) error {
+	defer metrics.ObserveQuery("add_preference_entry")()
+	query := `INSERT INTO user_preference_entries (user_id, kind, value) VALUES ($1, $2, $3)`
+	err := retry(ctx, r.retries, func() error {
+		if _, err := r.db.Exec(ctx, query, userId, entry.Kind, entry.Value); err != nil {
+			repoErr := pgError(err, ErrAddUserFailed)
+			if repoErr.Class == ErrUniqueViolation {
+				repoErr.Kind = ErrPreferenceExists
+			}
+			return repoErr
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.log.DebugContext(ctx, "Preference entry added successfully")
+	return nil
+}
+
+func (r *repository) RemovePreferenceEntry(ctx context.Context, userId string, entry entity.PreferenceEntry) error {
+	defer metrics.ObserveQuery("remove_preference_entry")()
+	query := `DELETE FROM user_preference_entries WHERE user_id = $1 AND kind = $2 AND value = $3`
+	removed, err := retryResult(ctx, r.retries, func() (int64, error) {
+		tag, err := r.db.Exec(ctx, query, userId, entry.Kind, entry.Value)
+		if err != nil {
+			return 0, pgError(err, ErrQueryFailed)
+		}
+		return tag.RowsAffected(), nil
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrPreferenceNotFound
+	}
+	r.log.DebugContext(ctx, "Preference entry removed successfully")
+	return nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"user-service/internal/entity"
+)
+
+// ProductSortField - поле сортировки списка продуктов
+type ProductSortField int
+
+const (
+	// SortByCreatedAt - сортировка по времени добавления (по умолчанию)
+	SortByCreatedAt ProductSortField = iota
+	// SortByName - сортировка по названию
+	SortByName
+	// SortByExpiresAt - сортировка по сроку годности, продукты без срока идут последними
+	// при любом направлении сортировки
+	SortByExpiresAt
+)
+
+// ProductFilter - фильтры списка продуктов, пустые поля не применяются
+type ProductFilter struct {
+	// NamePrefix - префикс названия без учета регистра
+	NamePrefix string
+	// ExpiringBefore - только продукты со сроком годности раньше указанного
+	ExpiringBefore *time.Time
+	// Category - точное совпадение категории
+	Category string
+}
+
+// ProductCursor - ключ последней записи страницы для keyset-пагинации
+type ProductCursor struct {
+	Name      string
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+	ID        string
+}
+
+// CursorFromProduct - строит курсор, указывающий на переданный продукт
+func CursorFromProduct(product entity.Product) ProductCursor {
+	return ProductCursor{
+		Name:      product.Name,
+		CreatedAt: product.CreatedAt,
+		ExpiresAt: product.ExpiresAt,
+		ID:        product.ID,
+	}
+}
+
+// ProductQuery - параметры выборки страницы продуктов
+type ProductQuery struct {
+	Filter     ProductFilter
+	SortBy     ProductSortField
+	Descending bool
+	// Limit - максимальный размер страницы
+	Limit int
+	// After - курсор, после которого начинается страница, nil для первой страницы
+	After *ProductCursor
+}
+
+// ProductPage - страница продуктов
+type ProductPage struct {
+	Products []entity.Product
+	// Next - курсор следующей страницы, nil если страница последняя
+	Next *ProductCursor
+}
+
+// sortExpression - выражение сортировки и значение курсора для него
+func (q ProductQuery) sortExpression() (string, any) {
+	var cursor ProductCursor
+	if q.After != nil {
+		cursor = *q.After
+	}
+	switch q.SortBy {
+	case SortByName:
+		return `product_name`, cursor.Name
+	case SortByExpiresAt:
+		// NULL не сравнивается в row-выражениях, поэтому продукты без срока годности получают
+		// срок, который идет последним в направлении сортировки (аналог NULLS LAST)
+		if q.Descending {
+			expiresAt := pgtype.Timestamp{InfinityModifier: pgtype.NegativeInfinity, Valid: true}
+			if cursor.ExpiresAt != nil {
+				expiresAt = pgtype.Timestamp{Time: *cursor.ExpiresAt, Valid: true}
+			}
+			return `COALESCE(expires_at, '-infinity'::timestamp)`, expiresAt
+		}
+		expiresAt := pgtype.Timestamp{InfinityModifier: pgtype.Infinity, Valid: true}
+		if cursor.ExpiresAt != nil {
+			expiresAt = pgtype.Timestamp{Time: *cursor.ExpiresAt, Valid: true}
+		}
+		return `COALESCE(expires_at, 'infinity'::timestamp)`, expiresAt
+	default:
+		return `created_at`, cursor.CreatedAt
+	}
+}
+
+// build - собирает SQL-запрос страницы продуктов пользователя и его аргументы.
+// Запрашивается на одну запись больше лимита, чтобы понять, есть ли следующая страница.
+func (q ProductQuery) build(userId string) (string, []any) {
+	args := []any{userId}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions := []string{`user_id = $1`}
+	if q.Filter.NamePrefix != "" {
+		conditions = append(conditions, `starts_with(lower(product_name), lower(`+arg(q.Filter.NamePrefix)+`))`)
+	}
+	if q.Filter.ExpiringBefore != nil {
+		conditions = append(conditions, `expires_at < `+arg(*q.Filter.ExpiringBefore))
+	}
+	if q.Filter.Category != "" {
+		conditions = append(conditions, `category = `+arg(q.Filter.Category))
+	}
+
+	sortExpr, cursorValue := q.sortExpression()
+	direction, comparison := "ASC", ">"
+	if q.Descending {
+		direction, comparison = "DESC", "<"
+	}
+	if q.After != nil {
+		conditions = append(conditions, fmt.Sprintf(`(%s, id) %s (%s, %s::uuid)`,
+			sortExpr, comparison, arg(cursorValue), arg(q.After.ID)))
+	}
+
+	query := `SELECT ` + productColumns + ` FROM user_products` +
+		` WHERE ` + strings.Join(conditions, ` AND `) +
+		fmt.Sprintf(` ORDER BY %s %s, id %s LIMIT %s`, sortExpr, direction, direction, arg(q.Limit+1))
+	return query, args
+}
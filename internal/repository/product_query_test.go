@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"user-service/internal/entity"
+)
+
+func TestGetProductsSortByExpiresAt(t *testing.T) {
+	january := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	march := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	products := []entity.Product{
+		{Name: "milk", Quantity: 1, ExpiresAt: &march},
+		{Name: "salt", Quantity: 1},
+		{Name: "eggs", Quantity: 1, ExpiresAt: &january},
+		{Name: "rice", Quantity: 1},
+	}
+
+	tests := []struct {
+		name       string
+		descending bool
+		// want - сроки годности страниц по порядку, nil - продукт без срока
+		want []*time.Time
+	}{
+		{name: "ascending", descending: false, want: []*time.Time{&january, &march, nil, nil}},
+		{name: "descending keeps products without expiry last", descending: true, want: []*time.Time{&march, &january, nil, nil}},
+	}
+
+	for backend, repo := range testBackends(t) {
+		ctx, userId := testUser()
+		for _, product := range products {
+			if _, err := repo.AddProduct(ctx, userId, product); err != nil {
+				t.Fatalf("%s: AddProduct(%s): %v", backend, product.Name, err)
+			}
+		}
+
+		for _, tt := range tests {
+			t.Run(backend+"/"+tt.name, func(t *testing.T) {
+				// Страницы по одному продукту проверяют и порядок, и курсор
+				query := ProductQuery{SortBy: SortByExpiresAt, Descending: tt.descending, Limit: 1}
+				var got []*time.Time
+				for range len(products) + 1 {
+					page, err := repo.GetProducts(ctx, userId, query)
+					if err != nil {
+						t.Fatalf("GetProducts: %v", err)
+					}
+					for _, product := range page.Products {
+						got = append(got, product.ExpiresAt)
+					}
+					if page.Next == nil {
+						break
+					}
+					query.After = page.Next
+				}
+
+				if len(got) != len(tt.want) {
+					t.Fatalf("got %d products, want %d", len(got), len(tt.want))
+				}
+				for i := range tt.want {
+					if !sameExpiry(got[i], tt.want[i]) {
+						t.Errorf("product #%d expires at %v, want %v", i, got[i], tt.want[i])
+					}
+				}
+			})
+		}
+	}
+}
@@ -3,9 +3,13 @@ package repository
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
 )
 
 var (
@@ -13,105 +17,223 @@ var (
 	ErrPreferenceNotFound     = errors.New("preference not found")
 	ErrPreferenceUpdateFailed = errors.New("preference update failed")
 	ErrProductAlreadyExists   = errors.New("product already exists")
+	ErrPreferenceExists       = errors.New("preference already exists")
 	ErrQueryFailed            = errors.New("query failed")
 	ErrNoRows                 = errors.New("no rows in result")
 	ErrAddUserFailed          = errors.New("add user failed")
+	ErrBatchAborted           = errors.New("batch aborted")
+	ErrTransactionFailed      = errors.New("transaction failed")
 )
 
 var _ Repository = (*repository)(nil)
 
 type Repository interface {
-	// GetProducts - получить список продуктов пользователя
-	GetProducts(ctx context.Context, userId string) ([]string, error)
-	// GetPreference - получить предпочтения пользователя
-	GetPreference(ctx context.Context, userId string) (string, error)
-	// UpdatePreference - обновить предпочтения пользователя
-	UpdatePreference(ctx context.Context, userId string, preferenceName string) (error)
-	// RemovePreference - удалить предпочтения пользователя
+	// GetProducts - получить страницу продуктов пользователя
+	GetProducts(ctx context.Context, userId string, query ProductQuery) (ProductPage, error)
+	// GetPreference - получить набор предпочтений пользователя
+	GetPreference(ctx context.Context, userId string) (entity.Preferences, error)
+	// UpdatePreference - заменить набор предпочтений пользователя целиком
+	UpdatePreference(ctx context.Context, userId string, preferences entity.Preferences) (error)
+	// RemovePreference - удалить все предпочтения пользователя
 	RemovePreference(ctx context.Context, userId string) (error)
-	// AddProduct - добавить продукт пользователю
-	AddProduct(ctx context.Context, userId string, productName string) (error)
-	// RemoveProduct - удалить продукт у пользователя
-	RemoveProduct(ctx context.Context, userId string, productName string) (error)
+	// AddPreferenceEntry - добавить одно значение предпочтения
+	AddPreferenceEntry(ctx context.Context, userId string, entry entity.PreferenceEntry) (error)
+	// RemovePreferenceEntry - удалить одно значение предпочтения
+	RemovePreferenceEntry(ctx context.Context, userId string, entry entity.PreferenceEntry) (error)
+	// AddProduct - добавить продукт пользователю, возвращает сохраненную запись
+	AddProduct(ctx context.Context, userId string, product entity.Product) (entity.Product, error)
+	// RemoveProduct - удалить продукт у пользователя по идентификатору
+	RemoveProduct(ctx context.Context, userId string, productId string) (error)
+	// RemoveProductByName - удалить все партии продукта у пользователя по названию,
+	// возвращает количество удаленных записей
+	RemoveProductByName(ctx context.Context, userId string, productName string) (int64, error)
+	// AddProducts - добавить несколько продуктов в одной транзакции
+	AddProducts(ctx context.Context, userId string, products []entity.Product, atomic bool) ([]BatchResult, error)
+	// RemoveProducts - удалить несколько продуктов по идентификаторам в одной транзакции
+	RemoveProducts(ctx context.Context, userId string, productIds []string, atomic bool) ([]BatchResult, error)
+	// WithTx - выполнить fn в одной транзакции: операции переданного в fn репозитория
+	// применяются вместе или не применяются вовсе. Вложенный вызов создает точку сохранения.
+	WithTx(ctx context.Context, fn func(Repository) error, opts ...TxOption) error
+	// AddEvents - записать доменные события в outbox. Чтобы событие не разошлось
+	// с изменением, вызывается в той же транзакции WithTx.
+	AddEvents(ctx context.Context, events ...entity.Event) error
+	// WatchChanges - подписаться на изменения продуктов и предпочтений, в том числе сделанные другими репликами.
+	// subscribed вызывается после подписки, changed - на каждое изменение.
+	// Блокируется до отмены ctx, разрыва соединения или ошибки subscribed.
+	WatchChanges(ctx context.Context, subscribed func() error, changed func(Change)) error
 }
 
-type repository struct {
-	db *pgxpool.Pool
+// productColumns - список колонок user_products в порядке сканирования scanProduct
+const productColumns = `id, product_name, catalog_product_id, category, quantity, unit, expires_at, created_at, updated_at`
+
+// rowScanner - общий интерфейс для pgx.Row и pgx.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
 }
 
-func New(db *pgxpool.Pool) *repository {
-	return &repository{
-		db: db,
+// scanProduct - сканирует строку user_products в entity.Product
+func scanProduct(row rowScanner) (entity.Product, error) {
+	var product entity.Product
+	var catalogProductId *string
+	err := row.Scan(
+		&product.ID,
+		&product.Name,
+		&catalogProductId,
+		&product.Category,
+		&product.Quantity,
+		&product.Unit,
+		&product.ExpiresAt,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	)
+	if catalogProductId != nil {
+		product.CatalogProductID = *catalogProductId
 	}
+	return product, err
+}
+
+// querier - общий интерфейс пула соединений и транзакции
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
-func (r *repository) GetProducts(ctx context.Context, userId string) ([]string, error) {
-	query := `SELECT product_name FROM user_products WHERE user_id = $1`
-	rows, err := r.db.Query(ctx, query, userId)
+
+// insertProduct - добавляет продукт пользователю через пул или транзакцию
+func insertProduct(ctx context.Context, q querier, userId string, product entity.Product) (entity.Product, error) {
+	query := `INSERT INTO user_products (user_id, product_name, catalog_product_id, category, quantity, unit, expires_at)
+		VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5, $6, $7)
+		RETURNING ` + productColumns
+	added, err := scanProduct(q.QueryRow(ctx, query,
+		userId, product.Name, product.CatalogProductID, product.Category, product.Quantity, product.Unit, product.ExpiresAt))
 	if err != nil {
-		return nil, ErrQueryFailed
-	}
-	defer rows.Close()
-	var products []string
-	for rows.Next() {
-		var product string
-		if err := rows.Scan(&product); err != nil {
-			return nil, ErrNoRows
-		}
-		products = append(products, product)
+		return entity.Product{}, productInsertError(pgError(err, ErrQueryFailed))
 	}
-	return products, nil
+	return added, nil
 }
 
-func (r *repository) GetPreference(ctx context.Context, userId string) (string, error) {
-	query := `SELECT preference_name FROM user_preference WHERE user_id = $1`
-	var preference string
-	if err := r.db.QueryRow(ctx, query, userId).Scan(&preference); err != nil {
-		return "", ErrPreferenceNotFound
+// productInsertError - уточняет доменную ошибку добавления продукта по классу ошибки хранилища
+func productInsertError(err *Error) *Error {
+	switch err.Class {
+	case ErrUniqueViolation:
+		err.Kind = ErrProductAlreadyExists
+	case ErrForeignKeyViolation:
+		// Запись каталога удалили после того, как продукт был с ней сопоставлен
+		err.Kind = ErrCatalogProductNotFound
 	}
-	return preference, nil
+	return err
 }
 
-func (r *repository) UpdatePreference(ctx context.Context, userId string, preferenceName string) (error) {
-	query := `SELECT user_id FROM user_preference WHERE user_id = $1`
-	err := r.db.QueryRow(ctx, query, userId)
+// deleteProduct - удаляет продукт пользователя по идентификатору, возвращает число удаленных строк
+func deleteProduct(ctx context.Context, q querier, userId string, productId string) (int64, error) {
+	query := `DELETE FROM user_products WHERE user_id = $1 AND id = $2`
+	tag, err := q.Exec(ctx, query, userId, productId)
 	if err != nil {
-		query = `INSERT INTO user_preference (user_id, preference_name) VALUES ($1, $2)`
-		if _, err := r.db.Exec(ctx, query, userId, preferenceName); err != nil {
-			return ErrAddUserFailed
+		return 0, pgError(err, ErrQueryFailed)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// dbtx - пул соединений или транзакция, в которой выполняются запросы репозитория;
+// Begin внутри транзакции создает точку сохранения
+type dbtx interface {
+	querier
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type repository struct {
+	pool scopedPool
+	// db - пул или, для репозитория внутри WithTx, транзакция
+	db      dbtx
+	log     *slog.Logger
+	retries retryPolicy
+}
+
+func New(db *pgxpool.Pool, log *slog.Logger) *repository {
+	pool := scopedPool{Pool: db}
+	return &repository{
+		pool:    pool,
+		db:      pool,
+		log:     log,
+		retries: newRetryPolicy(log),
+	}
+}
+func (r *repository) GetProducts(ctx context.Context, userId string, productQuery ProductQuery) (ProductPage, error) {
+	defer metrics.ObserveQuery("get_products")()
+	query, args := productQuery.build(userId)
+	page, err := retryResult(ctx, r.retries, func() (ProductPage, error) {
+		rows, err := r.db.Query(ctx, query, args...)
+		if err != nil {
+			return ProductPage{}, pgError(err, ErrQueryFailed)
+		}
+		defer rows.Close()
+		var page ProductPage
+		for rows.Next() {
+			product, err := scanProduct(rows)
+			if err != nil {
+				return ProductPage{}, pgError(err, ErrNoRows)
+			}
+			page.Products = append(page.Products, product)
+		}
+		if err := rows.Err(); err != nil {
+			return ProductPage{}, pgError(err, ErrQueryFailed)
 		}
-		return nil
+		return page, nil
+	})
+	if err != nil {
+		return ProductPage{}, err
 	}
-	query = `UPDATE user_preference SET preference_name = $1 WHERE user_id = $2`
-	if _, err := r.db.Exec(ctx, query, preferenceName, userId); err != nil {
-		return ErrPreferenceUpdateFailed
+	if len(page.Products) > productQuery.Limit {
+		page.Products = page.Products[:productQuery.Limit]
+		next := CursorFromProduct(page.Products[len(page.Products)-1])
+		page.Next = &next
 	}
-	log.Println("Preference updated successfully")
-	return nil
+	return page, nil
 }
 
-func (r *repository) RemovePreference(ctx context.Context, userId string) (error) {
-	query := `DELETE FROM user_preference WHERE user_id = $1`
-	if _, err := r.db.Exec(ctx, query, userId); err != nil {
-		return ErrPreferenceNotFound
+func (r *repository) AddProduct(ctx context.Context, userId string, product entity.Product) (entity.Product, error) {
+	defer metrics.ObserveQuery("add_product")()
+	added, err := retryResult(ctx, r.retries, func() (entity.Product, error) {
+		return insertProduct(ctx, r.db, userId, product)
+	})
+	if err != nil {
+		return entity.Product{}, err
 	}
-	log.Println("Preference removed successfully")
-	return nil
+	r.log.DebugContext(ctx, "Product added successfully")
+	return added, nil
 }
 
-func (r *repository) AddProduct(ctx context.Context, userId string, productName string) (error) {
-	query := `INSERT INTO user_products (user_id, product_name) VALUES ($1, $2)`
-	if _, err := r.db.Exec(ctx, query, userId, productName); err != nil {
-		return ErrProductAlreadyExists
+func (r *repository) RemoveProduct(ctx context.Context, userId string, productId string) (error) {
+	defer metrics.ObserveQuery("remove_product")()
+	removed, err := retryResult(ctx, r.retries, func() (int64, error) {
+		return deleteProduct(ctx, r.db, userId, productId)
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrProductNotFound
 	}
-	log.Println("Product added successfully")
+	r.log.DebugContext(ctx, "Product removed successfully")
 	return nil
 }
 
-func (r *repository) RemoveProduct(ctx context.Context, userId string, productName string) (error) {
+func (r *repository) RemoveProductByName(ctx context.Context, userId string, productName string) (int64, error) {
+	defer metrics.ObserveQuery("remove_product_by_name")()
 	query := `DELETE FROM user_products WHERE user_id = $1 AND product_name = $2`
-	if _, err := r.db.Exec(ctx, query, userId, productName); err != nil {
-		return ErrProductNotFound
+	removed, err := retryResult(ctx, r.retries, func() (int64, error) {
+		tag, err := r.db.Exec(ctx, query, userId, productName)
+		if err != nil {
+			return 0, pgError(err, ErrQueryFailed)
+		}
+		return tag.RowsAffected(), nil
+	})
+	if err != nil {
+		return 0, err
 	}
-	log.Println("Product removed successfully")
-	return nil
+	if removed == 0 {
+		return 0, ErrProductNotFound
+	}
+	r.log.DebugContext(ctx, "Product removed successfully", "removed", removed)
+	return removed, nil
 }
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
+)
+
+// revocationChannel - канал LISTEN/NOTIFY, в который триггеры пишут новые отзывы токенов
+const revocationChannel = "token_revocations"
+
+var _ RevocationRepository = (*revocationRepository)(nil)
+
+type RevocationRepository interface {
+	// RevokeToken - отозвать токен по jti до истечения его срока действия
+	RevokeToken(ctx context.Context, token entity.RevokedToken) error
+	// RevokeUserTokens - отозвать все токены пользователя, выпущенные не позже RevokedBefore.
+	// Граница не сдвигается назад, возвращается действующее значение.
+	RevokeUserTokens(ctx context.Context, revocation entity.UserTokensRevocation) (entity.UserTokensRevocation, error)
+	// ListRevocations - получить все действующие отзывы
+	ListRevocations(ctx context.Context) (entity.Revocations, error)
+	// WatchRevocations - подписаться на новые отзывы, в том числе сделанные другими репликами.
+	// subscribed вызывается после подписки, changed - на каждый отзыв.
+	// Блокируется до отмены ctx, разрыва соединения или ошибки subscribed.
+	WatchRevocations(ctx context.Context, subscribed func() error, changed func(entity.Revocations)) error
+}
+
+type revocationRepository struct {
+	db      *pgxpool.Pool
+	log     *slog.Logger
+	retries retryPolicy
+}
+
+func NewRevocation(db *pgxpool.Pool, log *slog.Logger) *revocationRepository {
+	return &revocationRepository{
+		db:      db,
+		log:     log,
+		retries: newRetryPolicy(log),
+	}
+}
+
+func (r *revocationRepository) RevokeToken(ctx context.Context, token entity.RevokedToken) error {
+	defer metrics.ObserveQuery("revoke_token")()
+	query := `INSERT INTO revoked_tokens (jti, user_id, expires_at) VALUES ($1, NULLIF($2, '')::uuid, $3)
+		ON CONFLICT (jti) DO UPDATE SET expires_at = GREATEST(revoked_tokens.expires_at, EXCLUDED.expires_at)`
+	err := retry(ctx, r.retries, func() error {
+		if _, err := r.db.Exec(ctx, query, token.TokenID, token.UserID, token.ExpiresAt); err != nil {
+			return pgError(err, ErrQueryFailed)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// Отзывы истекших токенов больше ни на что не влияют
+	if _, err := r.db.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at < CURRENT_TIMESTAMP`); err != nil {
+		r.log.WarnContext(ctx, "Failed to purge expired token revocations", "error", err)
+	}
+	r.log.DebugContext(ctx, "Token revoked successfully")
+	return nil
+}
+
+func (r *revocationRepository) RevokeUserTokens(ctx context.Context, revocation entity.UserTokensRevocation) (entity.UserTokensRevocation, error) {
+	defer metrics.ObserveQuery("revoke_user_tokens")()
+	query := `INSERT INTO user_token_revocations (user_id, revoked_before) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET revoked_before = GREATEST(user_token_revocations.revoked_before, EXCLUDED.revoked_before),
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING revoked_before`
+	revokedBefore, err := retryResult(ctx, r.retries, func() (time.Time, error) {
+		var revokedBefore time.Time
+		if err := r.db.QueryRow(ctx, query, revocation.UserID, revocation.RevokedBefore).Scan(&revokedBefore); err != nil {
+			return time.Time{}, pgError(err, ErrQueryFailed)
+		}
+		return revokedBefore, nil
+	})
+	if err != nil {
+		return entity.UserTokensRevocation{}, err
+	}
+	revocation.RevokedBefore = revokedBefore
+	r.log.DebugContext(ctx, "User tokens revoked successfully")
+	return revocation, nil
+}
+
+func (r *revocationRepository) ListRevocations(ctx context.Context) (entity.Revocations, error) {
+	defer metrics.ObserveQuery("list_revocations")()
+	return retryResult(ctx, r.retries, func() (entity.Revocations, error) {
+		return r.listRevocations(ctx)
+	})
+}
+
+// listRevocations - одна попытка ListRevocations
+func (r *revocationRepository) listRevocations(ctx context.Context) (entity.Revocations, error) {
+	var revocations entity.Revocations
+
+	query := `SELECT jti, COALESCE(user_id::text, ''), expires_at FROM revoked_tokens WHERE expires_at >= CURRENT_TIMESTAMP`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return entity.Revocations{}, pgError(err, ErrQueryFailed)
+	}
+	for rows.Next() {
+		var token entity.RevokedToken
+		if err := rows.Scan(&token.TokenID, &token.UserID, &token.ExpiresAt); err != nil {
+			rows.Close()
+			return entity.Revocations{}, pgError(err, ErrNoRows)
+		}
+		revocations.Tokens = append(revocations.Tokens, token)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return entity.Revocations{}, pgError(err, ErrQueryFailed)
+	}
+
+	rows, err = r.db.Query(ctx, `SELECT user_id::text, revoked_before FROM user_token_revocations`)
+	if err != nil {
+		return entity.Revocations{}, pgError(err, ErrQueryFailed)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var revocation entity.UserTokensRevocation
+		if err := rows.Scan(&revocation.UserID, &revocation.RevokedBefore); err != nil {
+			return entity.Revocations{}, pgError(err, ErrNoRows)
+		}
+		revocations.Users = append(revocations.Users, revocation)
+	}
+	if err := rows.Err(); err != nil {
+		return entity.Revocations{}, pgError(err, ErrQueryFailed)
+	}
+
+	return revocations, nil
+}
+
+func (r *revocationRepository) WatchRevocations(ctx context.Context, subscribed func() error, changed func(entity.Revocations)) error {
+	// LISTEN держит соединение все время работы, поэтому оно не берется из пула
+	conn, err := pgx.ConnectConfig(ctx, r.db.Config().ConnConfig.Copy())
+	if err != nil {
+		return fmt.Errorf("failed to connect for %s notifications: %w", revocationChannel, err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+revocationChannel); err != nil {
+		return fmt.Errorf("failed to listen %s: %w", revocationChannel, err)
+	}
+	if err := subscribed(); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		revocations, err := decodeRevocationNotification(notification.Payload)
+		if err != nil {
+			r.log.WarnContext(ctx, "Skipping malformed notification", "channel", revocationChannel, "error", err)
+			continue
+		}
+		changed(revocations)
+	}
+}
+
+// revocationNotification - payload уведомления, который формирует триггер notify_token_revocation
+type revocationNotification struct {
+	TokenID       string    `json:"jti"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	UserID        string    `json:"user_id"`
+	RevokedBefore time.Time `json:"revoked_before"`
+}
+
+// decodeRevocationNotification - разбирает payload уведомления в отзыв токена или пользователя
+func decodeRevocationNotification(payload string) (entity.Revocations, error) {
+	var notification revocationNotification
+	if err := json.Unmarshal([]byte(payload), &notification); err != nil {
+		return entity.Revocations{}, err
+	}
+	var revocations entity.Revocations
+	switch {
+	case notification.TokenID != "":
+		revocations.Tokens = append(revocations.Tokens, entity.RevokedToken{
+			TokenID:   notification.TokenID,
+			ExpiresAt: notification.ExpiresAt,
+		})
+	case notification.UserID != "":
+		revocations.Users = append(revocations.Users, entity.UserTokensRevocation{
+			UserID:        notification.UserID,
+			RevokedBefore: notification.RevokedBefore,
+		})
+	default:
+		return entity.Revocations{}, fmt.Errorf("empty revocation payload %q", payload)
+	}
+	return revocations, nil
+}
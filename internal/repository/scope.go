@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// userScopeKey - ключ контекста с пользователем, чьи строки видны запросам Postgres
+type userScopeKey struct{}
+
+// setUserScopeQuery - ставит переменную app.user_id, по которой политики RLS отбирают строки
+// user_products, user_preferences и user_preference_entries, до конца текущей транзакции
+const setUserScopeQuery = `SELECT set_config('app.user_id', $1, true)`
+
+// WithUserScope - возвращает контекст, запросы Postgres в котором видят только строки пользователя userId.
+// Запросы без пользователя в контексте не видят ни одной строки данных пользователей.
+func WithUserScope(ctx context.Context, userId string) context.Context {
+	return context.WithValue(ctx, userScopeKey{}, userId)
+}
+
+// userScope - пользователь из контекста запроса
+func userScope(ctx context.Context) (string, bool) {
+	userId, ok := ctx.Value(userScopeKey{}).(string)
+	return userId, ok
+}
+
+// scopedPool - пул, который ставит app.user_id из контекста в каждой транзакции.
+// Отдельный запрос отправляется вместе с set_config одним пакетом: запросы пакета
+// выполняются в одной неявной транзакции, поэтому переменная не переживает запрос.
+type scopedPool struct {
+	*pgxpool.Pool
+}
+
+func (p scopedPool) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	userId, ok := userScope(ctx)
+	if !ok {
+		return p.Pool.Exec(ctx, sql, args...)
+	}
+	results := p.SendBatch(ctx, scopedBatch(userId, sql, args))
+	tag, err := execScoped(results)
+	if closeErr := results.Close(); err == nil {
+		err = closeErr
+	}
+	return tag, err
+}
+
+func (p scopedPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	userId, ok := userScope(ctx)
+	if !ok {
+		return p.Pool.Query(ctx, sql, args...)
+	}
+	results := p.SendBatch(ctx, scopedBatch(userId, sql, args))
+	if _, err := results.Exec(); err != nil {
+		results.Close()
+		return nil, err
+	}
+	rows, err := results.Query()
+	if err != nil {
+		results.Close()
+		return nil, err
+	}
+	return &scopedRows{Rows: rows, results: results}, nil
+}
+
+func (p scopedPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	rows, err := p.Query(ctx, sql, args...)
+	if err != nil {
+		return errRow{err: err}
+	}
+	return scopedRow{rows: rows}
+}
+
+func (p scopedPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	return p.BeginTx(ctx, pgx.TxOptions{})
+}
+
+// BeginTx - начинает транзакцию и ставит в ней app.user_id из контекста
+func (p scopedPool) BeginTx(ctx context.Context, options pgx.TxOptions) (pgx.Tx, error) {
+	tx, err := p.Pool.BeginTx(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	userId, ok := userScope(ctx)
+	if !ok {
+		return tx, nil
+	}
+	if _, err := tx.Exec(ctx, setUserScopeQuery, userId); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+	return tx, nil
+}
+
+// scopedBatch - пакет из set_config и запроса sql
+func scopedBatch(userId string, sql string, args []any) *pgx.Batch {
+	batch := &pgx.Batch{}
+	batch.Queue(setUserScopeQuery, userId)
+	batch.Queue(sql, args...)
+	return batch
+}
+
+// execScoped - читает результаты пакета scopedBatch, возвращает результат запроса
+func execScoped(results pgx.BatchResults) (pgconn.CommandTag, error) {
+	if _, err := results.Exec(); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return results.Exec()
+}
+
+// scopedRows - строки запроса из пакета, Close закрывает и пакет
+type scopedRows struct {
+	pgx.Rows
+	results pgx.BatchResults
+}
+
+func (r *scopedRows) Close() {
+	r.Rows.Close()
+	r.results.Close()
+}
+
+// scopedRow - аналог pgx.Row поверх scopedRows
+type scopedRow struct {
+	rows pgx.Rows
+}
+
+func (r scopedRow) Scan(dest ...any) error {
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	if err := r.rows.Scan(dest...); err != nil {
+		return err
+	}
+	r.rows.Close()
+	return r.rows.Err()
+}
+
+// errRow - pgx.Row, который возвращает ошибку отправки запроса
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...any) error {
+	return r.err
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"user-service/internal/adapter/postgres"
+)
+
+// newTestScopedPool - scopedPool с единственным соединением, чтобы каждый следующий запрос
+// получал то же соединение, что и предыдущий
+func newTestScopedPool(t *testing.T) scopedPool {
+	t.Helper()
+	if err := postgres.CheckRowLevelSecurity(context.Background(), newTestPostgresPool(t)); err != nil {
+		t.Skipf("row level security is not enforced for the test role: %v", err)
+	}
+	poolConfig, err := pgxpool.ParseConfig(os.Getenv(testPostgresEnv))
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", testPostgresEnv, err)
+	}
+	poolConfig.MaxConns = 1
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		t.Fatalf("failed to connect to Postgres: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return scopedPool{Pool: pool}
+}
+
+// addScopedUser - пользователь с одним продуктом productName, удаляется после теста
+func addScopedUser(t *testing.T, db scopedPool, productName string) (context.Context, string) {
+	t.Helper()
+	ctx, userId := testUser()
+	_, err := db.Exec(ctx, `INSERT INTO user_products (user_id, product_name) VALUES ($1, $2)`, userId, productName)
+	if err != nil {
+		t.Fatalf("failed to add product: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := db.Exec(ctx, `DELETE FROM users WHERE id = $1`, userId); err != nil {
+			t.Errorf("failed to delete user: %v", err)
+		}
+	})
+	return ctx, userId
+}
+
+func TestScopedPoolIsolatesUsers(t *testing.T) {
+	db := newTestScopedPool(t)
+	productName := "scoped-" + t.Name()
+	ctx, userId := addScopedUser(t, db, productName)
+	addScopedUser(t, db, productName)
+
+	countProducts := func(ctx context.Context) int {
+		t.Helper()
+		var count int
+		err := db.QueryRow(ctx, `SELECT count(*) FROM user_products WHERE product_name = $1`, productName).Scan(&count)
+		if err != nil {
+			t.Fatalf("QueryRow: %v", err)
+		}
+		return count
+	}
+
+	t.Run("query", func(t *testing.T) {
+		rows, err := db.Query(ctx, `SELECT user_id::text FROM user_products WHERE product_name = $1`, productName)
+		if err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+		defer rows.Close()
+		var owners []string
+		for rows.Next() {
+			var owner string
+			if err := rows.Scan(&owner); err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			owners = append(owners, owner)
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatalf("rows: %v", err)
+		}
+		if len(owners) != 1 || owners[0] != userId {
+			t.Errorf("Query returned products of %v, want only %s", owners, userId)
+		}
+	})
+
+	t.Run("query row", func(t *testing.T) {
+		if got := countProducts(ctx); got != 1 {
+			t.Errorf("QueryRow counted %d products, want 1", got)
+		}
+	})
+
+	t.Run("exec", func(t *testing.T) {
+		tag, err := db.Exec(ctx, `UPDATE user_products SET quantity = 2 WHERE product_name = $1`, productName)
+		if err != nil {
+			t.Fatalf("Exec: %v", err)
+		}
+		if tag.RowsAffected() != 1 {
+			t.Errorf("Exec updated %d products, want 1", tag.RowsAffected())
+		}
+	})
+
+	t.Run("other user rows cannot be written", func(t *testing.T) {
+		_, other := testUser()
+		_, err := db.Exec(ctx, `INSERT INTO user_products (user_id, product_name) VALUES ($1, $2)`, other, productName)
+		if err == nil {
+			t.Error("Exec inserted a product of another user")
+		}
+	})
+
+	t.Run("unscoped context", func(t *testing.T) {
+		if got := countProducts(context.Background()); got != 0 {
+			t.Errorf("QueryRow without user counted %d products, want 0", got)
+		}
+		tag, err := db.Exec(context.Background(), `DELETE FROM user_products WHERE product_name = $1`, productName)
+		if err != nil {
+			t.Fatalf("Exec: %v", err)
+		}
+		if tag.RowsAffected() != 0 {
+			t.Errorf("Exec without user deleted %d products, want 0", tag.RowsAffected())
+		}
+	})
+}
+
+func TestScopedPoolDoesNotLeakUserToNextBorrower(t *testing.T) {
+	db := newTestScopedPool(t)
+	productName := "scoped-" + t.Name()
+	ctx, _ := addScopedUser(t, db, productName)
+
+	currentUser := func() string {
+		t.Helper()
+		var userId string
+		err := db.QueryRow(context.Background(), `SELECT COALESCE(current_setting('app.user_id', true), '')`).Scan(&userId)
+		if err != nil {
+			t.Fatalf("QueryRow: %v", err)
+		}
+		return userId
+	}
+
+	borrowers := map[string]func() error{
+		"exec": func() error {
+			_, err := db.Exec(ctx, `UPDATE user_products SET quantity = 3 WHERE product_name = $1`, productName)
+			return err
+		},
+		"query": func() error {
+			rows, err := db.Query(ctx, `SELECT id FROM user_products WHERE product_name = $1`, productName)
+			if err != nil {
+				return err
+			}
+			rows.Close()
+			return rows.Err()
+		},
+		"query row": func() error {
+			var count int
+			return db.QueryRow(ctx, `SELECT count(*) FROM user_products WHERE product_name = $1`, productName).Scan(&count)
+		},
+		"transaction": func() error {
+			tx, err := db.BeginTx(ctx, pgx.TxOptions{})
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, `UPDATE user_products SET quantity = 4 WHERE product_name = $1`, productName); err != nil {
+				tx.Rollback(ctx)
+				return err
+			}
+			return tx.Commit(ctx)
+		},
+	}
+	for name, borrow := range borrowers {
+		t.Run(name, func(t *testing.T) {
+			if err := borrow(); err != nil {
+				t.Fatalf("scoped %s: %v", name, err)
+			}
+			if got := currentUser(); got != "" {
+				t.Errorf("next borrower of the connection sees app.user_id = %q, want empty", got)
+			}
+			var count int
+			err := db.QueryRow(context.Background(), `SELECT count(*) FROM user_products WHERE product_name = $1`, productName).Scan(&count)
+			if err != nil {
+				t.Fatalf("QueryRow: %v", err)
+			}
+			if count != 0 {
+				t.Errorf("next borrower of the connection sees %d products, want 0", count)
+			}
+		})
+	}
+}
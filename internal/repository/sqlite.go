@@ -0,0 +1,451 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	sqliteadapter "user-service/internal/adapter/sqlite"
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
+)
+
+var (
+	_ Repository           = (*sqliteRepository)(nil)
+	_ CatalogRepository    = (*sqliteRepository)(nil)
+	_ RevocationRepository = (*sqliteRepository)(nil)
+)
+
+// sqliteRepository - хранилище в файле SQLite для запуска без Postgres.
+// Реализует Repository, CatalogRepository и RevocationRepository с той же семантикой
+// и теми же ошибками, что и Postgres. Схема создается миграциями из migrations/sqlite.
+type sqliteRepository struct {
+	base *sql.DB
+	// db - база или, для репозитория внутри WithTx, транзакция
+	db      sqliteConn
+	log     *slog.Logger
+	retries retryPolicy
+}
+
+// NewSQLite - создает хранилище поверх открытой базы SQLite
+func NewSQLite(db *sql.DB, log *slog.Logger) *sqliteRepository {
+	return &sqliteRepository{
+		base:    db,
+		db:      sqliteDB{DB: db},
+		log:     log,
+		retries: newRetryPolicy(log),
+	}
+}
+
+// Ping - проверяет, что файл базы данных доступен
+func (r *sqliteRepository) Ping(ctx context.Context) error {
+	return r.base.PingContext(ctx)
+}
+
+// sqliteQuerier - общий интерфейс базы и транзакции database/sql
+type sqliteQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sqliteConn - база или транзакция, в которой выполняются запросы репозитория;
+// аналог dbtx для Postgres
+type sqliteConn interface {
+	sqliteQuerier
+	// begin - начинает транзакцию, а внутри транзакции - точку сохранения
+	begin(ctx context.Context) (*sqliteTx, error)
+}
+
+// sqliteDB - база SQLite, begin начинает транзакцию
+type sqliteDB struct {
+	*sql.DB
+}
+
+func (db sqliteDB) begin(ctx context.Context) (*sqliteTx, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteTx{Tx: tx, ctx: ctx}, nil
+}
+
+// sqliteTx - транзакция SQLite или точка сохранения внутри нее
+type sqliteTx struct {
+	*sql.Tx
+	ctx context.Context
+	// savepoint - имя точки сохранения, пустое для самой транзакции
+	savepoint string
+	depth     int
+	done      bool
+}
+
+func (tx *sqliteTx) begin(ctx context.Context) (*sqliteTx, error) {
+	savepoint := fmt.Sprintf("savepoint_%d", tx.depth+1)
+	if _, err := tx.ExecContext(ctx, `SAVEPOINT `+savepoint); err != nil {
+		return nil, err
+	}
+	return &sqliteTx{Tx: tx.Tx, ctx: ctx, savepoint: savepoint, depth: tx.depth + 1}, nil
+}
+
+// commit - фиксирует транзакцию или освобождает точку сохранения
+func (tx *sqliteTx) commit() error {
+	if tx.savepoint == "" {
+		return tx.Commit()
+	}
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+	_, err := tx.ExecContext(tx.ctx, `RELEASE `+tx.savepoint)
+	return err
+}
+
+// rollback - откатывает транзакцию или изменения после точки сохранения;
+// после commit ничего не делает, поэтому его можно вызывать в defer
+func (tx *sqliteTx) rollback() error {
+	if tx.savepoint == "" {
+		return tx.Rollback()
+	}
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+	if _, err := tx.ExecContext(tx.ctx, `ROLLBACK TO `+tx.savepoint); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(tx.ctx, `RELEASE `+tx.savepoint)
+	return err
+}
+
+// WatchChanges - файл SQLite принадлежит одному процессу, а его изменения
+// кэш сбрасывает сам, поэтому после подписки остается только ждать отмены ctx
+func (r *sqliteRepository) WatchChanges(ctx context.Context, subscribed func() error, changed func(Change)) error {
+	if err := subscribed(); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// WithTx - выполняет fn в транзакции SQLite. Транзакции SQLite всегда сериализуемые,
+// поэтому уровень изоляции из opts не учитывается. Повторы и точки сохранения такие же,
+// как у WithTx для Postgres. Пока транзакция открыта, единственное соединение с базой занято:
+// внутри fn нужно использовать только переданный репозиторий.
+func (r *sqliteRepository) WithTx(ctx context.Context, fn func(Repository) error, opts ...TxOption) error {
+	if _, ok := r.db.(*sqliteTx); ok {
+		return r.withTx(ctx, fn)
+	}
+	return retry(ctx, r.retries, func() error {
+		return r.withTx(ctx, fn)
+	})
+}
+
+// withTx - одна попытка транзакции или точки сохранения WithTx
+func (r *sqliteRepository) withTx(ctx context.Context, fn func(Repository) error) error {
+	tx, err := r.db.begin(ctx)
+	if err != nil {
+		return sqliteError(err, ErrTransactionFailed)
+	}
+	defer tx.rollback()
+
+	if err := fn(r.inTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.commit(); err != nil {
+		return sqliteError(err, ErrTransactionFailed)
+	}
+	return nil
+}
+
+// inTx - репозиторий, выполняющий запросы в транзакции tx без отдельных повторов
+func (r *sqliteRepository) inTx(tx *sqliteTx) *sqliteRepository {
+	return &sqliteRepository{
+		base:    r.base,
+		db:      tx,
+		log:     r.log,
+		retries: retryPolicy{log: r.log, attempts: 1},
+	}
+}
+
+// sqliteNow - текущее время с точностью TIMESTAMP в Postgres.
+// SQLite хранит время строкой, поэтому все значения пишутся в UTC и сравниваются как строки.
+func sqliteNow() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// sqliteTime - значение времени для записи в SQLite
+func sqliteTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	value := t.UTC().Truncate(time.Microsecond)
+	return &value
+}
+
+// sqliteInfinity - аналог 'infinity'::timestamp: позже любого срока годности
+const sqliteInfinity = `'9999-12-31 23:59:59.999999+00:00'`
+
+// sqliteInfinityTime - значение sqliteInfinity для параметров запроса
+var sqliteInfinityTime = time.Date(9999, time.December, 31, 23, 59, 59, 999999000, time.UTC)
+
+// sqliteNegativeInfinity - аналог '-infinity'::timestamp: раньше любого срока годности
+const sqliteNegativeInfinity = `'0001-01-01 00:00:00+00:00'`
+
+// sqliteNegativeInfinityTime - значение sqliteNegativeInfinity для параметров запроса
+var sqliteNegativeInfinityTime = time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// sqliteSortExpression - аналог sortExpression для SQLite
+func (q ProductQuery) sqliteSortExpression() (string, any) {
+	var cursor ProductCursor
+	if q.After != nil {
+		cursor = *q.After
+	}
+	switch q.SortBy {
+	case SortByName:
+		return `product_name`, cursor.Name
+	case SortByExpiresAt:
+		infinity, infinityTime := sqliteInfinity, sqliteInfinityTime
+		if q.Descending {
+			infinity, infinityTime = sqliteNegativeInfinity, sqliteNegativeInfinityTime
+		}
+		expiresAt := infinityTime
+		if cursor.ExpiresAt != nil {
+			expiresAt = *sqliteTime(cursor.ExpiresAt)
+		}
+		return `COALESCE(expires_at, ` + infinity + `)`, expiresAt
+	default:
+		return `created_at`, *sqliteTime(&cursor.CreatedAt)
+	}
+}
+
+// buildSQLite - аналог ProductQuery.build для SQLite
+func (q ProductQuery) buildSQLite(userId string) (string, []any) {
+	args := []any{userId}
+	conditions := []string{`user_id = ?`}
+	if q.Filter.NamePrefix != "" {
+		conditions = append(conditions, `instr(`+sqliteadapter.LowerFunction+`(product_name), ?) = 1`)
+		args = append(args, strings.ToLower(q.Filter.NamePrefix))
+	}
+	if q.Filter.ExpiringBefore != nil {
+		conditions = append(conditions, `expires_at < ?`)
+		args = append(args, sqliteTime(q.Filter.ExpiringBefore))
+	}
+	if q.Filter.Category != "" {
+		conditions = append(conditions, `category = ?`)
+		args = append(args, q.Filter.Category)
+	}
+
+	sortExpr, cursorValue := q.sqliteSortExpression()
+	direction, comparison := "ASC", ">"
+	if q.Descending {
+		direction, comparison = "DESC", "<"
+	}
+	if q.After != nil {
+		conditions = append(conditions, fmt.Sprintf(`(%s, id) %s (?, ?)`, sortExpr, comparison))
+		args = append(args, cursorValue, q.After.ID)
+	}
+
+	query := `SELECT ` + productColumns + ` FROM user_products` +
+		` WHERE ` + strings.Join(conditions, ` AND `) +
+		fmt.Sprintf(` ORDER BY %s %s, id %s LIMIT ?`, sortExpr, direction, direction)
+	args = append(args, q.Limit+1)
+	return query, args
+}
+
+// insertSQLiteProduct - аналог insertProduct для SQLite
+func insertSQLiteProduct(ctx context.Context, q sqliteQuerier, userId string, product entity.Product) (entity.Product, error) {
+	now := sqliteNow()
+	query := `INSERT INTO user_products (id, user_id, product_name, catalog_product_id, category, quantity, unit, expires_at, created_at, updated_at)
+		VALUES (?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?)
+		RETURNING ` + productColumns
+	added, err := scanProduct(q.QueryRowContext(ctx, query,
+		uuid.NewString(), userId, product.Name, product.CatalogProductID, product.Category, product.Quantity, product.Unit,
+		sqliteTime(product.ExpiresAt), now, now))
+	if err != nil {
+		return entity.Product{}, productInsertError(sqliteError(err, ErrQueryFailed))
+	}
+	return added, nil
+}
+
+// sqliteRowsAffected - число строк, измененных запросом
+func sqliteRowsAffected(result sql.Result) (int64, error) {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, sqliteError(err, ErrQueryFailed)
+	}
+	return affected, nil
+}
+
+// deleteSQLiteProduct - аналог deleteProduct для SQLite
+func deleteSQLiteProduct(ctx context.Context, q sqliteQuerier, userId string, productId string) (int64, error) {
+	query := `DELETE FROM user_products WHERE user_id = ? AND id = ?`
+	result, err := q.ExecContext(ctx, query, userId, productId)
+	if err != nil {
+		return 0, sqliteError(err, ErrQueryFailed)
+	}
+	return sqliteRowsAffected(result)
+}
+
+func (r *sqliteRepository) GetProducts(ctx context.Context, userId string, productQuery ProductQuery) (ProductPage, error) {
+	defer metrics.ObserveQuery("get_products")()
+	query, args := productQuery.buildSQLite(userId)
+	page, err := retryResult(ctx, r.retries, func() (ProductPage, error) {
+		rows, err := r.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return ProductPage{}, sqliteError(err, ErrQueryFailed)
+		}
+		defer rows.Close()
+		var page ProductPage
+		for rows.Next() {
+			product, err := scanProduct(rows)
+			if err != nil {
+				return ProductPage{}, sqliteError(err, ErrNoRows)
+			}
+			page.Products = append(page.Products, product)
+		}
+		if err := rows.Err(); err != nil {
+			return ProductPage{}, sqliteError(err, ErrQueryFailed)
+		}
+		return page, nil
+	})
+	if err != nil {
+		return ProductPage{}, err
+	}
+	if len(page.Products) > productQuery.Limit {
+		page.Products = page.Products[:productQuery.Limit]
+		next := CursorFromProduct(page.Products[len(page.Products)-1])
+		page.Next = &next
+	}
+	return page, nil
+}
+
+func (r *sqliteRepository) AddProduct(ctx context.Context, userId string, product entity.Product) (entity.Product, error) {
+	defer metrics.ObserveQuery("add_product")()
+	added, err := retryResult(ctx, r.retries, func() (entity.Product, error) {
+		return insertSQLiteProduct(ctx, r.db, userId, product)
+	})
+	if err != nil {
+		return entity.Product{}, err
+	}
+	r.log.DebugContext(ctx, "Product added successfully")
+	return added, nil
+}
+
+func (r *sqliteRepository) RemoveProduct(ctx context.Context, userId string, productId string) error {
+	defer metrics.ObserveQuery("remove_product")()
+	removed, err := retryResult(ctx, r.retries, func() (int64, error) {
+		return deleteSQLiteProduct(ctx, r.db, userId, productId)
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrProductNotFound
+	}
+	r.log.DebugContext(ctx, "Product removed successfully")
+	return nil
+}
+
+func (r *sqliteRepository) RemoveProductByName(ctx context.Context, userId string, productName string) (int64, error) {
+	defer metrics.ObserveQuery("remove_product_by_name")()
+	query := `DELETE FROM user_products WHERE user_id = ? AND product_name = ?`
+	removed, err := retryResult(ctx, r.retries, func() (int64, error) {
+		result, err := r.db.ExecContext(ctx, query, userId, productName)
+		if err != nil {
+			return 0, sqliteError(err, ErrQueryFailed)
+		}
+		return sqliteRowsAffected(result)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if removed == 0 {
+		return 0, ErrProductNotFound
+	}
+	r.log.DebugContext(ctx, "Product removed successfully", "removed", removed)
+	return removed, nil
+}
+
+// AddProducts - добавляет продукты в одной транзакции с той же семантикой, что и в Postgres
+func (r *sqliteRepository) AddProducts(ctx context.Context, userId string, products []entity.Product, atomic bool) ([]BatchResult, error) {
+	defer metrics.ObserveQuery("add_products")()
+	return r.runBatch(ctx, len(products), atomic, func(ctx context.Context, tx sqliteQuerier, i int) (entity.Product, error) {
+		return insertSQLiteProduct(ctx, tx, userId, products[i])
+	})
+}
+
+// RemoveProducts - удаляет продукты по идентификаторам в одной транзакции,
+// семантика такая же, как у AddProducts
+func (r *sqliteRepository) RemoveProducts(ctx context.Context, userId string, productIds []string, atomic bool) ([]BatchResult, error) {
+	defer metrics.ObserveQuery("remove_products")()
+	return r.runBatch(ctx, len(productIds), atomic, func(ctx context.Context, tx sqliteQuerier, i int) (entity.Product, error) {
+		removed, err := deleteSQLiteProduct(ctx, tx, userId, productIds[i])
+		if err != nil {
+			return entity.Product{}, err
+		}
+		if removed == 0 {
+			return entity.Product{}, ErrProductNotFound
+		}
+		return entity.Product{ID: productIds[i]}, nil
+	})
+}
+
+// runBatch - выполняет n элементов пакета в одной транзакции, каждый в своей точке сохранения.
+// При временной ошибке хранилища пакет повторяется целиком.
+func (r *sqliteRepository) runBatch(
+	ctx context.Context,
+	n int,
+	atomic bool,
+	apply func(ctx context.Context, tx sqliteQuerier, i int) (entity.Product, error),
+) ([]BatchResult, error) {
+	return retryResult(ctx, r.retries, func() ([]BatchResult, error) {
+		tx, err := r.db.begin(ctx)
+		if err != nil {
+			return nil, sqliteError(err, ErrTransactionFailed)
+		}
+		defer tx.rollback()
+
+		results := make([]BatchResult, n)
+		failed := false
+		for i := range n {
+			savepoint, err := tx.begin(ctx)
+			if err != nil {
+				return nil, sqliteError(err, ErrTransactionFailed)
+			}
+			product, err := apply(ctx, savepoint, i)
+			if err != nil {
+				if rbErr := savepoint.rollback(); rbErr != nil {
+					return nil, sqliteError(rbErr, ErrTransactionFailed)
+				}
+				results[i].Err = err
+				failed = true
+				continue
+			}
+			if err := savepoint.commit(); err != nil {
+				return nil, sqliteError(err, ErrTransactionFailed)
+			}
+			results[i].Product = product
+		}
+
+		if atomic && failed {
+			for i := range results {
+				if results[i].Err == nil {
+					results[i] = BatchResult{Err: ErrBatchAborted}
+				}
+			}
+			r.log.DebugContext(ctx, "Batch rolled back")
+			return results, nil
+		}
+
+		if err := tx.commit(); err != nil {
+			return nil, sqliteError(err, ErrTransactionFailed)
+		}
+		r.log.DebugContext(ctx, "Batch committed successfully")
+		return results, nil
+	})
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	sqliteadapter "user-service/internal/adapter/sqlite"
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
+)
+
+// sqliteCatalogSelect - аналог catalogSelect для SQLite: названия и синонимы собираются в JSON
+const sqliteCatalogSelect = `SELECT p.id, p.canonical_name, p.category, p.created_at, p.updated_at,
+	(SELECT json_group_object(n.locale, n.display_name) FROM product_names n WHERE n.product_id = p.id),
+	(SELECT json_group_array(alias) FROM (SELECT a.alias FROM product_aliases a WHERE a.product_id = p.id ORDER BY a.alias))
+	FROM products p`
+
+// scanSQLiteCatalogProduct - сканирует строку sqliteCatalogSelect в entity.CatalogProduct
+func scanSQLiteCatalogProduct(row rowScanner) (entity.CatalogProduct, error) {
+	var product entity.CatalogProduct
+	var displayNames, aliases string
+	err := row.Scan(
+		&product.ID,
+		&product.CanonicalName,
+		&product.Category,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+		&displayNames,
+		&aliases,
+	)
+	if err != nil {
+		return entity.CatalogProduct{}, err
+	}
+	if err := json.Unmarshal([]byte(displayNames), &product.DisplayNames); err != nil {
+		return entity.CatalogProduct{}, err
+	}
+	if err := json.Unmarshal([]byte(aliases), &product.Aliases); err != nil {
+		return entity.CatalogProduct{}, err
+	}
+	return product, nil
+}
+
+func (r *sqliteRepository) ResolveProduct(ctx context.Context, name string) (entity.CatalogProduct, error) {
+	defer metrics.ObserveQuery("catalog_resolve_product")()
+	query := sqliteCatalogSelect + ` JOIN product_aliases pa ON pa.product_id = p.id WHERE pa.alias = ?`
+	return retryResult(ctx, r.retries, func() (entity.CatalogProduct, error) {
+		product, err := scanSQLiteCatalogProduct(r.db.QueryRowContext(ctx, query, entity.NormalizeProductName(name)))
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.CatalogProduct{}, ErrCatalogProductNotFound
+		}
+		if err != nil {
+			return entity.CatalogProduct{}, sqliteError(err, ErrQueryFailed)
+		}
+		return product, nil
+	})
+}
+
+func (r *sqliteRepository) UpsertProduct(ctx context.Context, product entity.CatalogProduct) (entity.CatalogProduct, error) {
+	defer metrics.ObserveQuery("catalog_upsert_product")()
+	saved, err := retryResult(ctx, r.retries, func() (entity.CatalogProduct, error) {
+		return r.upsertProduct(ctx, product)
+	})
+	if err != nil {
+		return entity.CatalogProduct{}, err
+	}
+	r.log.DebugContext(ctx, "Catalog product saved successfully")
+	return saved, nil
+}
+
+// upsertProduct - одна попытка транзакции UpsertProduct
+func (r *sqliteRepository) upsertProduct(ctx context.Context, product entity.CatalogProduct) (entity.CatalogProduct, error) {
+	tx, err := r.db.begin(ctx)
+	if err != nil {
+		return entity.CatalogProduct{}, sqliteError(err, ErrTransactionFailed)
+	}
+	defer tx.rollback()
+
+	now := sqliteNow()
+	query := `INSERT INTO products (id, canonical_name, category, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (canonical_name) DO UPDATE
+		SET category = excluded.category, updated_at = excluded.updated_at
+		RETURNING id`
+	var productId string
+	if err := tx.QueryRowContext(ctx, query, uuid.NewString(), product.CanonicalName, product.Category, now, now).Scan(&productId); err != nil {
+		return entity.CatalogProduct{}, sqliteError(err, ErrQueryFailed)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM product_names WHERE product_id = ?`, productId); err != nil {
+		return entity.CatalogProduct{}, sqliteError(err, ErrQueryFailed)
+	}
+	for locale, displayName := range product.DisplayNames {
+		query = `INSERT INTO product_names (product_id, locale, display_name) VALUES (?, ?, ?)`
+		if _, err := tx.ExecContext(ctx, query, productId, locale, displayName); err != nil {
+			return entity.CatalogProduct{}, sqliteError(err, ErrQueryFailed)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM product_aliases WHERE product_id = ?`, productId); err != nil {
+		return entity.CatalogProduct{}, sqliteError(err, ErrQueryFailed)
+	}
+	for _, alias := range catalogAliases(product) {
+		query = `INSERT INTO product_aliases (alias, product_id) VALUES (?, ?)`
+		if _, err := tx.ExecContext(ctx, query, alias, productId); err != nil {
+			repoErr := sqliteError(err, ErrQueryFailed)
+			if repoErr.Class == ErrUniqueViolation {
+				repoErr.Kind = ErrCatalogAliasConflict
+			}
+			return entity.CatalogProduct{}, repoErr
+		}
+	}
+
+	saved, err := scanSQLiteCatalogProduct(tx.QueryRowContext(ctx, sqliteCatalogSelect+` WHERE p.id = ?`, productId))
+	if err != nil {
+		return entity.CatalogProduct{}, sqliteError(err, ErrQueryFailed)
+	}
+	if err := tx.commit(); err != nil {
+		return entity.CatalogProduct{}, sqliteError(err, ErrTransactionFailed)
+	}
+	return saved, nil
+}
+
+func (r *sqliteRepository) DeleteProduct(ctx context.Context, productId string) error {
+	defer metrics.ObserveQuery("catalog_delete_product")()
+	removed, err := retryResult(ctx, r.retries, func() (int64, error) {
+		result, err := r.db.ExecContext(ctx, `DELETE FROM products WHERE id = ?`, productId)
+		if err != nil {
+			return 0, sqliteError(err, ErrQueryFailed)
+		}
+		return sqliteRowsAffected(result)
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrCatalogProductNotFound
+	}
+	r.log.DebugContext(ctx, "Catalog product removed successfully")
+	return nil
+}
+
+func (r *sqliteRepository) ListProducts(ctx context.Context, namePrefix string, after string, limit int) ([]entity.CatalogProduct, error) {
+	defer metrics.ObserveQuery("catalog_list_products")()
+	query := sqliteCatalogSelect + ` WHERE instr(` + sqliteadapter.LowerFunction + `(p.canonical_name), ?) = 1 AND p.canonical_name > ?
+		ORDER BY p.canonical_name LIMIT ?`
+	return retryResult(ctx, r.retries, func() ([]entity.CatalogProduct, error) {
+		rows, err := r.db.QueryContext(ctx, query, strings.ToLower(namePrefix), after, limit)
+		if err != nil {
+			return nil, sqliteError(err, ErrQueryFailed)
+		}
+		defer rows.Close()
+		var products []entity.CatalogProduct
+		for rows.Next() {
+			product, err := scanSQLiteCatalogProduct(rows)
+			if err != nil {
+				return nil, sqliteError(err, ErrNoRows)
+			}
+			products = append(products, product)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, sqliteError(err, ErrQueryFailed)
+		}
+		return products, nil
+	})
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
+)
+
+var _ OutboxRepository = (*sqliteRepository)(nil)
+
+func (r *sqliteRepository) AddEvents(ctx context.Context, events ...entity.Event) error {
+	defer metrics.ObserveQuery("add_events")()
+	if len(events) == 0 {
+		return nil
+	}
+	query := `INSERT INTO outbox_events (user_id, type, payload, created_at) VALUES (?, ?, ?, ?)`
+	return retry(ctx, r.retries, func() error {
+		tx, err := r.db.begin(ctx)
+		if err != nil {
+			return sqliteError(err, ErrTransactionFailed)
+		}
+		defer tx.rollback()
+
+		now := sqliteNow()
+		for _, event := range events {
+			if _, err := tx.ExecContext(ctx, query, event.UserID, string(event.Type), string(event.Payload), now); err != nil {
+				return sqliteError(err, ErrQueryFailed)
+			}
+		}
+		if err := tx.commit(); err != nil {
+			return sqliteError(err, ErrTransactionFailed)
+		}
+		return nil
+	})
+}
+
+// PublishEvents - файл SQLite принадлежит одному процессу, поэтому других relay нет, и транзакция
+// на время публикации не открывается: она заняла бы единственное соединение с базой
+func (r *sqliteRepository) PublishEvents(ctx context.Context, limit int, publish func(context.Context, []entity.Event) (int, error)) (int, error) {
+	defer metrics.ObserveQuery("publish_events")()
+	query := `SELECT id, user_id, type, payload, created_at FROM outbox_events ORDER BY id LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return 0, sqliteError(err, ErrQueryFailed)
+	}
+	var events []entity.Event
+	for rows.Next() {
+		var event entity.Event
+		if err := rows.Scan(&event.ID, &event.UserID, &event.Type, &event.Payload, &event.CreatedAt); err != nil {
+			rows.Close()
+			return 0, sqliteError(err, ErrQueryFailed)
+		}
+		events = append(events, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, sqliteError(err, ErrQueryFailed)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, outboxPublishTimeout)
+	published, publishErr := publish(publishCtx, events)
+	cancel()
+	if published == 0 {
+		return 0, publishErr
+	}
+	ids := make([]any, published)
+	for i := range ids {
+		ids[i] = events[i].ID
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", published), ", ")
+	// Опубликованные события удаляются и при отмене ctx, иначе они уйдут в брокер повторно
+	query = `DELETE FROM outbox_events WHERE id IN (` + placeholders + `)`
+	if _, err := r.db.ExecContext(context.WithoutCancel(ctx), query, ids...); err != nil {
+		return published, sqliteError(err, ErrQueryFailed)
+	}
+	return published, publishErr
+}
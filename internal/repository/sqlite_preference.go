@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
+)
+
+func (r *sqliteRepository) GetPreference(ctx context.Context, userId string) (entity.Preferences, error) {
+	defer metrics.ObserveQuery("get_preference")()
+	return retryResult(ctx, r.retries, func() (entity.Preferences, error) {
+		var preferences entity.Preferences
+		found := false
+
+		query := `SELECT calories, protein_g, fat_g, carbs_g, updated_at FROM user_preferences WHERE user_id = ?`
+		err := r.db.QueryRowContext(ctx, query, userId).Scan(
+			&preferences.Targets.Calories,
+			&preferences.Targets.ProteinG,
+			&preferences.Targets.FatG,
+			&preferences.Targets.CarbsG,
+			&preferences.UpdatedAt,
+		)
+		switch {
+		case err == nil:
+			found = true
+		case !errors.Is(err, sql.ErrNoRows):
+			return entity.Preferences{}, sqliteError(err, ErrQueryFailed)
+		}
+
+		query = `SELECT kind, value, created_at FROM user_preference_entries WHERE user_id = ? ORDER BY kind, value`
+		rows, err := r.db.QueryContext(ctx, query, userId)
+		if err != nil {
+			return entity.Preferences{}, sqliteError(err, ErrQueryFailed)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var entry entity.PreferenceEntry
+			var createdAt time.Time
+			if err := rows.Scan(&entry.Kind, &entry.Value, &createdAt); err != nil {
+				return entity.Preferences{}, sqliteError(err, ErrNoRows)
+			}
+			preferences.Add(entry)
+			if createdAt.After(preferences.UpdatedAt) {
+				preferences.UpdatedAt = createdAt
+			}
+			found = true
+		}
+		if err := rows.Err(); err != nil {
+			return entity.Preferences{}, sqliteError(err, ErrQueryFailed)
+		}
+
+		if !found {
+			return entity.Preferences{}, ErrPreferenceNotFound
+		}
+		return preferences, nil
+	})
+}
+
+func (r *sqliteRepository) UpdatePreference(ctx context.Context, userId string, preferences entity.Preferences) error {
+	defer metrics.ObserveQuery("update_preference")()
+	err := retry(ctx, r.retries, func() error {
+		tx, err := r.db.begin(ctx)
+		if err != nil {
+			return sqliteError(err, ErrTransactionFailed)
+		}
+		defer tx.rollback()
+
+		now := sqliteNow()
+		query := `INSERT INTO user_preferences (user_id, calories, protein_g, fat_g, carbs_g, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (user_id) DO UPDATE
+			SET calories = excluded.calories, protein_g = excluded.protein_g,
+				fat_g = excluded.fat_g, carbs_g = excluded.carbs_g, updated_at = excluded.updated_at`
+		targets := preferences.Targets
+		if _, err := tx.ExecContext(ctx, query, userId, targets.Calories, targets.ProteinG, targets.FatG, targets.CarbsG, now, now); err != nil {
+			return sqliteError(err, ErrPreferenceUpdateFailed)
+		}
+
+		query = `DELETE FROM user_preference_entries WHERE user_id = ?`
+		if _, err := tx.ExecContext(ctx, query, userId); err != nil {
+			return sqliteError(err, ErrPreferenceUpdateFailed)
+		}
+		for _, entry := range preferences.Entries() {
+			query = `INSERT INTO user_preference_entries (user_id, kind, value, created_at) VALUES (?, ?, ?, ?)
+				ON CONFLICT DO NOTHING`
+			if _, err := tx.ExecContext(ctx, query, userId, entry.Kind, entry.Value, now); err != nil {
+				return sqliteError(err, ErrPreferenceUpdateFailed)
+			}
+		}
+
+		if err := tx.commit(); err != nil {
+			return sqliteError(err, ErrTransactionFailed)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.log.DebugContext(ctx, "Preference updated successfully")
+	return nil
+}
+
+func (r *sqliteRepository) RemovePreference(ctx context.Context, userId string) error {
+	defer metrics.ObserveQuery("remove_preference")()
+	err := retry(ctx, r.retries, func() error {
+		tx, err := r.db.begin(ctx)
+		if err != nil {
+			return sqliteError(err, ErrTransactionFailed)
+		}
+		defer tx.rollback()
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_preference_entries WHERE user_id = ?`, userId); err != nil {
+			return sqliteError(err, ErrQueryFailed)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_preferences WHERE user_id = ?`, userId); err != nil {
+			return sqliteError(err, ErrQueryFailed)
+		}
+
+		if err := tx.commit(); err != nil {
+			return sqliteError(err, ErrTransactionFailed)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.log.DebugContext(ctx, "Preference removed successfully")
+	return nil
+}
+
+func (r *sqliteRepository) AddPreferenceEntry(ctx context.Context, userId string, entry entity.PreferenceEntry) error {
+	defer metrics.ObserveQuery("add_preference_entry")()
+	query := `INSERT INTO user_preference_entries (user_id, kind, value, created_at) VALUES (?, ?, ?, ?)`
+	err := retry(ctx, r.retries, func() error {
+		if _, err := r.db.ExecContext(ctx, query, userId, entry.Kind, entry.Value, sqliteNow()); err != nil {
+			repoErr := sqliteError(err, ErrAddUserFailed)
+			if repoErr.Class == ErrUniqueViolation {
+				repoErr.Kind = ErrPreferenceExists
+			}
+			return repoErr
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.log.DebugContext(ctx, "Preference entry added successfully")
+	return nil
+}
+
+func (r *sqliteRepository) RemovePreferenceEntry(ctx context.Context, userId string, entry entity.PreferenceEntry) error {
+	defer metrics.ObserveQuery("remove_preference_entry")()
+	query := `DELETE FROM user_preference_entries WHERE user_id = ? AND kind = ? AND value = ?`
+	removed, err := retryResult(ctx, r.retries, func() (int64, error) {
+		result, err := r.db.ExecContext(ctx, query, userId, entry.Kind, entry.Value)
+		if err != nil {
+			return 0, sqliteError(err, ErrQueryFailed)
+		}
+		return sqliteRowsAffected(result)
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrPreferenceNotFound
+	}
+	r.log.DebugContext(ctx, "Preference entry removed successfully")
+	return nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
+)
+
+func (r *sqliteRepository) RevokeToken(ctx context.Context, token entity.RevokedToken) error {
+	defer metrics.ObserveQuery("revoke_token")()
+	query := `INSERT INTO revoked_tokens (jti, user_id, expires_at, revoked_at) VALUES (?, NULLIF(?, ''), ?, ?)
+		ON CONFLICT (jti) DO UPDATE SET expires_at = max(revoked_tokens.expires_at, excluded.expires_at)`
+	now := sqliteNow()
+	err := retry(ctx, r.retries, func() error {
+		if _, err := r.db.ExecContext(ctx, query, token.TokenID, token.UserID, *sqliteTime(&token.ExpiresAt), now); err != nil {
+			return sqliteError(err, ErrQueryFailed)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// Отзывы истекших токенов больше ни на что не влияют
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE expires_at < ?`, now); err != nil {
+		r.log.WarnContext(ctx, "Failed to purge expired token revocations", "error", err)
+	}
+	r.log.DebugContext(ctx, "Token revoked successfully")
+	return nil
+}
+
+func (r *sqliteRepository) RevokeUserTokens(ctx context.Context, revocation entity.UserTokensRevocation) (entity.UserTokensRevocation, error) {
+	defer metrics.ObserveQuery("revoke_user_tokens")()
+	query := `INSERT INTO user_token_revocations (user_id, revoked_before, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE
+		SET revoked_before = max(user_token_revocations.revoked_before, excluded.revoked_before),
+			updated_at = excluded.updated_at
+		RETURNING revoked_before`
+	revokedBefore, err := retryResult(ctx, r.retries, func() (time.Time, error) {
+		var revokedBefore time.Time
+		err := r.db.QueryRowContext(ctx, query, revocation.UserID, *sqliteTime(&revocation.RevokedBefore), sqliteNow()).
+			Scan(&revokedBefore)
+		if err != nil {
+			return time.Time{}, sqliteError(err, ErrQueryFailed)
+		}
+		return revokedBefore, nil
+	})
+	if err != nil {
+		return entity.UserTokensRevocation{}, err
+	}
+	revocation.RevokedBefore = revokedBefore
+	r.log.DebugContext(ctx, "User tokens revoked successfully")
+	return revocation, nil
+}
+
+func (r *sqliteRepository) ListRevocations(ctx context.Context) (entity.Revocations, error) {
+	defer metrics.ObserveQuery("list_revocations")()
+	return retryResult(ctx, r.retries, func() (entity.Revocations, error) {
+		return r.listRevocations(ctx)
+	})
+}
+
+// listRevocations - одна попытка ListRevocations
+func (r *sqliteRepository) listRevocations(ctx context.Context) (entity.Revocations, error) {
+	var revocations entity.Revocations
+
+	query := `SELECT jti, COALESCE(user_id, ''), expires_at FROM revoked_tokens WHERE expires_at >= ?`
+	rows, err := r.db.QueryContext(ctx, query, sqliteNow())
+	if err != nil {
+		return entity.Revocations{}, sqliteError(err, ErrQueryFailed)
+	}
+	for rows.Next() {
+		var token entity.RevokedToken
+		if err := rows.Scan(&token.TokenID, &token.UserID, &token.ExpiresAt); err != nil {
+			rows.Close()
+			return entity.Revocations{}, sqliteError(err, ErrNoRows)
+		}
+		revocations.Tokens = append(revocations.Tokens, token)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return entity.Revocations{}, sqliteError(err, ErrQueryFailed)
+	}
+
+	rows, err = r.db.QueryContext(ctx, `SELECT user_id, revoked_before FROM user_token_revocations`)
+	if err != nil {
+		return entity.Revocations{}, sqliteError(err, ErrQueryFailed)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var revocation entity.UserTokensRevocation
+		if err := rows.Scan(&revocation.UserID, &revocation.RevokedBefore); err != nil {
+			return entity.Revocations{}, sqliteError(err, ErrNoRows)
+		}
+		revocations.Users = append(revocations.Users, revocation)
+	}
+	if err := rows.Err(); err != nil {
+		return entity.Revocations{}, sqliteError(err, ErrQueryFailed)
+	}
+
+	return revocations, nil
+}
+
+// WatchRevocations - файл SQLite принадлежит одному процессу, а отзывы этого процесса
+// usecase применяет к кэшу сам, поэтому после подписки остается только ждать отмены ctx
+func (r *sqliteRepository) WatchRevocations(ctx context.Context, subscribed func() error, changed func(entity.Revocations)) error {
+	if err := subscribed(); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// IsolationLevel - уровень изоляции транзакции WithTx
+type IsolationLevel int
+
+const (
+	// ReadCommitted - уровень по умолчанию: каждый запрос видит данные, зафиксированные до его начала
+	ReadCommitted IsolationLevel = iota
+	// RepeatableRead - все запросы транзакции видят один снимок данных
+	RepeatableRead
+	// Serializable - результат такой же, как при последовательном выполнении транзакций;
+	// конфликтующая транзакция откатывается с ErrSerializationFailure и повторяется
+	Serializable
+)
+
+// pgx - уровень изоляции pgx
+func (l IsolationLevel) pgx() pgx.TxIsoLevel {
+	switch l {
+	case RepeatableRead:
+		return pgx.RepeatableRead
+	case Serializable:
+		return pgx.Serializable
+	default:
+		return pgx.ReadCommitted
+	}
+}
+
+// txOptions - параметры транзакции WithTx
+type txOptions struct {
+	isolation IsolationLevel
+}
+
+// TxOption - параметр транзакции WithTx
+type TxOption func(*txOptions)
+
+// WithIsolation - уровень изоляции транзакции; у вложенных вызовов WithTx не учитывается
+func WithIsolation(level IsolationLevel) TxOption {
+	return func(o *txOptions) {
+		o.isolation = level
+	}
+}
+
+// newTxOptions - параметры транзакции с учетом переданных опций
+func newTxOptions(opts []TxOption) txOptions {
+	options := txOptions{isolation: ReadCommitted}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// WithTx - выполняет fn в транзакции Postgres с уровнем изоляции из opts.
+// Если транзакция прервана временной ошибкой хранилища (конфликт сериализации, обрыв соединения),
+// fn вызывается заново в новой транзакции, поэтому fn не должна иметь побочных эффектов вне репозитория.
+// Внутри транзакции WithTx создает точку сохранения: ошибка fn откатывает только ее изменения.
+func (r *repository) WithTx(ctx context.Context, fn func(Repository) error, opts ...TxOption) error {
+	if _, ok := r.db.(pgx.Tx); ok {
+		return r.withSavepoint(ctx, fn)
+	}
+	options := newTxOptions(opts)
+	return retry(ctx, r.retries, func() error {
+		tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: options.isolation.pgx()})
+		if err != nil {
+			return pgError(err, ErrTransactionFailed)
+		}
+		defer tx.Rollback(ctx)
+
+		if err := fn(r.inTx(tx)); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return pgError(err, ErrTransactionFailed)
+		}
+		return nil
+	})
+}
+
+// withSavepoint - вложенный WithTx: fn выполняется в точке сохранения текущей транзакции
+func (r *repository) withSavepoint(ctx context.Context, fn func(Repository) error) error {
+	savepoint, err := r.db.Begin(ctx)
+	if err != nil {
+		return pgError(err, ErrTransactionFailed)
+	}
+	defer savepoint.Rollback(ctx)
+
+	if err := fn(r.inTx(savepoint)); err != nil {
+		return err
+	}
+	if err := savepoint.Commit(ctx); err != nil {
+		return pgError(err, ErrTransactionFailed)
+	}
+	return nil
+}
+
+// inTx - репозиторий, выполняющий запросы в транзакции tx без отдельных повторов
+func (r *repository) inTx(tx pgx.Tx) *repository {
+	return &repository{
+		pool:    r.pool,
+		db:      tx,
+		log:     r.log,
+		retries: retryPolicy{log: r.log, attempts: 1},
+	}
+}
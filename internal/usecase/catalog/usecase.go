@@ -0,0 +1,173 @@
+package catalog
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"user-service/internal/entity"
+	"user-service/internal/repository"
+	"user-service/internal/tracing"
+	usecase "user-service/internal/usecase/user"
+)
+
+// tracer - источник спанов usecase-слоя
+var tracer = otel.Tracer("user-service/internal/usecase/catalog")
+
+// Список ошибок
+var (
+	// ErrEmptyCanonicalName - ошибка, когда не передано каноническое название
+	ErrEmptyCanonicalName = errors.New("canonical name is empty")
+	// ErrNameTooLong - ошибка, когда название или синоним длиннее maxNameLength
+	ErrNameTooLong = errors.New("name must be at most 255 characters")
+	// ErrCategoryTooLong - ошибка, когда категория длиннее maxCategoryLength
+	ErrCategoryTooLong = errors.New("category must be at most 64 characters")
+	// ErrInvalidLocale - ошибка, когда локаль пустая или длиннее maxLocaleLength
+	ErrInvalidLocale = errors.New("locale must be 1 to 16 characters")
+	// ErrInvalidProductId - ошибка, когда идентификатор записи каталога не является UUID
+	ErrInvalidProductId = errors.New("product id is not a valid uuid")
+	// ErrInvalidPageSize - ошибка, когда размер страницы отрицательный
+	ErrInvalidPageSize = errors.New("page size must not be negative")
+	// ErrInvalidPageToken - ошибка, когда токен страницы поврежден
+	ErrInvalidPageToken = errors.New("page token is invalid")
+)
+
+const (
+	// maxNameLength - максимальная длина названий, совпадает с колонками каталога
+	maxNameLength = 255
+	// maxCategoryLength - максимальная длина категории, совпадает с колонкой products.category
+	maxCategoryLength = 64
+	// maxLocaleLength - максимальная длина локали, совпадает с колонкой product_names.locale
+	maxLocaleLength = 16
+	// defaultPageSize - размер страницы каталога, если клиент его не указал
+	defaultPageSize = 100
+	// maxPageSize - максимальный размер страницы каталога
+	maxPageSize = 1000
+)
+
+var _ CatalogUseCase = (*catalog)(nil)
+
+// CatalogUseCase - интерфейс для ведения каталога продуктов
+type CatalogUseCase interface {
+	// UpsertProduct - создать или обновить запись каталога
+	UpsertProduct(ctx context.Context, product entity.CatalogProduct) (saved entity.CatalogProduct, err error)
+	// DeleteProduct - удалить запись каталога
+	DeleteProduct(ctx context.Context, productId string) (err error)
+	// ListProducts - получить страницу записей каталога
+	ListProducts(ctx context.Context, namePrefix string, pageSize int, pageToken string) (products []entity.CatalogProduct, nextPageToken string, err error)
+}
+
+type catalog struct {
+	catalogRepo repository.CatalogRepository
+	log         *slog.Logger
+}
+
+// New - конструктор для создания нового экземпляра CatalogUseCase
+func New(catalogRepo repository.CatalogRepository, log *slog.Logger) *catalog {
+	return &catalog{
+		catalogRepo: catalogRepo,
+		log:         log,
+	}
+}
+
+func (c *catalog) UpsertProduct(ctx context.Context, product entity.CatalogProduct) (saved entity.CatalogProduct, err error) {
+	ctx, span := tracer.Start(ctx, "CatalogUseCase.UpsertProduct")
+	defer tracing.End(span, &err)
+
+	product, err = normalizeCatalogProduct(product)
+	if err != nil {
+		return entity.CatalogProduct{}, err
+	}
+	saved, err = c.catalogRepo.UpsertProduct(ctx, product)
+	if err != nil {
+		return entity.CatalogProduct{}, err
+	}
+	c.log.InfoContext(ctx, "Catalog product saved", "catalog_product_id", saved.ID)
+	return saved, nil
+}
+
+func (c *catalog) DeleteProduct(ctx context.Context, productId string) (err error) {
+	ctx, span := tracer.Start(ctx, "CatalogUseCase.DeleteProduct")
+	defer tracing.End(span, &err)
+
+	if _, err := uuid.Parse(productId); err != nil {
+		return &usecase.FieldError{Field: "id", Err: ErrInvalidProductId}
+	}
+	if err := c.catalogRepo.DeleteProduct(ctx, productId); err != nil {
+		return err
+	}
+	c.log.InfoContext(ctx, "Catalog product deleted", "catalog_product_id", productId)
+	return nil
+}
+
+func (c *catalog) ListProducts(ctx context.Context, namePrefix string, pageSize int, pageToken string) (products []entity.CatalogProduct, nextPageToken string, err error) {
+	ctx, span := tracer.Start(ctx, "CatalogUseCase.ListProducts")
+	defer tracing.End(span, &err)
+
+	if pageSize < 0 {
+		return nil, "", &usecase.FieldError{Field: "page_size", Err: ErrInvalidPageSize}
+	}
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
+
+	after, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return nil, "", &usecase.FieldError{Field: "page_token", Err: ErrInvalidPageToken}
+	}
+
+	products, err = c.catalogRepo.ListProducts(ctx, namePrefix, string(after), pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(products) > pageSize {
+		products = products[:pageSize]
+		last := products[len(products)-1].CanonicalName
+		nextPageToken = base64.RawURLEncoding.EncodeToString([]byte(last))
+	}
+	return products, nextPageToken, nil
+}
+
+// normalizeCatalogProduct - проверяет запись каталога и убирает лишние пробелы
+func normalizeCatalogProduct(product entity.CatalogProduct) (entity.CatalogProduct, error) {
+	product.CanonicalName = strings.TrimSpace(product.CanonicalName)
+	product.Category = strings.TrimSpace(product.Category)
+	if product.CanonicalName == "" {
+		return product, &usecase.FieldError{Field: "canonical_name", Err: ErrEmptyCanonicalName}
+	}
+	if utf8.RuneCountInString(product.CanonicalName) > maxNameLength {
+		return product, &usecase.FieldError{Field: "canonical_name", Err: ErrNameTooLong}
+	}
+	if utf8.RuneCountInString(product.Category) > maxCategoryLength {
+		return product, &usecase.FieldError{Field: "category", Err: ErrCategoryTooLong}
+	}
+
+	displayNames := make(map[string]string, len(product.DisplayNames))
+	for locale, displayName := range product.DisplayNames {
+		locale = strings.ToLower(strings.TrimSpace(locale))
+		displayName = strings.TrimSpace(displayName)
+		if locale == "" || utf8.RuneCountInString(locale) > maxLocaleLength {
+			return product, &usecase.FieldError{Field: "display_names", Err: ErrInvalidLocale}
+		}
+		if utf8.RuneCountInString(displayName) > maxNameLength {
+			return product, &usecase.FieldError{Field: "display_names", Err: ErrNameTooLong}
+		}
+		if displayName != "" {
+			displayNames[locale] = displayName
+		}
+	}
+	product.DisplayNames = displayNames
+
+	for _, alias := range product.Aliases {
+		if utf8.RuneCountInString(alias) > maxNameLength {
+			return product, &usecase.FieldError{Field: "aliases", Err: ErrNameTooLong}
+		}
+	}
+	return product, nil
+}
@@ -0,0 +1,115 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"user-service/internal/auth"
+	"user-service/internal/entity"
+	"user-service/internal/repository"
+	"user-service/internal/tracing"
+	usecase "user-service/internal/usecase/user"
+)
+
+// tracer - источник спанов usecase-слоя
+var tracer = otel.Tracer("user-service/internal/usecase/revocation")
+
+// Список ошибок
+var (
+	// ErrEmptyTokenId - ошибка, когда не передан jti токена
+	ErrEmptyTokenId = errors.New("jti is empty")
+	// ErrTokenIdTooLong - ошибка, когда jti длиннее maxTokenIdLength
+	ErrTokenIdTooLong = errors.New("jti must be at most 255 characters")
+	// ErrEmptyExpiresAt - ошибка, когда не передан срок действия токена
+	ErrEmptyExpiresAt = errors.New("expires_at is required")
+	// ErrInvalidUserId - ошибка, когда идентификатор пользователя не является UUID
+	ErrInvalidUserId = errors.New("user id is not a valid uuid")
+)
+
+// maxTokenIdLength - максимальная длина jti, совпадает с колонкой revoked_tokens.jti
+const maxTokenIdLength = 255
+
+var _ RevocationUseCase = (*revocation)(nil)
+
+// RevocationUseCase - интерфейс для отзыва токенов доступа
+type RevocationUseCase interface {
+	// RevokeToken - отозвать один токен по jti
+	RevokeToken(ctx context.Context, token entity.RevokedToken) (err error)
+	// RevokeUserTokens - отозвать все токены пользователя, выпущенные не позже RevokedBefore
+	RevokeUserTokens(ctx context.Context, revocation entity.UserTokensRevocation) (saved entity.UserTokensRevocation, err error)
+}
+
+type revocation struct {
+	revocationRepo repository.RevocationRepository
+	revocations    *auth.RevocationList
+	log            *slog.Logger
+}
+
+// New - конструктор для RevocationUseCase.
+// Отзыв сразу применяется к кэшу этой реплики, остальные получают его через уведомление.
+func New(revocationRepo repository.RevocationRepository, revocations *auth.RevocationList, log *slog.Logger) *revocation {
+	return &revocation{
+		revocationRepo: revocationRepo,
+		revocations:    revocations,
+		log:            log,
+	}
+}
+
+func (r *revocation) RevokeToken(ctx context.Context, token entity.RevokedToken) (err error) {
+	ctx, span := tracer.Start(ctx, "RevocationUseCase.RevokeToken")
+	defer tracing.End(span, &err)
+
+	token.TokenID = strings.TrimSpace(token.TokenID)
+	if token.TokenID == "" {
+		return &usecase.FieldError{Field: "jti", Err: ErrEmptyTokenId}
+	}
+	if utf8.RuneCountInString(token.TokenID) > maxTokenIdLength {
+		return &usecase.FieldError{Field: "jti", Err: ErrTokenIdTooLong}
+	}
+	if token.ExpiresAt.IsZero() {
+		return &usecase.FieldError{Field: "expires_at", Err: ErrEmptyExpiresAt}
+	}
+	if token.UserID != "" {
+		if _, err := uuid.Parse(token.UserID); err != nil {
+			return &usecase.FieldError{Field: "user_id", Err: ErrInvalidUserId}
+		}
+	}
+
+	if err := r.revocationRepo.RevokeToken(ctx, token); err != nil {
+		return err
+	}
+	r.revocations.Apply(entity.Revocations{Tokens: []entity.RevokedToken{token}})
+	r.log.InfoContext(ctx, "Token revoked", "user_id", token.UserID, "expires_at", token.ExpiresAt)
+
+	return nil
+}
+
+func (r *revocation) RevokeUserTokens(ctx context.Context, revocation entity.UserTokensRevocation) (saved entity.UserTokensRevocation, err error) {
+	ctx, span := tracer.Start(ctx, "RevocationUseCase.RevokeUserTokens")
+	defer tracing.End(span, &err)
+
+	userId, err := uuid.Parse(revocation.UserID)
+	if err != nil {
+		return entity.UserTokensRevocation{}, &usecase.FieldError{Field: "user_id", Err: ErrInvalidUserId}
+	}
+	revocation.UserID = userId.String()
+	// Граница в будущем отклоняла бы и токены, выпущенные после отзыва
+	if now := time.Now(); revocation.RevokedBefore.IsZero() || revocation.RevokedBefore.After(now) {
+		revocation.RevokedBefore = now
+	}
+
+	saved, err = r.revocationRepo.RevokeUserTokens(ctx, revocation)
+	if err != nil {
+		return entity.UserTokensRevocation{}, err
+	}
+	r.revocations.Apply(entity.Revocations{Users: []entity.UserTokensRevocation{saved}})
+	r.log.InfoContext(ctx, "User tokens revoked", "user_id", saved.UserID, "revoked_before", saved.RevokedBefore)
+
+	return saved, nil
+}
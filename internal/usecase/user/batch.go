@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"user-service/internal/auth"
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
+	"user-service/internal/repository"
+	"user-service/internal/tracing"
+)
+
+// maxBatchSize - максимальное количество элементов в одном пакетном запросе
+const maxBatchSize = 500
+
+var (
+	// ErrEmptyBatch - ошибка, когда в пакетном запросе нет элементов
+	ErrEmptyBatch = errors.New("batch is empty")
+	// ErrBatchTooLarge - ошибка, когда в пакетном запросе больше maxBatchSize элементов
+	ErrBatchTooLarge = errors.New("batch is too large")
+)
+
+// BatchResult - результат обработки одного элемента пакетного запроса
+type BatchResult struct {
+	// Product - сохраненный продукт при добавлении или продукт с одним ID при удалении
+	Product entity.Product
+	// Err - ошибка элемента, nil при успехе
+	Err error
+}
+
+// BatchResponse - результат пакетного запроса
+type BatchResponse struct {
+	// Results - результаты в порядке элементов запроса
+	Results []BatchResult
+	// Committed - были ли изменения сохранены
+	Committed bool
+}
+
+func (u *user) BatchAddUserProducts(ctx context.Context, products []entity.Product, allOrNothing bool) (response BatchResponse, err error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.BatchAddUserProducts")
+	defer tracing.End(span, &err)
+
+	if err := validateBatchSize(len(products)); err != nil {
+		return BatchResponse{}, err
+	}
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return BatchResponse{}, err
+	}
+
+	results := make([]BatchResult, len(products))
+	valid := make([]entity.Product, 0, len(products))
+	for i, product := range products {
+		normalized, err := normalizeProduct(product)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		resolved, err := u.resolveProduct(ctx, normalized)
+		if err != nil {
+			return BatchResponse{}, err
+		}
+		valid = append(valid, resolved)
+	}
+
+	response, err = u.runBatch(results, len(valid), allOrNothing, func(atomic bool) ([]repository.BatchResult, error) {
+		return u.batchWithEvents(ctx, atomic, func(repo repository.Repository) ([]repository.BatchResult, error) {
+			return repo.AddProducts(ctx, userId.String(), valid, atomic)
+		}, func(product entity.Product) (entity.Event, error) {
+			return productAdded(userId.String(), product)
+		})
+	})
+	if err != nil {
+		return BatchResponse{}, err
+	}
+	metrics.ProductsAdded(response.succeeded())
+	return response, nil
+}
+
+func (u *user) BatchRemoveUserProducts(ctx context.Context, productIds []string, allOrNothing bool) (response BatchResponse, err error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.BatchRemoveUserProducts")
+	defer tracing.End(span, &err)
+
+	if err := validateBatchSize(len(productIds)); err != nil {
+		return BatchResponse{}, err
+	}
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return BatchResponse{}, err
+	}
+
+	results := make([]BatchResult, len(productIds))
+	valid := make([]string, 0, len(productIds))
+	for i, productId := range productIds {
+		if _, err := uuid.Parse(productId); err != nil {
+			results[i].Err = &FieldError{Field: "product_ids", Err: ErrInvalidProductId}
+			continue
+		}
+		valid = append(valid, productId)
+	}
+
+	response, err = u.runBatch(results, len(valid), allOrNothing, func(atomic bool) ([]repository.BatchResult, error) {
+		return u.batchWithEvents(ctx, atomic, func(repo repository.Repository) ([]repository.BatchResult, error) {
+			return repo.RemoveProducts(ctx, userId.String(), valid, atomic)
+		}, func(product entity.Product) (entity.Event, error) {
+			return productRemoved(userId.String(), product.ID, "")
+		})
+	})
+	if err != nil {
+		return BatchResponse{}, err
+	}
+	metrics.ProductsRemoved(response.succeeded())
+	return response, nil
+}
+
+// runBatch - отправляет прошедшие валидацию элементы в репозиторий и раскладывает
+// его результаты по позициям исходного запроса. results уже содержит ошибки валидации.
+func (u *user) runBatch(
+	results []BatchResult,
+	validCount int,
+	allOrNothing bool,
+	apply func(atomic bool) ([]repository.BatchResult, error),
+) (BatchResponse, error) {
+	invalidCount := len(results) - validCount
+
+	// В режиме "все или ничего" ошибка валидации отменяет пакет без обращения к базе
+	if allOrNothing && invalidCount > 0 {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Err = repository.ErrBatchAborted
+			}
+		}
+		return BatchResponse{Results: results}, nil
+	}
+	if validCount == 0 {
+		return BatchResponse{Results: results}, nil
+	}
+
+	repoResults, err := apply(allOrNothing)
+	if err != nil {
+		return BatchResponse{}, err
+	}
+
+	committed := true
+	j := 0
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		results[i] = BatchResult{Product: repoResults[j].Product, Err: repoResults[j].Err}
+		if allOrNothing && results[i].Err != nil {
+			committed = false
+		}
+		j++
+	}
+	return BatchResponse{Results: results, Committed: committed}, nil
+}
+
+// batchWithEvents - выполняет пакетную операцию apply и записывает события о сохраненных
+// элементах в той же транзакции. Пакет "все или ничего" с ошибкой откатывается и событий не порождает.
+func (u *user) batchWithEvents(
+	ctx context.Context,
+	atomic bool,
+	apply func(repo repository.Repository) ([]repository.BatchResult, error),
+	event func(product entity.Product) (entity.Event, error),
+) ([]repository.BatchResult, error) {
+	var results []repository.BatchResult
+	err := u.userRepo.WithTx(ctx, func(repo repository.Repository) error {
+		var err error
+		results, err = apply(repo)
+		if err != nil {
+			return err
+		}
+		events := make([]entity.Event, 0, len(results))
+		for _, result := range results {
+			if result.Err != nil {
+				if atomic {
+					return nil
+				}
+				continue
+			}
+			e, err := event(result.Product)
+			if err != nil {
+				return err
+			}
+			events = append(events, e)
+		}
+		return repo.AddEvents(ctx, events...)
+	})
+	return results, err
+}
+
+// succeeded - сколько элементов пакета сохранено
+func (r BatchResponse) succeeded() int {
+	if !r.Committed {
+		return 0
+	}
+	n := 0
+	for _, result := range r.Results {
+		if result.Err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// validateBatchSize - проверяет количество элементов пакетного запроса
+func validateBatchSize(n int) error {
+	if n == 0 {
+		return &FieldError{Field: "items", Err: ErrEmptyBatch}
+	}
+	if n > maxBatchSize {
+		return &FieldError{Field: "items", Err: ErrBatchTooLarge}
+	}
+	return nil
+}
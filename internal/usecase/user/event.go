@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"encoding/json"
+	"time"
+
+	"user-service/internal/entity"
+)
+
+// productEvent - данные событий ProductAdded и ProductRemoved. У ProductRemoved заполнены
+// только поля, по которым продукт удаляли: product_id или name.
+type productEvent struct {
+	ProductID        string     `json:"product_id,omitempty"`
+	Name             string     `json:"name,omitempty"`
+	CatalogProductID string     `json:"catalog_product_id,omitempty"`
+	Category         string     `json:"category,omitempty"`
+	Quantity         float64    `json:"quantity,omitempty"`
+	Unit             string     `json:"unit,omitempty"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+}
+
+// preferenceEvent - данные события PreferenceChanged: набор предпочтений после изменения
+type preferenceEvent struct {
+	Diets               []string         `json:"diets"`
+	Allergens           []string         `json:"allergens"`
+	DislikedIngredients []string         `json:"disliked_ingredients"`
+	Targets             nutritionTargets `json:"targets"`
+}
+
+type nutritionTargets struct {
+	Calories int32   `json:"calories"`
+	ProteinG float64 `json:"protein_g"`
+	FatG     float64 `json:"fat_g"`
+	CarbsG   float64 `json:"carbs_g"`
+}
+
+// productAdded - событие ProductAdded о сохраненном продукте
+func productAdded(userId string, product entity.Product) (entity.Event, error) {
+	return newEvent(userId, entity.EventProductAdded, productEvent{
+		ProductID:        product.ID,
+		Name:             product.Name,
+		CatalogProductID: product.CatalogProductID,
+		Category:         product.Category,
+		Quantity:         product.Quantity,
+		Unit:             product.Unit,
+		ExpiresAt:        product.ExpiresAt,
+	})
+}
+
+// productRemoved - событие ProductRemoved о продукте, удаленном по идентификатору или названию
+func productRemoved(userId string, productId string, productName string) (entity.Event, error) {
+	return newEvent(userId, entity.EventProductRemoved, productEvent{ProductID: productId, Name: productName})
+}
+
+// preferenceChanged - событие PreferenceChanged с набором предпочтений после изменения
+func preferenceChanged(userId string, preferences entity.Preferences) (entity.Event, error) {
+	return newEvent(userId, entity.EventPreferenceChanged, preferenceEvent{
+		Diets:               nonNil(preferences.Diets),
+		Allergens:           nonNil(preferences.Allergens),
+		DislikedIngredients: nonNil(preferences.DislikedIngredients),
+		Targets: nutritionTargets{
+			Calories: preferences.Targets.Calories,
+			ProteinG: preferences.Targets.ProteinG,
+			FatG:     preferences.Targets.FatG,
+			CarbsG:   preferences.Targets.CarbsG,
+		},
+	})
+}
+
+// newEvent - событие с данными payload в JSON
+func newEvent(userId string, eventType entity.EventType, payload any) (entity.Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return entity.Event{}, err
+	}
+	return entity.Event{Type: eventType, UserID: userId, Payload: data}, nil
+}
+
+// nonNil - пустой список вместо nil, чтобы в JSON был [] вместо null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"user-service/internal/repository"
+)
+
+// pageToken - содержимое непрозрачного токена страницы.
+// Вместе с курсором хранятся параметры выборки, чтобы токен нельзя было
+// использовать с другой сортировкой или другими фильтрами.
+type pageToken struct {
+	SortBy     repository.ProductSortField `json:"s"`
+	Descending bool                        `json:"d"`
+	Filter     string                      `json:"f"`
+	Cursor     repository.ProductCursor    `json:"c"`
+}
+
+// filterFingerprint - короткий отпечаток фильтра для проверки токена страницы
+func filterFingerprint(filter repository.ProductFilter) string {
+	var expiringBefore string
+	if filter.ExpiringBefore != nil {
+		expiringBefore = filter.ExpiringBefore.UTC().Format(time.RFC3339Nano)
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%q|%q|%q", filter.NamePrefix, expiringBefore, filter.Category)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// encodePageToken - кодирует курсор следующей страницы в строку для клиента
+func encodePageToken(req ProductsRequest, cursor repository.ProductCursor) (string, error) {
+	data, err := json.Marshal(pageToken{
+		SortBy:     req.SortBy,
+		Descending: req.Descending,
+		Filter:     filterFingerprint(req.Filter),
+		Cursor:     cursor,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodePageToken - разбирает токен страницы и проверяет, что он выдан для тех же параметров выборки
+func decodePageToken(req ProductsRequest) (*repository.ProductCursor, error) {
+	if req.PageToken == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(req.PageToken)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	var token pageToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	if token.SortBy != req.SortBy || token.Descending != req.Descending ||
+		token.Filter != filterFingerprint(req.Filter) {
+		return nil, ErrInvalidPageToken
+	}
+	if _, err := uuid.Parse(token.Cursor.ID); err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	return &token.Cursor, nil
+}
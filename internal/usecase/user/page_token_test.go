@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"user-service/internal/repository"
+)
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	expiresAt := time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		req    ProductsRequest
+		cursor repository.ProductCursor
+	}{
+		{
+			name:   "default sort",
+			req:    ProductsRequest{},
+			cursor: repository.ProductCursor{CreatedAt: time.Date(2026, time.April, 1, 10, 0, 0, 123, time.UTC), ID: uuid.NewString()},
+		},
+		{
+			name:   "name with filter",
+			req:    ProductsRequest{SortBy: repository.SortByName, Filter: repository.ProductFilter{NamePrefix: "mi", Category: "dairy"}},
+			cursor: repository.ProductCursor{Name: "milk", ID: uuid.NewString()},
+		},
+		{
+			name:   "expiry descending",
+			req:    ProductsRequest{SortBy: repository.SortByExpiresAt, Descending: true, Filter: repository.ProductFilter{ExpiringBefore: &expiresAt}},
+			cursor: repository.ProductCursor{ExpiresAt: &expiresAt, ID: uuid.NewString()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := encodePageToken(tt.req, tt.cursor)
+			if err != nil {
+				t.Fatalf("encodePageToken: %v", err)
+			}
+			req := tt.req
+			req.PageToken = token
+			cursor, err := decodePageToken(req)
+			if err != nil {
+				t.Fatalf("decodePageToken: %v", err)
+			}
+			if cursor.Name != tt.cursor.Name || !cursor.CreatedAt.Equal(tt.cursor.CreatedAt) || cursor.ID != tt.cursor.ID ||
+				(cursor.ExpiresAt == nil) != (tt.cursor.ExpiresAt == nil) ||
+				cursor.ExpiresAt != nil && !cursor.ExpiresAt.Equal(*tt.cursor.ExpiresAt) {
+				t.Errorf("got cursor %+v, want %+v", *cursor, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodePageTokenRejects(t *testing.T) {
+	expiresAt := time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)
+	issuedFor := ProductsRequest{SortBy: repository.SortByName, Filter: repository.ProductFilter{NamePrefix: "mi"}}
+	token, err := encodePageToken(issuedFor, repository.ProductCursor{Name: "milk", ID: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("encodePageToken: %v", err)
+	}
+	badCursor, err := encodePageToken(issuedFor, repository.ProductCursor{Name: "milk", ID: "42"})
+	if err != nil {
+		t.Fatalf("encodePageToken: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  ProductsRequest
+	}{
+		{name: "other sort field", req: ProductsRequest{PageToken: token, SortBy: repository.SortByCreatedAt, Filter: issuedFor.Filter}},
+		{name: "other direction", req: ProductsRequest{PageToken: token, SortBy: issuedFor.SortBy, Descending: true, Filter: issuedFor.Filter}},
+		{name: "other name prefix", req: ProductsRequest{PageToken: token, SortBy: issuedFor.SortBy, Filter: repository.ProductFilter{NamePrefix: "m"}}},
+		{name: "added expiry filter", req: ProductsRequest{PageToken: token, SortBy: issuedFor.SortBy, Filter: repository.ProductFilter{NamePrefix: "mi", ExpiringBefore: &expiresAt}}},
+		{name: "not base64", req: ProductsRequest{PageToken: "not a token!", SortBy: issuedFor.SortBy, Filter: issuedFor.Filter}},
+		{name: "not json", req: ProductsRequest{PageToken: base64.RawURLEncoding.EncodeToString([]byte("milk")), SortBy: issuedFor.SortBy, Filter: issuedFor.Filter}},
+		{name: "cursor id is not a uuid", req: ProductsRequest{PageToken: badCursor, SortBy: issuedFor.SortBy, Filter: issuedFor.Filter}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodePageToken(tt.req); !errors.Is(err, ErrInvalidPageToken) {
+				t.Errorf("got %v, want ErrInvalidPageToken", err)
+			}
+		})
+	}
+}
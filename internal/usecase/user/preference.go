@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"unicode/utf8"
+
+	"user-service/internal/auth"
+	"user-service/internal/entity"
+	"user-service/internal/repository"
+	"user-service/internal/tracing"
+)
+
+var (
+	// ErrInvalidPreferenceKind - ошибка, когда тип предпочтения не указан или неизвестен
+	ErrInvalidPreferenceKind = errors.New("preference kind is invalid")
+	// ErrPreferenceValueTooLong - ошибка, когда значение предпочтения длиннее maxPreferenceValueLength
+	ErrPreferenceValueTooLong = errors.New("preference value must be at most 255 characters")
+	// ErrTooManyPreferences - ошибка, когда значений одного типа больше maxPreferencesPerKind
+	ErrTooManyPreferences = errors.New("too many preference values of one kind")
+	// ErrInvalidNutritionTarget - ошибка, когда цель по калориям или БЖУ отрицательная
+	ErrInvalidNutritionTarget = errors.New("nutrition target must not be negative")
+	// ErrNutritionTargetTooLarge - ошибка, когда цель больше maxCaloriesTarget или maxNutrientTarget
+	ErrNutritionTargetTooLarge = errors.New("nutrition target is too large")
+)
+
+const (
+	// maxPreferenceValueLength - максимальная длина значения, совпадает с колонкой user_preference_entries.value
+	maxPreferenceValueLength = 255
+	// maxPreferencesPerKind - максимальное количество значений одного типа у пользователя
+	maxPreferencesPerKind = 100
+	// maxCaloriesTarget - максимальная суточная цель по калориям, с запасом выше любой реальной
+	maxCaloriesTarget = 100000
+	// maxNutrientTarget - максимальная цель по БЖУ в граммах, совпадает с колонками NUMERIC(7, 2)
+	maxNutrientTarget = 99999.99
+)
+
+func (u *user) GetUserPreference(ctx context.Context) (preferences entity.Preferences, err error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.GetUserPreference")
+	defer tracing.End(span, &err)
+
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return entity.Preferences{}, err
+	}
+
+	preferences, err = u.userRepo.GetPreference(ctx, userId.String())
+	if err != nil {
+		return entity.Preferences{}, err
+	}
+
+	return preferences, nil
+}
+
+func (u *user) UpdateUserPreference(ctx context.Context, preferences entity.Preferences) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.UpdateUserPreference")
+	defer tracing.End(span, &err)
+
+	preferences, err = normalizePreferences(preferences)
+	if err != nil {
+		return err
+	}
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return err
+	}
+	return u.userRepo.WithTx(ctx, func(repo repository.Repository) error {
+		if err := repo.UpdatePreference(ctx, userId.String(), preferences); err != nil {
+			return err
+		}
+		return addPreferenceChanged(ctx, repo, userId.String(), preferences)
+	})
+}
+
+func (u *user) RemoveUserPreference(ctx context.Context) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.RemoveUserPreference")
+	defer tracing.End(span, &err)
+
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return err
+	}
+	return u.userRepo.WithTx(ctx, func(repo repository.Repository) error {
+		if err := repo.RemovePreference(ctx, userId.String()); err != nil {
+			return err
+		}
+		return addPreferenceChanged(ctx, repo, userId.String(), entity.Preferences{})
+	})
+}
+
+func (u *user) AddUserPreferenceEntry(ctx context.Context, entry entity.PreferenceEntry) (preferences entity.Preferences, err error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.AddUserPreferenceEntry")
+	defer tracing.End(span, &err)
+
+	entry, err = normalizePreferenceEntry(entry)
+	if err != nil {
+		return entity.Preferences{}, err
+	}
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return entity.Preferences{}, err
+	}
+	// Проверка лимита, добавление и чтение набора выполняются в одной транзакции,
+	// чтобы параллельные запросы не превысили maxPreferencesPerKind
+	err = u.userRepo.WithTx(ctx, func(repo repository.Repository) error {
+		current, err := repo.GetPreference(ctx, userId.String())
+		if err != nil && !errors.Is(err, repository.ErrPreferenceNotFound) {
+			return err
+		}
+		if countPreferences(current, entry.Kind) >= maxPreferencesPerKind {
+			return &FieldError{Field: "value", Err: ErrTooManyPreferences}
+		}
+		if err := repo.AddPreferenceEntry(ctx, userId.String(), entry); err != nil {
+			return err
+		}
+		preferences, err = repo.GetPreference(ctx, userId.String())
+		if err != nil {
+			return err
+		}
+		return addPreferenceChanged(ctx, repo, userId.String(), preferences)
+	}, repository.WithIsolation(repository.Serializable))
+	if err != nil {
+		return entity.Preferences{}, err
+	}
+
+	return preferences, nil
+}
+
+func (u *user) RemoveUserPreferenceEntry(ctx context.Context, entry entity.PreferenceEntry) (preferences entity.Preferences, err error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.RemoveUserPreferenceEntry")
+	defer tracing.End(span, &err)
+
+	entry, err = normalizePreferenceEntry(entry)
+	if err != nil {
+		return entity.Preferences{}, err
+	}
+	userId, err := auth.UserID(ctx)
+	if err != nil {
+		return entity.Preferences{}, err
+	}
+	// Набор читается в той же транзакции, чтобы ответ соответствовал результату удаления
+	err = u.userRepo.WithTx(ctx, func(repo repository.Repository) error {
+		if err := repo.RemovePreferenceEntry(ctx, userId.String(), entry); err != nil {
+			return err
+		}
+		preferences, err = repo.GetPreference(ctx, userId.String())
+		if errors.Is(err, repository.ErrPreferenceNotFound) {
+			preferences = entity.Preferences{}
+		} else if err != nil {
+			return err
+		}
+		return addPreferenceChanged(ctx, repo, userId.String(), preferences)
+	}, repository.WithIsolation(repository.Serializable))
+	if err != nil {
+		return entity.Preferences{}, err
+	}
+
+	return preferences, nil
+}
+
+// addPreferenceChanged - записывает событие PreferenceChanged в транзакции repo
+func addPreferenceChanged(ctx context.Context, repo repository.Repository, userId string, preferences entity.Preferences) error {
+	event, err := preferenceChanged(userId, preferences)
+	if err != nil {
+		return err
+	}
+	return repo.AddEvents(ctx, event)
+}
+
+// countPreferences - количество значений типа kind в наборе
+func countPreferences(preferences entity.Preferences, kind entity.PreferenceKind) int {
+	count := 0
+	for _, entry := range preferences.Entries() {
+		if entry.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// normalizePreferenceEntry - проверяет одно значение предпочтения и приводит его к единому виду
+func normalizePreferenceEntry(entry entity.PreferenceEntry) (entity.PreferenceEntry, error) {
+	switch entry.Kind {
+	case entity.PreferenceDiet, entity.PreferenceAllergen, entity.PreferenceDislikedIngredient:
+	default:
+		return entry, &FieldError{Field: "kind", Err: ErrInvalidPreferenceKind}
+	}
+	entry.Value = entity.NormalizePreferenceValue(entry.Value)
+	if entry.Value == "" {
+		return entry, &FieldError{Field: "value", Err: ErrEmptyPreferenceName}
+	}
+	if utf8.RuneCountInString(entry.Value) > maxPreferenceValueLength {
+		return entry, &FieldError{Field: "value", Err: ErrPreferenceValueTooLong}
+	}
+	return entry, nil
+}
+
+// normalizePreferences - проверяет набор предпочтений, приводит значения к единому виду
+// и убирает повторы
+func normalizePreferences(preferences entity.Preferences) (entity.Preferences, error) {
+	targets := preferences.Targets
+	if err := validateNutritionTargets(targets); err != nil {
+		return preferences, err
+	}
+
+	normalized := entity.Preferences{Targets: targets}
+	for _, entry := range preferences.Entries() {
+		entry, err := normalizePreferenceEntry(entry)
+		if err != nil {
+			return preferences, err
+		}
+		normalized.Add(entry)
+	}
+
+	fields := []struct {
+		name   string
+		values *[]string
+	}{
+		{"diets", &normalized.Diets},
+		{"allergens", &normalized.Allergens},
+		{"disliked_ingredients", &normalized.DislikedIngredients},
+	}
+	for _, field := range fields {
+		slices.Sort(*field.values)
+		*field.values = slices.Compact(*field.values)
+		if len(*field.values) > maxPreferencesPerKind {
+			return preferences, &FieldError{Field: field.name, Err: ErrTooManyPreferences}
+		}
+	}
+	return normalized, nil
+}
+
+// validateNutritionTargets - проверяет, что цели не отрицательные и помещаются в колонки хранилища.
+// Граммы сравниваются после округления до сотых, как их сохраняет NUMERIC(7, 2).
+func validateNutritionTargets(targets entity.NutritionTargets) error {
+	if targets.Calories < 0 {
+		return &FieldError{Field: "targets.calories", Err: ErrInvalidNutritionTarget}
+	}
+	if targets.Calories > maxCaloriesTarget {
+		return &FieldError{Field: "targets.calories", Err: ErrNutritionTargetTooLarge}
+	}
+
+	nutrients := []struct {
+		name  string
+		value float64
+	}{
+		{"targets.protein_g", targets.ProteinG},
+		{"targets.fat_g", targets.FatG},
+		{"targets.carbs_g", targets.CarbsG},
+	}
+	for _, nutrient := range nutrients {
+		// NaN не проходит ни одно сравнение, поэтому проверка записана через отрицание
+		if !(nutrient.value >= 0) {
+			return &FieldError{Field: nutrient.name, Err: ErrInvalidNutritionTarget}
+		}
+		if math.Round(nutrient.value*100)/100 > maxNutrientTarget {
+			return &FieldError{Field: nutrient.name, Err: ErrNutritionTargetTooLarge}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"user-service/internal/entity"
+)
+
+func TestValidateNutritionTargets(t *testing.T) {
+	tests := []struct {
+		name      string
+		targets   entity.NutritionTargets
+		wantField string
+		wantErr   error
+	}{
+		{name: "not set", targets: entity.NutritionTargets{}},
+		{name: "upper bounds", targets: entity.NutritionTargets{Calories: 100000, ProteinG: 99999.99, FatG: 99999.99, CarbsG: 99999.994}},
+		{name: "negative calories", targets: entity.NutritionTargets{Calories: -1}, wantField: "targets.calories", wantErr: ErrInvalidNutritionTarget},
+		{name: "too many calories", targets: entity.NutritionTargets{Calories: 100001}, wantField: "targets.calories", wantErr: ErrNutritionTargetTooLarge},
+		{name: "negative fat", targets: entity.NutritionTargets{FatG: -0.01}, wantField: "targets.fat_g", wantErr: ErrInvalidNutritionTarget},
+		{name: "protein above precision", targets: entity.NutritionTargets{ProteinG: 100000}, wantField: "targets.protein_g", wantErr: ErrNutritionTargetTooLarge},
+		{name: "carbs rounded above precision", targets: entity.NutritionTargets{CarbsG: 99999.996}, wantField: "targets.carbs_g", wantErr: ErrNutritionTargetTooLarge},
+		{name: "infinite fat", targets: entity.NutritionTargets{FatG: math.Inf(1)}, wantField: "targets.fat_g", wantErr: ErrNutritionTargetTooLarge},
+		{name: "NaN protein", targets: entity.NutritionTargets{ProteinG: math.NaN()}, wantField: "targets.protein_g", wantErr: ErrInvalidNutritionTarget},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNutritionTargets(tt.targets)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("got %v, want FieldError", err)
+			}
+			if fieldErr.Field != tt.wantField || !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %s: %v, want %s: %v", fieldErr.Field, fieldErr.Err, tt.wantField, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,136 +3,336 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
-	"user-service/internal/adapter/token"
+	"go.opentelemetry.io/otel"
+	"user-service/internal/auth"
+	"user-service/internal/entity"
+	"user-service/internal/metrics"
 	"user-service/internal/repository"
+	"user-service/internal/tracing"
 )
 
+// tracer - источник спанов usecase-слоя
+var tracer = otel.Tracer("user-service/internal/usecase/user")
+
 // Список ошибок
 var (
 	// ErrInvalidToken - ошибка, когда токен недействителен
-	ErrInvalidToken = errors.New("invalid token")
+	ErrInvalidToken = auth.ErrInvalidToken
+	// ErrEmptyProductName - ошибка, когда не передано название продукта
+	ErrEmptyProductName = errors.New("product name is empty")
+	// ErrEmptyPreferenceName - ошибка, когда не передано название предпочтения
+	ErrEmptyPreferenceName = errors.New("preference name is empty")
+	// ErrInvalidQuantity - ошибка, когда количество продукта отрицательное или не является числом
+	ErrInvalidQuantity = errors.New("quantity must not be negative")
+	// ErrInvalidProductId - ошибка, когда идентификатор продукта не является UUID
+	ErrInvalidProductId = errors.New("product id is not a valid uuid")
+	// ErrEmptyProductRef - ошибка, когда не передан ни идентификатор, ни название продукта
+	ErrEmptyProductRef = errors.New("product id or product name is required")
+	// ErrInvalidPageSize - ошибка, когда размер страницы отрицательный
+	ErrInvalidPageSize = errors.New("page size must not be negative")
+	// ErrInvalidPageToken - ошибка, когда токен страницы поврежден или выдан для других параметров выборки
+	ErrInvalidPageToken = errors.New("page token is invalid")
+)
+
+const (
+	// defaultPageSize - размер страницы продуктов, если клиент его не указал
+	defaultPageSize = 50
+	// maxPageSize - максимальный размер страницы продуктов
+	maxPageSize = 500
+)
+
+// ProductsRequest - параметры запроса списка продуктов
+type ProductsRequest struct {
+	// PageSize - размер страницы, 0 означает размер по умолчанию
+	PageSize int
+	// PageToken - токен страницы из предыдущего ответа, пустой для первой страницы
+	PageToken  string
+	Filter     repository.ProductFilter
+	SortBy     repository.ProductSortField
+	Descending bool
+}
+
+// ProductsPage - страница списка продуктов
+type ProductsPage struct {
+	Products []entity.Product
+	// NextPageToken - токен следующей страницы, пустой если страница последняя
+	NextPageToken string
+}
+
+// defaultQuantity - количество, которое подставляется, если клиент его не указал
+const defaultQuantity = 1
+
+const (
+	// maxProductNameLength - максимальная длина названия продукта, совпадает с колонкой user_products.product_name
+	maxProductNameLength = 255
+	// maxQuantity - максимальное количество продукта, которое помещается в колонку user_products.quantity NUMERIC(12, 3)
+	maxQuantity = 999999999.999
+	// maxUnitLength - максимальная длина единицы измерения, совпадает с колонкой user_products.unit
+	maxUnitLength = 32
+	// maxCategoryLength - максимальная длина категории, совпадает с колонкой user_products.category
+	maxCategoryLength = 64
 )
 
+var (
+	// ErrProductNameTooLong - ошибка, когда название продукта длиннее maxProductNameLength
+	ErrProductNameTooLong = fmt.Errorf("product name must be at most %d characters", maxProductNameLength)
+	// ErrQuantityTooLarge - ошибка, когда количество продукта больше maxQuantity
+	ErrQuantityTooLarge = errors.New("quantity is too large")
+	// ErrUnitTooLong - ошибка, когда единица измерения длиннее maxUnitLength
+	ErrUnitTooLong = fmt.Errorf("unit must be at most %d characters", maxUnitLength)
+	// ErrCategoryTooLong - ошибка, когда категория длиннее maxCategoryLength
+	ErrCategoryTooLong = fmt.Errorf("category must be at most %d characters", maxCategoryLength)
+)
+
+// FieldError - ошибка валидации конкретного поля запроса
+type FieldError struct {
+	// Field - имя поля в запросе
+	Field string
+	// Err - причина ошибки
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
 var _ UserUseCase = (*user)(nil)
 
 // UserUsecase - интерфейс для работы с пользователями
 type UserUseCase interface {
-	// GetUserProducts - получить список продуктов пользователя
-	GetUserProducts(accessToken string) (products []string, err error)
-	// GetUserPreference - получить предпочтения пользователя
-	GetUserPreference(accessToken string) (preferenceName string, err error)
-	// UpdateUserPreference - обновить предпочтения пользователя
-	UpdateUserPreference(accessToken string, preferenceName string) (err error)
-	// RemoveUserPreference - удалить предпочтения пользователя
-	RemoveUserPreference(accessToken string) (err error)
+	// GetUserProducts - получить страницу продуктов пользователя
+	GetUserProducts(ctx context.Context, req ProductsRequest) (page ProductsPage, err error)
+	// GetUserPreference - получить набор предпочтений пользователя
+	GetUserPreference(ctx context.Context) (preferences entity.Preferences, err error)
+	// UpdateUserPreference - заменить набор предпочтений пользователя целиком
+	UpdateUserPreference(ctx context.Context, preferences entity.Preferences) (err error)
+	// RemoveUserPreference - удалить все предпочтения пользователя
+	RemoveUserPreference(ctx context.Context) (err error)
+	// AddUserPreferenceEntry - добавить одно значение предпочтения, возвращает обновленный набор
+	AddUserPreferenceEntry(ctx context.Context, entry entity.PreferenceEntry) (preferences entity.Preferences, err error)
+	// RemoveUserPreferenceEntry - удалить одно значение предпочтения, возвращает обновленный набор
+	RemoveUserPreferenceEntry(ctx context.Context, entry entity.PreferenceEntry) (preferences entity.Preferences, err error)
 	// AddUserProduct - добавить продукт пользователю
-	AddUserProduct(accessToken string, productName string) (err error)
-	// RemoveUserProduct - удалить продукт у пользователя
-	RemoveUserProduct(accessToken string, productName string) (err error)
+	AddUserProduct(ctx context.Context, product entity.Product) (added entity.Product, err error)
+	// RemoveUserProduct - удалить продукт у пользователя по идентификатору или, если он не задан, по названию
+	RemoveUserProduct(ctx context.Context, productId string, productName string) (err error)
+	// BatchAddUserProducts - добавить несколько продуктов в одной транзакции
+	BatchAddUserProducts(ctx context.Context, products []entity.Product, allOrNothing bool) (response BatchResponse, err error)
+	// BatchRemoveUserProducts - удалить несколько продуктов по идентификаторам в одной транзакции
+	BatchRemoveUserProducts(ctx context.Context, productIds []string, allOrNothing bool) (response BatchResponse, err error)
 }
 
 type user struct {
-	userRepo     repository.Repository
-	tokenService token.Token
+	userRepo    repository.Repository
+	catalogRepo repository.CatalogRepository
+	log         *slog.Logger
 }
 
-// New - конструктор для создания нового экземпляра UserUsecase
-func New(userRepo repository.Repository, tokenService token.Token) *user {
+// New - конструктор для создания нового экземпляра UserUsecase.
+// Пользователь запроса берется из контекста, куда его кладет auth-интерсептор.
+func New(userRepo repository.Repository, catalogRepo repository.CatalogRepository, log *slog.Logger) *user {
 	return &user{
-		userRepo:     userRepo,
-		tokenService: tokenService,
+		userRepo:    userRepo,
+		catalogRepo: catalogRepo,
+		log:         log,
 	}
 }
 
-func (u *user) GetUserProducts(accessToken string) (products []string, err error) {
-	userId, err := u.extractUserIdFromToken(accessToken)
-	if err != nil {
-		return nil, err
+func (u *user) GetUserProducts(ctx context.Context, req ProductsRequest) (page ProductsPage, err error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.GetUserProducts")
+	defer tracing.End(span, &err)
+
+	if req.PageSize < 0 {
+		return ProductsPage{}, &FieldError{Field: "page_size", Err: ErrInvalidPageSize}
 	}
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
 
-	products, err = u.userRepo.GetProducts(context.Background(), userId.String())
+	cursor, err := decodePageToken(req)
 	if err != nil {
-		return nil, err
+		return ProductsPage{}, &FieldError{Field: "page_token", Err: err}
 	}
 
-	return products, nil
-}
-
-func (u *user) GetUserPreference(accessToken string) (preferenceName string, err error) {
-	userId, err := u.extractUserIdFromToken(accessToken)
+	userId, err := auth.UserID(ctx)
 	if err != nil {
-		return "", err
+		return ProductsPage{}, err
 	}
 
-	preferenceName, err = u.userRepo.GetPreference(context.Background(), userId.String())
+	result, err := u.userRepo.GetProducts(ctx, userId.String(), repository.ProductQuery{
+		Filter:     req.Filter,
+		SortBy:     req.SortBy,
+		Descending: req.Descending,
+		Limit:      pageSize,
+		After:      cursor,
+	})
 	if err != nil {
-		return "", err
+		return ProductsPage{}, err
 	}
 
-	return preferenceName, nil
-}
-
-func (u *user) UpdateUserPreference(accessToken string, preferenceName string) (err error) {
-	userId, err := u.extractUserIdFromToken(accessToken)
-	if err != nil {
-		return err
+	page.Products = result.Products
+	if result.Next != nil {
+		page.NextPageToken, err = encodePageToken(req, *result.Next)
+		if err != nil {
+			return ProductsPage{}, err
+		}
 	}
-	err = u.userRepo.UpdatePreference(context.Background(), userId.String(), preferenceName)
 
-	return err
+	return page, nil
 }
 
-func (u *user) RemoveUserPreference(accessToken string) (err error) {
-	userId, err := u.extractUserIdFromToken(accessToken)
+func (u *user) AddUserProduct(ctx context.Context, product entity.Product) (added entity.Product, err error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.AddUserProduct")
+	defer tracing.End(span, &err)
+
+	product, err = normalizeProduct(product)
 	if err != nil {
-		return err
+		return entity.Product{}, err
 	}
-	err = u.userRepo.RemovePreference(context.Background(), userId.String())
+	userId, err := auth.UserID(ctx)
 	if err != nil {
-		return err
+		return entity.Product{}, err
 	}
-
-	return nil
-}
-
-func (u *user) AddUserProduct(accessToken string, productName string) (err error) {
-	userId, err := u.extractUserIdFromToken(accessToken)
+	product, err = u.resolveProduct(ctx, product)
 	if err != nil {
-		return err
+		return entity.Product{}, err
 	}
-	err = u.userRepo.AddProduct(context.Background(), userId.String(), productName)
+	// Продукт и событие ProductAdded сохраняются в одной транзакции
+	err = u.userRepo.WithTx(ctx, func(repo repository.Repository) error {
+		saved, err := repo.AddProduct(ctx, userId.String(), product)
+		if err != nil {
+			return err
+		}
+		event, err := productAdded(userId.String(), saved)
+		if err != nil {
+			return err
+		}
+		added = saved
+		return repo.AddEvents(ctx, event)
+	})
 	if err != nil {
-		return err
+		return entity.Product{}, err
 	}
+	metrics.ProductsAdded(1)
 
-	return nil
+	return added, nil
 }
 
-func (u *user) RemoveUserProduct(accessToken string, productName string) (err error) {
-	userId, err := u.extractUserIdFromToken(accessToken)
+func (u *user) RemoveUserProduct(ctx context.Context, productId string, productName string) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.RemoveUserProduct")
+	defer tracing.End(span, &err)
+
+	productName = strings.TrimSpace(productName)
+	if productId == "" && productName == "" {
+		return &FieldError{Field: "product_id", Err: ErrEmptyProductRef}
+	}
+	if productId != "" {
+		if _, err := uuid.Parse(productId); err != nil {
+			return &FieldError{Field: "product_id", Err: ErrInvalidProductId}
+		}
+	}
+	userId, err := auth.UserID(ctx)
 	if err != nil {
 		return err
 	}
-	err = u.userRepo.RemoveProduct(context.Background(), userId.String(), productName)
+	if productId == "" {
+		// Продукт сохранен под каноническим названием каталога, поэтому название
+		// из запроса сопоставляется с каталогом так же, как при добавлении
+		resolved, err := u.resolveProduct(ctx, entity.Product{Name: productName})
+		if err != nil {
+			return err
+		}
+		productName = resolved.Name
+	}
+	// По названию удаляются все партии продукта, в метрику идет число удаленных записей
+	var removed int64
+	err = u.userRepo.WithTx(ctx, func(repo repository.Repository) error {
+		var err error
+		if productId != "" {
+			removed = 1
+			err = repo.RemoveProduct(ctx, userId.String(), productId)
+		} else {
+			removed, err = repo.RemoveProductByName(ctx, userId.String(), productName)
+		}
+		if err != nil {
+			return err
+		}
+		event, err := productRemoved(userId.String(), productId, productName)
+		if err != nil {
+			return err
+		}
+		return repo.AddEvents(ctx, event)
+	})
 	if err != nil {
 		return err
 	}
+	metrics.ProductsRemoved(int(removed))
 
 	return nil
 }
 
-// extractUserIdFromToken - функция для извлечения id пользователя из токена
-func (u *user) extractUserIdFromToken(accessToken string) (uuid.UUID, error) {
-	valid, userId, err := u.tokenService.ValidateToken(accessToken)
-
-	if !valid {
-		return uuid.Nil, err
+// resolveProduct - сопоставляет продукт с записью каталога по названию или синониму.
+// Если запись не найдена, продукт сохраняется как пользовательский.
+func (u *user) resolveProduct(ctx context.Context, product entity.Product) (entity.Product, error) {
+	catalogProduct, err := u.catalogRepo.ResolveProduct(ctx, product.Name)
+	if errors.Is(err, repository.ErrCatalogProductNotFound) {
+		u.log.DebugContext(ctx, "Product not found in catalog, saving as custom")
+		return product, nil
 	}
-
 	if err != nil {
-		return uuid.Nil, err
+		return entity.Product{}, err
+	}
+	u.log.DebugContext(ctx, "Product resolved from catalog", "catalog_product_id", catalogProduct.ID)
+	product.CatalogProductID = catalogProduct.ID
+	product.Name = catalogProduct.CanonicalName
+	if product.Category == "" {
+		product.Category = catalogProduct.Category
+	}
+	return product, nil
+}
+
+// normalizeProduct - проверяет продукт из запроса и подставляет значения по умолчанию.
+// Значения должны помещаться в колонки хранилища: иначе ошибка базы выглядела бы для клиента
+// как недоступность хранилища, и он повторял бы тот же запрос.
+func normalizeProduct(product entity.Product) (entity.Product, error) {
+	product.Name = strings.TrimSpace(product.Name)
+	product.Unit = strings.TrimSpace(product.Unit)
+	product.Category = strings.TrimSpace(product.Category)
+	if product.Name == "" {
+		return product, &FieldError{Field: "product_name", Err: ErrEmptyProductName}
+	}
+	if utf8.RuneCountInString(product.Name) > maxProductNameLength {
+		return product, &FieldError{Field: "product_name", Err: ErrProductNameTooLong}
+	}
+	// NaN не проходит ни одно сравнение, поэтому проверка записана через отрицание
+	if !(product.Quantity >= 0) {
+		return product, &FieldError{Field: "quantity", Err: ErrInvalidQuantity}
+	}
+	// Количество сравнивается после округления до тысячных, как его сохраняет NUMERIC(12, 3)
+	if math.IsInf(product.Quantity, 0) || math.Round(product.Quantity*1000)/1000 > maxQuantity {
+		return product, &FieldError{Field: "quantity", Err: ErrQuantityTooLarge}
+	}
+	if product.Quantity == 0 {
+		product.Quantity = defaultQuantity
+	}
+	if utf8.RuneCountInString(product.Unit) > maxUnitLength {
+		return product, &FieldError{Field: "unit", Err: ErrUnitTooLong}
+	}
+	if utf8.RuneCountInString(product.Category) > maxCategoryLength {
+		return product, &FieldError{Field: "category", Err: ErrCategoryTooLong}
 	}
-	return userId, nil
+	return product, nil
 }
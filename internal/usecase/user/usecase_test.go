@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"user-service/internal/auth"
+	"user-service/internal/entity"
+	"user-service/internal/repository"
+)
+
+func TestRemoveUserProductByName(t *testing.T) {
+	tests := []struct {
+		name string
+		// productName - название из запроса на удаление
+		productName string
+	}{
+		{name: "canonical name", productName: "Milk"},
+		{name: "surrounding spaces", productName: "  Milk \t"},
+		{name: "catalog alias", productName: "молоко"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := auth.WithIdentity(context.Background(), auth.Identity{UserID: uuid.New()})
+			repo := repository.NewMemory(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			if _, err := repo.UpsertProduct(ctx, entity.CatalogProduct{CanonicalName: "Milk", Aliases: []string{"молоко"}}); err != nil {
+				t.Fatalf("UpsertProduct: %v", err)
+			}
+			usecase := New(repo, repo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			if _, err := usecase.AddUserProduct(ctx, entity.Product{Name: "молоко"}); err != nil {
+				t.Fatalf("AddUserProduct: %v", err)
+			}
+
+			if err := usecase.RemoveUserProduct(ctx, "", tt.productName); err != nil {
+				t.Fatalf("RemoveUserProduct(%q): %v", tt.productName, err)
+			}
+
+			var events []entity.Event
+			_, err := repo.PublishEvents(ctx, 10, func(_ context.Context, batch []entity.Event) (int, error) {
+				events = batch
+				return len(batch), nil
+			})
+			if err != nil {
+				t.Fatalf("PublishEvents: %v", err)
+			}
+			if len(events) != 2 || events[1].Type != entity.EventProductRemoved {
+				t.Fatalf("got events %+v, want ProductAdded and ProductRemoved", events)
+			}
+			var payload productEvent
+			if err := json.Unmarshal(events[1].Payload, &payload); err != nil {
+				t.Fatalf("failed to decode event payload: %v", err)
+			}
+			if payload.Name != "Milk" {
+				t.Errorf("ProductRemoved name = %q, want canonical name %q", payload.Name, "Milk")
+			}
+		})
+	}
+}
+
+func TestAddUserProductValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		product entity.Product
+		// wantField - поле FieldError, пустое если продукт корректен
+		wantField string
+		wantErr   error
+	}{
+		{name: "valid", product: entity.Product{Name: "Milk", Quantity: 2}},
+		{name: "longest name", product: entity.Product{Name: strings.Repeat("я", maxProductNameLength)}},
+		{name: "largest quantity", product: entity.Product{Name: "Rice", Quantity: maxQuantity}},
+		{name: "empty name", product: entity.Product{Name: " \t"}, wantField: "product_name", wantErr: ErrEmptyProductName},
+		{name: "name too long", product: entity.Product{Name: strings.Repeat("я", maxProductNameLength+1)},
+			wantField: "product_name", wantErr: ErrProductNameTooLong},
+		{name: "negative quantity", product: entity.Product{Name: "Milk", Quantity: -1},
+			wantField: "quantity", wantErr: ErrInvalidQuantity},
+		{name: "NaN quantity", product: entity.Product{Name: "Milk", Quantity: math.NaN()},
+			wantField: "quantity", wantErr: ErrInvalidQuantity},
+		{name: "negative infinite quantity", product: entity.Product{Name: "Milk", Quantity: math.Inf(-1)},
+			wantField: "quantity", wantErr: ErrInvalidQuantity},
+		{name: "infinite quantity", product: entity.Product{Name: "Milk", Quantity: math.Inf(1)},
+			wantField: "quantity", wantErr: ErrQuantityTooLarge},
+		{name: "quantity too large", product: entity.Product{Name: "Milk", Quantity: 1e9},
+			wantField: "quantity", wantErr: ErrQuantityTooLarge},
+		{name: "quantity rounds above limit", product: entity.Product{Name: "Milk", Quantity: 999999999.9996},
+			wantField: "quantity", wantErr: ErrQuantityTooLarge},
+		{name: "unit too long", product: entity.Product{Name: "Milk", Unit: strings.Repeat("l", maxUnitLength+1)},
+			wantField: "unit", wantErr: ErrUnitTooLong},
+		{name: "category too long", product: entity.Product{Name: "Milk", Category: strings.Repeat("c", maxCategoryLength+1)},
+			wantField: "category", wantErr: ErrCategoryTooLong},
+	}
+
+	checkErr := func(t *testing.T, err error, wantField string, wantErr error) {
+		t.Helper()
+		if wantErr == nil {
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			return
+		}
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Field != wantField || !errors.Is(err, wantErr) {
+			t.Fatalf("got error %v, want FieldError{%s: %v}", err, wantField, wantErr)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := auth.WithIdentity(context.Background(), auth.Identity{UserID: uuid.New()})
+			repo := repository.NewMemory(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			usecase := New(repo, repo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			_, err := usecase.AddUserProduct(ctx, tt.product)
+			checkErr(t, err, tt.wantField, tt.wantErr)
+
+			// Пакет добавляется другому пользователю, чтобы корректный продукт не оказался дубликатом
+			ctx = auth.WithIdentity(context.Background(), auth.Identity{UserID: uuid.New()})
+			response, err := usecase.BatchAddUserProducts(ctx, []entity.Product{tt.product}, false)
+			if err != nil {
+				t.Fatalf("BatchAddUserProducts: %v", err)
+			}
+			checkErr(t, response.Results[0].Err, tt.wantField, tt.wantErr)
+		})
+	}
+}
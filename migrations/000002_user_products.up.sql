@@ -3,5 +3,5 @@ CREATE TABLE IF NOT EXISTS user_products (
     user_id INT NOT NULL,
     product_name VARCHAR(255), 
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
\ No newline at end of file
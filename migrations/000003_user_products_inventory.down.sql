@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS user_products_user_id_product_name_idx;
+
+ALTER TABLE user_products
+    DROP COLUMN expires_at,
+    DROP COLUMN unit,
+    DROP COLUMN quantity,
+    DROP COLUMN id,
+    ALTER COLUMN product_name DROP NOT NULL;
+
+ALTER TABLE user_products ADD COLUMN id SERIAL PRIMARY KEY;
\ No newline at end of file
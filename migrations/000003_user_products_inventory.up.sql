@@ -0,0 +1,27 @@
+ALTER TABLE user_products DROP COLUMN id;
+
+ALTER TABLE user_products
+    ADD COLUMN id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ADD COLUMN quantity NUMERIC(12, 3) NOT NULL DEFAULT 1 CHECK (quantity >= 0),
+    ADD COLUMN unit VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN expires_at TIMESTAMP,
+    ALTER COLUMN product_name SET NOT NULL;
+
+-- Повторы одного продукта из старой схемы объединяются в одну запись с количеством по числу повторов
+WITH copies AS (
+    SELECT id,
+        count(*) OVER (PARTITION BY user_id, product_name) AS total,
+        row_number() OVER (PARTITION BY user_id, product_name ORDER BY created_at, id) AS n
+    FROM user_products
+)
+UPDATE user_products p SET quantity = c.total FROM copies c WHERE p.id = c.id AND c.n = 1;
+
+DELETE FROM user_products p
+USING (
+    SELECT id, row_number() OVER (PARTITION BY user_id, product_name ORDER BY created_at, id) AS n
+    FROM user_products
+) c
+WHERE p.id = c.id AND c.n > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_products_user_id_product_name_idx
+    ON user_products (user_id, product_name);
\ No newline at end of file
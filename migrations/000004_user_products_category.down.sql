@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS user_products_user_id_category_idx;
+DROP INDEX IF EXISTS user_products_user_id_expires_at_id_idx;
+DROP INDEX IF EXISTS user_products_user_id_created_at_id_idx;
+DROP INDEX IF EXISTS user_products_user_id_name_id_idx;
+
+ALTER TABLE user_products DROP COLUMN category;
\ No newline at end of file
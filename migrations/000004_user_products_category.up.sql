@@ -0,0 +1,11 @@
+ALTER TABLE user_products ADD COLUMN category VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Индексы под keyset-пагинацию для каждого поддерживаемого порядка сортировки
+CREATE INDEX IF NOT EXISTS user_products_user_id_name_id_idx
+    ON user_products (user_id, product_name, id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_created_at_id_idx
+    ON user_products (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_expires_at_id_idx
+    ON user_products (user_id, (COALESCE(expires_at, 'infinity'::timestamp)), id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_category_idx
+    ON user_products (user_id, category);
\ No newline at end of file
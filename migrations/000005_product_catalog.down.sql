@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS user_products_catalog_product_id_idx;
+
+ALTER TABLE user_products DROP COLUMN catalog_product_id;
+
+DROP TABLE IF EXISTS product_aliases CASCADE;
+DROP TABLE IF EXISTS product_names CASCADE;
+DROP TABLE IF EXISTS products CASCADE;
\ No newline at end of file
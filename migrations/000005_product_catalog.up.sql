@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS products (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    canonical_name VARCHAR(255) NOT NULL UNIQUE,
+    category VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS product_names (
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    locale VARCHAR(16) NOT NULL,
+    display_name VARCHAR(255) NOT NULL,
+    PRIMARY KEY (product_id, locale)
+);
+
+-- Синонимы хранятся в нормализованном виде (нижний регистр, одинарные пробелы)
+CREATE TABLE IF NOT EXISTS product_aliases (
+    alias VARCHAR(255) PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS product_aliases_product_id_idx ON product_aliases (product_id);
+
+ALTER TABLE user_products
+    ADD COLUMN catalog_product_id UUID REFERENCES products (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS user_products_catalog_product_id_idx ON user_products (catalog_product_id);
\ No newline at end of file
@@ -0,0 +1,16 @@
+ALTER TABLE user_preferences
+    DROP COLUMN carbs_g,
+    DROP COLUMN fat_g,
+    DROP COLUMN protein_g,
+    DROP COLUMN calories,
+    ADD COLUMN preference_name VARCHAR(255);
+
+-- Старая схема хранит одно значение, переносим первый тип питания
+INSERT INTO user_preferences (user_id, preference_name)
+SELECT user_id, min(value)
+FROM user_preference_entries
+WHERE kind = 'diet'
+GROUP BY user_id
+ON CONFLICT (user_id) DO UPDATE SET preference_name = EXCLUDED.preference_name;
+
+DROP TABLE IF EXISTS user_preference_entries CASCADE;
\ No newline at end of file
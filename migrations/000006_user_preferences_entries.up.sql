@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS user_preference_entries (
+    user_id INT NOT NULL,
+    kind VARCHAR(32) NOT NULL CHECK (kind IN ('diet', 'allergen', 'disliked_ingredient')),
+    value VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, kind, value)
+);
+
+-- Единственное предпочтение из старой схемы становится типом питания
+INSERT INTO user_preference_entries (user_id, kind, value)
+SELECT user_id, 'diet', lower(trim(preference_name))
+FROM user_preferences
+WHERE trim(COALESCE(preference_name, '')) <> ''
+ON CONFLICT DO NOTHING;
+
+-- В user_preferences остаются цели по калориям и БЖУ, по одной строке на пользователя
+ALTER TABLE user_preferences
+    DROP COLUMN preference_name,
+    ADD COLUMN calories INT NOT NULL DEFAULT 0 CHECK (calories >= 0),
+    ADD COLUMN protein_g NUMERIC(7, 2) NOT NULL DEFAULT 0 CHECK (protein_g >= 0),
+    ADD COLUMN fat_g NUMERIC(7, 2) NOT NULL DEFAULT 0 CHECK (fat_g >= 0),
+    ADD COLUMN carbs_g NUMERIC(7, 2) NOT NULL DEFAULT 0 CHECK (carbs_g >= 0);
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS user_token_revocations_notify ON user_token_revocations;
+DROP TRIGGER IF EXISTS revoked_tokens_notify ON revoked_tokens;
+DROP FUNCTION IF EXISTS notify_token_revocation();
+
+DROP TABLE IF EXISTS user_token_revocations CASCADE;
+DROP TABLE IF EXISTS revoked_tokens CASCADE;
\ No newline at end of file
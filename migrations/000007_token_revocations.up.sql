@@ -0,0 +1,42 @@
+-- Отозванные токены по jti, запись нужна только до истечения срока действия токена
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti VARCHAR(255) PRIMARY KEY,
+    user_id UUID,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS revoked_tokens_expires_at_idx ON revoked_tokens (expires_at);
+
+-- Все токены пользователя, выпущенные раньше revoked_before, недействительны
+CREATE TABLE IF NOT EXISTS user_token_revocations (
+    user_id UUID PRIMARY KEY,
+    revoked_before TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Каждое изменение рассылается репликам через канал token_revocations
+CREATE OR REPLACE FUNCTION notify_token_revocation() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'revoked_tokens' THEN
+        PERFORM pg_notify('token_revocations', json_build_object(
+            'jti', NEW.jti,
+            'expires_at', NEW.expires_at
+        )::text);
+    ELSE
+        PERFORM pg_notify('token_revocations', json_build_object(
+            'user_id', NEW.user_id,
+            'revoked_before', NEW.revoked_before
+        )::text);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER revoked_tokens_notify
+    AFTER INSERT OR UPDATE ON revoked_tokens
+    FOR EACH ROW EXECUTE FUNCTION notify_token_revocation();
+
+CREATE TRIGGER user_token_revocations_notify
+    AFTER INSERT OR UPDATE ON user_token_revocations
+    FOR EACH ROW EXECUTE FUNCTION notify_token_revocation();
\ No newline at end of file
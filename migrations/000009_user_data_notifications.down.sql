@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS user_preference_entries_notify ON user_preference_entries;
+DROP TRIGGER IF EXISTS user_preferences_notify ON user_preferences;
+DROP TRIGGER IF EXISTS user_products_notify ON user_products;
+DROP FUNCTION IF EXISTS notify_user_data_change();
\ No newline at end of file
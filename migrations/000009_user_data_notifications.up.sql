@@ -0,0 +1,34 @@
+-- Изменения продуктов и предпочтений рассылаются репликам через канал user_data_changes,
+-- чтобы они сбросили кэш пользователя. Одинаковые уведомления одной транзакции Postgres
+-- доставляет один раз, поэтому пакетные операции не порождают лишних уведомлений.
+CREATE OR REPLACE FUNCTION notify_user_data_change() RETURNS TRIGGER AS $$
+DECLARE
+    kind TEXT := CASE WHEN TG_TABLE_NAME = 'user_products' THEN 'products' ELSE 'preferences' END;
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM pg_notify('user_data_changes', json_build_object(
+            'user_id', OLD.user_id::text,
+            'kind', kind
+        )::text);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM pg_notify('user_data_changes', json_build_object(
+            'user_id', NEW.user_id::text,
+            'kind', kind
+        )::text);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER user_products_notify
+    AFTER INSERT OR UPDATE OR DELETE ON user_products
+    FOR EACH ROW EXECUTE FUNCTION notify_user_data_change();
+
+CREATE TRIGGER user_preferences_notify
+    AFTER INSERT OR UPDATE OR DELETE ON user_preferences
+    FOR EACH ROW EXECUTE FUNCTION notify_user_data_change();
+
+CREATE TRIGGER user_preference_entries_notify
+    AFTER INSERT OR UPDATE OR DELETE ON user_preference_entries
+    FOR EACH ROW EXECUTE FUNCTION notify_user_data_change();
\ No newline at end of file
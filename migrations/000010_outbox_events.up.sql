@@ -0,0 +1,9 @@
+-- Доменные события об изменении продуктов и предпочтений. Событие записывается в одной транзакции
+-- с изменением, relay публикует события в брокер в порядке id и удаляет опубликованные.
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
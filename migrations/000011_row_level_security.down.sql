@@ -0,0 +1,11 @@
+DROP POLICY IF EXISTS user_preference_entries_owner ON user_preference_entries;
+ALTER TABLE user_preference_entries NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_preference_entries DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS user_preferences_owner ON user_preferences;
+ALTER TABLE user_preferences NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_preferences DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS user_products_owner ON user_products;
+ALTER TABLE user_products NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_products DISABLE ROW LEVEL SECURITY;
\ No newline at end of file
@@ -0,0 +1,24 @@
+-- Строки продуктов и предпочтений видны только пользователю из переменной app.user_id,
+-- которую репозиторий ставит в каждой транзакции. Без переменной запросы не видят ни одной строки.
+-- FORCE распространяет политики и на владельца таблиц, от имени которого обычно работает сервис;
+-- суперпользователи и роли с BYPASSRLS политики не проверяют.
+ALTER TABLE user_products ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_products FORCE ROW LEVEL SECURITY;
+CREATE POLICY user_products_owner ON user_products
+    USING (user_id::text = current_setting('app.user_id', true))
+    WITH CHECK (user_id::text = current_setting('app.user_id', true));
+
+ALTER TABLE user_preferences ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_preferences FORCE ROW LEVEL SECURITY;
+CREATE POLICY user_preferences_owner ON user_preferences
+    USING (user_id::text = current_setting('app.user_id', true))
+    WITH CHECK (user_id::text = current_setting('app.user_id', true));
+
+ALTER TABLE user_preference_entries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_preference_entries FORCE ROW LEVEL SECURITY;
+CREATE POLICY user_preference_entries_owner ON user_preference_entries
+    USING (user_id::text = current_setting('app.user_id', true))
+    WITH CHECK (user_id::text = current_setting('app.user_id', true));
+
+-- Роли сервиса и их права создает init-scripts/roles.sql от имени суперпользователя:
+-- миграции применяет владелец схемы, которому не положено создавать роли с BYPASSRLS.
\ No newline at end of file
@@ -0,0 +1,49 @@
+-- Обратно к целочисленным идентификаторам переводятся только пользователи из user_id_mappings,
+-- данные остальных пользователей удаляются. Не перенесенные строки legacy_* возвращаются в таблицы.
+DROP POLICY users_self ON users;
+DROP POLICY user_preference_entries_owner ON user_preference_entries;
+DROP POLICY user_preferences_owner ON user_preferences;
+DROP POLICY user_products_owner ON user_products;
+ALTER TABLE user_products NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_preferences NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_preference_entries NO FORCE ROW LEVEL SECURITY;
+
+ALTER TABLE user_preference_entries DROP CONSTRAINT user_preference_entries_user_id_fkey;
+ALTER TABLE user_preferences DROP CONSTRAINT user_preferences_user_id_fkey;
+ALTER TABLE user_products DROP CONSTRAINT user_products_user_id_fkey;
+DROP TRIGGER user_preference_entries_ensure_user ON user_preference_entries;
+DROP TRIGGER user_preferences_ensure_user ON user_preferences;
+DROP TRIGGER user_products_ensure_user ON user_products;
+DROP FUNCTION ensure_user();
+DROP TABLE users;
+
+DELETE FROM user_products WHERE user_id NOT IN (SELECT user_id FROM user_id_mappings);
+DELETE FROM user_preferences WHERE user_id NOT IN (SELECT user_id FROM user_id_mappings);
+DELETE FROM user_preference_entries WHERE user_id NOT IN (SELECT user_id FROM user_id_mappings);
+
+-- В USING нельзя писать подзапрос, поэтому соответствие ищет временная функция
+CREATE FUNCTION pg_temp.legacy_user_id(id UUID) RETURNS INT AS $$
+    SELECT legacy_id FROM user_id_mappings WHERE user_id = id
+$$ LANGUAGE sql STABLE;
+ALTER TABLE user_products ALTER COLUMN user_id TYPE INT USING pg_temp.legacy_user_id(user_id);
+ALTER TABLE user_preferences ALTER COLUMN user_id TYPE INT USING pg_temp.legacy_user_id(user_id);
+ALTER TABLE user_preference_entries ALTER COLUMN user_id TYPE INT USING pg_temp.legacy_user_id(user_id);
+
+INSERT INTO user_products SELECT * FROM legacy_user_products ON CONFLICT DO NOTHING;
+INSERT INTO user_preferences SELECT * FROM legacy_user_preferences ON CONFLICT DO NOTHING;
+INSERT INTO user_preference_entries SELECT * FROM legacy_user_preference_entries ON CONFLICT DO NOTHING;
+DROP TABLE legacy_user_products, legacy_user_preferences, legacy_user_preference_entries;
+DROP TABLE user_id_mappings;
+
+CREATE POLICY user_products_owner ON user_products
+    USING (user_id::text = current_setting('app.user_id', true))
+    WITH CHECK (user_id::text = current_setting('app.user_id', true));
+CREATE POLICY user_preferences_owner ON user_preferences
+    USING (user_id::text = current_setting('app.user_id', true))
+    WITH CHECK (user_id::text = current_setting('app.user_id', true));
+CREATE POLICY user_preference_entries_owner ON user_preference_entries
+    USING (user_id::text = current_setting('app.user_id', true))
+    WITH CHECK (user_id::text = current_setting('app.user_id', true));
+ALTER TABLE user_products FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_preferences FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_preference_entries FORCE ROW LEVEL SECURITY;
\ No newline at end of file
@@ -0,0 +1,83 @@
+-- Идентификатор пользователя - subject токена доступа (UUID). Строки со старыми целочисленными
+-- идентификаторами переносятся в таблицы legacy_*, откуда их забирает команда `migrate backfill`
+-- по таблице соответствия user_id_mappings.
+
+-- Политики ссылаются на user_id и не дают сменить его тип, поэтому создаются заново в конце.
+-- Без FORCE владелец таблиц видит все строки, даже если миграции применяет не суперпользователь.
+DROP POLICY user_products_owner ON user_products;
+DROP POLICY user_preferences_owner ON user_preferences;
+DROP POLICY user_preference_entries_owner ON user_preference_entries;
+ALTER TABLE user_products NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_preferences NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_preference_entries NO FORCE ROW LEVEL SECURITY;
+
+CREATE TABLE legacy_user_products AS SELECT * FROM user_products;
+CREATE TABLE legacy_user_preferences AS SELECT * FROM user_preferences;
+CREATE TABLE legacy_user_preference_entries AS SELECT * FROM user_preference_entries;
+TRUNCATE user_products, user_preferences, user_preference_entries;
+
+-- Соответствие старых идентификаторов пользователей новым, заполняется командой `migrate backfill`
+CREATE TABLE IF NOT EXISTS user_id_mappings (
+    legacy_id INT PRIMARY KEY,
+    user_id UUID NOT NULL UNIQUE
+);
+
+-- Таблицы пусты, поэтому смена типа не преобразует ни одной строки;
+-- индексы по user_id перестраиваются вместе с колонкой
+ALTER TABLE user_products ALTER COLUMN user_id TYPE UUID USING user_id::text::uuid;
+ALTER TABLE user_preferences ALTER COLUMN user_id TYPE UUID USING user_id::text::uuid;
+ALTER TABLE user_preference_entries ALTER COLUMN user_id TYPE UUID USING user_id::text::uuid;
+
+-- Пользователи, у которых есть данные. Запись заводится триггером при первой записи данных
+-- пользователя, удаление пользователя удаляет все его данные.
+CREATE TABLE IF NOT EXISTS users (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE OR REPLACE FUNCTION ensure_user() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO users (id) VALUES (NEW.user_id) ON CONFLICT DO NOTHING;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER user_products_ensure_user
+    BEFORE INSERT ON user_products
+    FOR EACH ROW EXECUTE FUNCTION ensure_user();
+
+CREATE TRIGGER user_preferences_ensure_user
+    BEFORE INSERT ON user_preferences
+    FOR EACH ROW EXECUTE FUNCTION ensure_user();
+
+CREATE TRIGGER user_preference_entries_ensure_user
+    BEFORE INSERT ON user_preference_entries
+    FOR EACH ROW EXECUTE FUNCTION ensure_user();
+
+-- Колонки user_id ссылающихся таблиц уже покрыты индексами, у которых user_id - первая колонка
+ALTER TABLE user_products
+    ADD CONSTRAINT user_products_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE user_preferences
+    ADD CONSTRAINT user_preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+ALTER TABLE user_preference_entries
+    ADD CONSTRAINT user_preference_entries_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+-- После транзакции с set_config переменная app.user_id пустая, а не NULL, поэтому она сравнивается через NULLIF
+CREATE POLICY user_products_owner ON user_products
+    USING (user_id = NULLIF(current_setting('app.user_id', true), '')::uuid)
+    WITH CHECK (user_id = NULLIF(current_setting('app.user_id', true), '')::uuid);
+CREATE POLICY user_preferences_owner ON user_preferences
+    USING (user_id = NULLIF(current_setting('app.user_id', true), '')::uuid)
+    WITH CHECK (user_id = NULLIF(current_setting('app.user_id', true), '')::uuid);
+CREATE POLICY user_preference_entries_owner ON user_preference_entries
+    USING (user_id = NULLIF(current_setting('app.user_id', true), '')::uuid)
+    WITH CHECK (user_id = NULLIF(current_setting('app.user_id', true), '')::uuid);
+ALTER TABLE user_products FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_preferences FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_preference_entries FORCE ROW LEVEL SECURITY;
+
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users FORCE ROW LEVEL SECURITY;
+CREATE POLICY users_self ON users
+    USING (id = NULLIF(current_setting('app.user_id', true), '')::uuid)
+    WITH CHECK (id = NULLIF(current_setting('app.user_id', true), '')::uuid);
\ No newline at end of file
@@ -0,0 +1,25 @@
+DROP INDEX IF EXISTS user_products_user_id_product_name_expires_at_idx;
+
+-- Партии одного продукта объединяются в самую раннюю с суммарным количеством.
+-- Без FORCE владелец таблицы видит строки всех пользователей.
+ALTER TABLE user_products NO FORCE ROW LEVEL SECURITY;
+
+WITH batches AS (
+    SELECT id,
+        sum(quantity) OVER (PARTITION BY user_id, product_name) AS total,
+        row_number() OVER (PARTITION BY user_id, product_name ORDER BY created_at, id) AS n
+    FROM user_products
+)
+UPDATE user_products p SET quantity = b.total FROM batches b WHERE p.id = b.id AND b.n = 1;
+
+DELETE FROM user_products p
+USING (
+    SELECT id, row_number() OVER (PARTITION BY user_id, product_name ORDER BY created_at, id) AS n
+    FROM user_products
+) b
+WHERE p.id = b.id AND b.n > 1;
+
+ALTER TABLE user_products FORCE ROW LEVEL SECURITY;
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_products_user_id_product_name_idx
+    ON user_products (user_id, product_name);
\ No newline at end of file
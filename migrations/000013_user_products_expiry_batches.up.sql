@@ -0,0 +1,6 @@
+-- Один продукт может храниться несколькими партиями с разным сроком годности:
+-- уникально название вместе со сроком, продукты без срока считаются одной партией
+DROP INDEX IF EXISTS user_products_user_id_product_name_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_products_user_id_product_name_expires_at_idx
+    ON user_products (user_id, product_name, (COALESCE(expires_at, 'infinity'::timestamp)));
\ No newline at end of file
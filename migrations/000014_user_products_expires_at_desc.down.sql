@@ -0,0 +1 @@
+DROP INDEX IF EXISTS user_products_user_id_expires_at_desc_id_idx;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- Индекс под keyset-пагинацию по убыванию срока годности: продукты без срока идут последними
+CREATE INDEX IF NOT EXISTS user_products_user_id_expires_at_desc_id_idx
+    ON user_products (user_id, (COALESCE(expires_at, '-infinity'::timestamp)), id);
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS outbox_events_claimed_until_idx;
+
+ALTER TABLE outbox_events
+    DROP COLUMN IF EXISTS claimed_until,
+    DROP COLUMN IF EXISTS attempts;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- Захват событий relay: события захватываются на время публикации, а публикуются вне транзакции.
+-- claimed_until - до какого момента события публикует захватившая их реплика, attempts - сколько раз
+-- событие захватывали; после сбоя реплики захват истекает, и события публикуются повторно.
+ALTER TABLE outbox_events
+    ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS outbox_events_claimed_until_idx
+    ON outbox_events (claimed_until) WHERE claimed_until IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS user_token_revocations;
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS user_preference_entries;
+DROP TABLE IF EXISTS user_preferences;
+DROP TABLE IF EXISTS user_products;
+DROP TABLE IF EXISTS product_aliases;
+DROP TABLE IF EXISTS product_names;
+DROP TABLE IF EXISTS products;
\ No newline at end of file
@@ -0,0 +1,82 @@
+-- Схема SQLite соответствует итоговой схеме Postgres из migrations/*.sql.
+-- Идентификаторы (UUID) и время создаются приложением, время хранится в UTC.
+CREATE TABLE IF NOT EXISTS products (
+    id TEXT PRIMARY KEY,
+    canonical_name TEXT NOT NULL UNIQUE,
+    category TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS product_names (
+    product_id TEXT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    locale TEXT NOT NULL,
+    display_name TEXT NOT NULL,
+    PRIMARY KEY (product_id, locale)
+);
+
+-- Синонимы хранятся в нормализованном виде (нижний регистр, одинарные пробелы)
+CREATE TABLE IF NOT EXISTS product_aliases (
+    alias TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS product_aliases_product_id_idx ON product_aliases (product_id);
+
+CREATE TABLE IF NOT EXISTS user_products (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    product_name TEXT NOT NULL,
+    catalog_product_id TEXT REFERENCES products (id) ON DELETE SET NULL,
+    category TEXT NOT NULL DEFAULT '',
+    quantity REAL NOT NULL DEFAULT 1 CHECK (quantity >= 0),
+    unit TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE (user_id, product_name)
+);
+
+CREATE INDEX IF NOT EXISTS user_products_user_id_name_id_idx
+    ON user_products (user_id, product_name, id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_created_at_id_idx
+    ON user_products (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_expires_at_id_idx
+    ON user_products (user_id, expires_at, id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_category_idx
+    ON user_products (user_id, category);
+CREATE INDEX IF NOT EXISTS user_products_catalog_product_id_idx
+    ON user_products (catalog_product_id);
+
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id TEXT PRIMARY KEY,
+    calories INTEGER NOT NULL DEFAULT 0 CHECK (calories >= 0),
+    protein_g REAL NOT NULL DEFAULT 0 CHECK (protein_g >= 0),
+    fat_g REAL NOT NULL DEFAULT 0 CHECK (fat_g >= 0),
+    carbs_g REAL NOT NULL DEFAULT 0 CHECK (carbs_g >= 0),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS user_preference_entries (
+    user_id TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('diet', 'allergen', 'disliked_ingredient')),
+    value TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, kind, value)
+);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti TEXT PRIMARY KEY,
+    user_id TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS revoked_tokens_expires_at_idx ON revoked_tokens (expires_at);
+
+CREATE TABLE IF NOT EXISTS user_token_revocations (
+    user_id TEXT PRIMARY KEY,
+    revoked_before TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- Доменные события об изменении продуктов и предпочтений, см. migrations/000010_outbox_events.up.sql
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
\ No newline at end of file
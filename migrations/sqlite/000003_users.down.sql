@@ -0,0 +1,63 @@
+-- Таблицы данных пересоздаются без внешних ключей, иначе удаление users удалило бы и данные
+DROP TRIGGER IF EXISTS user_preference_entries_ensure_user;
+DROP TRIGGER IF EXISTS user_preferences_ensure_user;
+DROP TRIGGER IF EXISTS user_products_ensure_user;
+
+CREATE TABLE user_products_old (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    product_name TEXT NOT NULL,
+    catalog_product_id TEXT REFERENCES products (id) ON DELETE SET NULL,
+    category TEXT NOT NULL DEFAULT '',
+    quantity REAL NOT NULL DEFAULT 1 CHECK (quantity >= 0),
+    unit TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE (user_id, product_name)
+);
+INSERT INTO user_products_old
+    (id, user_id, product_name, catalog_product_id, category, quantity, unit, expires_at, created_at, updated_at)
+SELECT id, user_id, product_name, catalog_product_id, category, quantity, unit, expires_at, created_at, updated_at
+FROM user_products;
+DROP TABLE user_products;
+ALTER TABLE user_products_old RENAME TO user_products;
+
+CREATE INDEX IF NOT EXISTS user_products_user_id_name_id_idx
+    ON user_products (user_id, product_name, id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_created_at_id_idx
+    ON user_products (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_expires_at_id_idx
+    ON user_products (user_id, expires_at, id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_category_idx
+    ON user_products (user_id, category);
+CREATE INDEX IF NOT EXISTS user_products_catalog_product_id_idx
+    ON user_products (catalog_product_id);
+
+CREATE TABLE user_preferences_old (
+    user_id TEXT PRIMARY KEY,
+    calories INTEGER NOT NULL DEFAULT 0 CHECK (calories >= 0),
+    protein_g REAL NOT NULL DEFAULT 0 CHECK (protein_g >= 0),
+    fat_g REAL NOT NULL DEFAULT 0 CHECK (fat_g >= 0),
+    carbs_g REAL NOT NULL DEFAULT 0 CHECK (carbs_g >= 0),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+INSERT INTO user_preferences_old (user_id, calories, protein_g, fat_g, carbs_g, created_at, updated_at)
+SELECT user_id, calories, protein_g, fat_g, carbs_g, created_at, updated_at FROM user_preferences;
+DROP TABLE user_preferences;
+ALTER TABLE user_preferences_old RENAME TO user_preferences;
+
+CREATE TABLE user_preference_entries_old (
+    user_id TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('diet', 'allergen', 'disliked_ingredient')),
+    value TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, kind, value)
+);
+INSERT INTO user_preference_entries_old (user_id, kind, value, created_at)
+SELECT user_id, kind, value, created_at FROM user_preference_entries;
+DROP TABLE user_preference_entries;
+ALTER TABLE user_preference_entries_old RENAME TO user_preference_entries;
+
+DROP TABLE IF EXISTS users;
\ No newline at end of file
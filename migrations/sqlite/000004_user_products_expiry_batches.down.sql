@@ -0,0 +1,54 @@
+-- Партии одного продукта объединяются в самую раннюю с суммарным количеством
+UPDATE user_products SET quantity = (
+    SELECT sum(b.quantity) FROM user_products b
+    WHERE b.user_id = user_products.user_id AND b.product_name = user_products.product_name
+)
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, row_number() OVER (PARTITION BY user_id, product_name ORDER BY created_at, id) AS n
+        FROM user_products
+    ) WHERE n = 1
+);
+DELETE FROM user_products WHERE id IN (
+    SELECT id FROM (
+        SELECT id, row_number() OVER (PARTITION BY user_id, product_name ORDER BY created_at, id) AS n
+        FROM user_products
+    ) WHERE n > 1
+);
+
+CREATE TABLE user_products_old (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    product_name TEXT NOT NULL,
+    catalog_product_id TEXT REFERENCES products (id) ON DELETE SET NULL,
+    category TEXT NOT NULL DEFAULT '',
+    quantity REAL NOT NULL DEFAULT 1 CHECK (quantity >= 0),
+    unit TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE (user_id, product_name)
+);
+INSERT INTO user_products_old
+    (id, user_id, product_name, catalog_product_id, category, quantity, unit, expires_at, created_at, updated_at)
+SELECT id, user_id, product_name, catalog_product_id, category, quantity, unit, expires_at, created_at, updated_at
+FROM user_products;
+DROP TABLE user_products;
+ALTER TABLE user_products_old RENAME TO user_products;
+
+CREATE INDEX IF NOT EXISTS user_products_user_id_name_id_idx
+    ON user_products (user_id, product_name, id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_created_at_id_idx
+    ON user_products (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_expires_at_id_idx
+    ON user_products (user_id, expires_at, id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_category_idx
+    ON user_products (user_id, category);
+CREATE INDEX IF NOT EXISTS user_products_catalog_product_id_idx
+    ON user_products (catalog_product_id);
+
+CREATE TRIGGER user_products_ensure_user
+    BEFORE INSERT ON user_products
+BEGIN
+    INSERT OR IGNORE INTO users (id) VALUES (NEW.user_id);
+END;
\ No newline at end of file
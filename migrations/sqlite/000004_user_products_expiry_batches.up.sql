@@ -0,0 +1,39 @@
+-- Партии одного продукта с разным сроком годности, см. migrations/000013_user_products_expiry_batches.up.sql.
+-- Ограничение UNIQUE таблицы нельзя удалить, поэтому таблица пересоздается.
+CREATE TABLE user_products_new (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    product_name TEXT NOT NULL,
+    catalog_product_id TEXT REFERENCES products (id) ON DELETE SET NULL,
+    category TEXT NOT NULL DEFAULT '',
+    quantity REAL NOT NULL DEFAULT 1 CHECK (quantity >= 0),
+    unit TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+INSERT INTO user_products_new
+    (id, user_id, product_name, catalog_product_id, category, quantity, unit, expires_at, created_at, updated_at)
+SELECT id, user_id, product_name, catalog_product_id, category, quantity, unit, expires_at, created_at, updated_at
+FROM user_products;
+DROP TABLE user_products;
+ALTER TABLE user_products_new RENAME TO user_products;
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_products_user_id_product_name_expires_at_idx
+    ON user_products (user_id, product_name, IFNULL(expires_at, 'infinity'));
+CREATE INDEX IF NOT EXISTS user_products_user_id_name_id_idx
+    ON user_products (user_id, product_name, id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_created_at_id_idx
+    ON user_products (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_expires_at_id_idx
+    ON user_products (user_id, expires_at, id);
+CREATE INDEX IF NOT EXISTS user_products_user_id_category_idx
+    ON user_products (user_id, category);
+CREATE INDEX IF NOT EXISTS user_products_catalog_product_id_idx
+    ON user_products (catalog_product_id);
+
+CREATE TRIGGER user_products_ensure_user
+    BEFORE INSERT ON user_products
+BEGIN
+    INSERT OR IGNORE INTO users (id) VALUES (NEW.user_id);
+END;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- Индекс под пагинацию по убыванию срока годности, см. migrations/000014_user_products_expires_at_desc.up.sql
+CREATE INDEX IF NOT EXISTS user_products_user_id_expires_at_desc_id_idx
+    ON user_products (user_id, COALESCE(expires_at, '0001-01-01 00:00:00+00:00'), id);
\ No newline at end of file